@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/k8s-lab/get-cluster-info/audit"
+	"github.com/k8s-lab/get-cluster-info/i18n"
+	"github.com/k8s-lab/get-cluster-info/render"
+	"github.com/spf13/cobra"
+)
+
+// =============================================================================
+// Audit subcommand
+// =============================================================================
+//
+// Reads the deployed security groups from the Terraform state (tf.Show) and
+// flags the ports open to the world. Exits with an error on high-severity
+// findings (or on --fail-on) so that CI can be gated on it.
+
+var auditFailOn string
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Audit the network exposure of the deployed cluster",
+	Long: `Audit the network exposure of the deployed cluster.
+
+Reads the security groups from the Terraform state and reports the inbound
+rules open to the world:
+  high    admin port (SSH, Kubernetes API, etcd, kubelet) open to the world
+  medium  admin port open to a wide public range, NodePorts or other ports open to the world
+  low     ICMP open to the world
+
+The command fails when a finding reaches --fail-on (default: high).
+
+Examples:
+  # Human readable report
+  get-cluster-info audit
+
+  # CI gate on medium findings, with a JSON report
+  get-cluster-info audit --fail-on medium --json`,
+	Args: cobra.NoArgs,
+	RunE: runAudit,
+}
+
+func init() {
+	auditCmd.Flags().StringVar(&auditFailOn, "fail-on", string(audit.SeverityHigh),
+		"Fail on findings of this severity or more serious (high, medium, low)")
+
+	rootCmd.AddCommand(auditCmd)
+}
+
+func runAudit(_ *cobra.Command, _ []string) error {
+	threshold, err := audit.ParseSeverity(auditFailOn)
+	if err != nil {
+		return withClass(errUsage, i18n.Errorf("invalid --fail-on: %w", err))
+	}
+
+	ctx := context.Background()
+
+	src, err := setupEnvironment(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = src.Close() }()
+
+	logInfo("Reading the Terraform state...")
+
+	state, err := src.Client().Show(ctx)
+	if err != nil {
+		return withClass(errBackend, i18n.Errorf("failed to read terraform state: %w", err))
+	}
+
+	report := audit.Run(state)
+
+	switch {
+	case config.JSONOutput:
+		data, _ := json.MarshalIndent(report, "", "  ")
+		fmt.Fprintln(stdout, string(data))
+	case report.RulesChecked == 0:
+		logWarning("No security group rules in the state - the cluster may not be deployed")
+	default:
+		if err := render.AuditReport(stdout, report); err != nil {
+			return err
+		}
+	}
+
+	if n := report.CountAtLeast(threshold); n > 0 {
+		return i18n.Errorf("%w: %d finding(s) of severity %s or more", audit.ErrFindings, n, threshold)
+	}
+
+	return nil
+}
@@ -0,0 +1,372 @@
+// Package audit inspects the network exposure of a deployed K8s-Lab cluster from
+// its Terraform state: security group policies and inbound rules.
+//
+// Findings have a severity; high-severity findings (an admin port open to the
+// world) make the report fail, so that CI can be gated on it.
+package audit
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+// =============================================================================
+// Types
+// =============================================================================
+
+// Severity ranks a finding.
+type Severity string
+
+// Severities, from the most to the least serious.
+const (
+	SeverityHigh   Severity = "high"
+	SeverityMedium Severity = "medium"
+	SeverityLow    Severity = "low"
+)
+
+// Severities lists the severities from the most to the least serious.
+var Severities = []Severity{SeverityHigh, SeverityMedium, SeverityLow}
+
+// ParseSeverity parses a severity name (high, medium, low).
+func ParseSeverity(s string) (Severity, error) {
+	for _, sev := range Severities {
+		if strings.EqualFold(s, string(sev)) {
+			return sev, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w %q (high, medium or low)", ErrUnknownSeverity, s)
+}
+
+// AtLeast reports whether s is as serious as other, or more.
+func (s Severity) AtLeast(other Severity) bool {
+	return s.rank() <= other.rank()
+}
+
+func (s Severity) rank() int {
+	for i, sev := range Severities {
+		if s == sev {
+			return i
+		}
+	}
+
+	return len(Severities)
+}
+
+// Finding is an exposure found in the state.
+type Finding struct {
+	Severity Severity `json:"severity"`
+	Resource string   `json:"resource"`
+	Rule     string   `json:"rule"`
+	Message  string   `json:"message"`
+	Hint     string   `json:"hint,omitempty"`
+}
+
+var (
+	// ErrFindings is returned when findings reach the failure threshold.
+	ErrFindings = errors.New("audit found exposed services")
+	// ErrUnknownSeverity is returned by ParseSeverity.
+	ErrUnknownSeverity = errors.New("unknown severity")
+)
+
+// Report is the outcome of an audit, findings sorted by severity.
+type Report struct {
+	RulesChecked int       `json:"rules_checked"` //nolint:tagliatelle
+	Findings     []Finding `json:"findings"`
+}
+
+// Count returns the number of findings with the given severity.
+func (r Report) Count(sev Severity) int {
+	n := 0
+
+	for _, f := range r.Findings {
+		if f.Severity == sev {
+			n++
+		}
+	}
+
+	return n
+}
+
+// CountAtLeast returns the number of findings as serious as threshold, or more.
+func (r Report) CountAtLeast(threshold Severity) int {
+	n := 0
+
+	for _, f := range r.Findings {
+		if f.Severity.AtLeast(threshold) {
+			n++
+		}
+	}
+
+	return n
+}
+
+// =============================================================================
+// Audit
+// =============================================================================
+
+// Resource types inspected by Run.
+const (
+	securityGroupType      = "scaleway_instance_security_group"
+	securityGroupRulesType = "scaleway_instance_security_group_rules"
+)
+
+// Port ranges of the lab.
+const (
+	maxPort      = 65535
+	nodePortLow  = 30000
+	nodePortHigh = 32767
+)
+
+// adminPorts are the ports that must never be reachable from the world.
+var adminPorts = []struct {
+	port int
+	name string
+}{
+	{22, "SSH"},
+	{6443, "Kubernetes API"},
+	{2379, "etcd"},
+	{2380, "etcd peers"},
+	{10250, "kubelet API"},
+}
+
+// Hints of the findings (variables of network.tf).
+const (
+	hintAllowedCIDR = "set allowed_ssh_cidr to your IP/32 in terraform.tfvars (get-cluster-info init-config --force)"
+	hintNodePorts   = "restrict the NodePort rule of network.tf, or expose services through an ingress"
+	hintNarrowRule  = "restrict ip_range to the addresses that need it"
+	hintDropPolicy  = "set inbound_default_policy = \"drop\" in network.tf"
+)
+
+// Run audits the security groups of state. A nil or empty state has no findings.
+func Run(state *tfjson.State) Report {
+	report := Report{Findings: []Finding{}}
+
+	if state == nil || state.Values == nil {
+		return report
+	}
+
+	forEachResource(state.Values.RootModule, func(r *tfjson.StateResource) {
+		if r.Mode != tfjson.ManagedResourceMode {
+			return
+		}
+
+		switch r.Type {
+		case securityGroupType:
+			report.Findings = append(report.Findings, auditSecurityGroup(r)...)
+		case securityGroupRulesType:
+			rules := inboundRules(r.AttributeValues)
+			report.RulesChecked += len(rules)
+
+			for _, rule := range rules {
+				report.Findings = append(report.Findings, rule.audit(r.Address)...)
+			}
+		}
+	})
+
+	// Stable order: by severity, then in state order.
+	sorted := make([]Finding, 0, len(report.Findings))
+	for _, sev := range Severities {
+		for _, f := range report.Findings {
+			if f.Severity == sev {
+				sorted = append(sorted, f)
+			}
+		}
+	}
+
+	report.Findings = sorted
+
+	return report
+}
+
+// forEachResource calls fn for the resources of m and its child modules.
+func forEachResource(m *tfjson.StateModule, fn func(*tfjson.StateResource)) {
+	if m == nil {
+		return
+	}
+
+	for _, r := range m.Resources {
+		fn(r)
+	}
+
+	for _, child := range m.ChildModules {
+		forEachResource(child, fn)
+	}
+}
+
+func auditSecurityGroup(r *tfjson.StateResource) []Finding {
+	if policy, _ := r.AttributeValues["inbound_default_policy"].(string); policy != "accept" {
+		return nil
+	}
+
+	return []Finding{{
+		Severity: SeverityHigh,
+		Resource: r.Address,
+		Rule:     "inbound_default_policy = accept",
+		Message:  "every port not explicitly dropped is open",
+		Hint:     hintDropPolicy,
+	}}
+}
+
+// -----------------------------------------------------------------------------
+// Inbound rules
+// -----------------------------------------------------------------------------
+
+// inboundRule is an inbound_rule block of scaleway_instance_security_group_rules.
+type inboundRule struct {
+	Action   string
+	Protocol string
+	FromPort int
+	ToPort   int
+	Source   string // ip_range, or ip
+}
+
+// inboundRules decodes the inbound_rule blocks of a resource (JSON numbers are float64).
+func inboundRules(values map[string]any) []inboundRule {
+	blocks, _ := values["inbound_rule"].([]any)
+	rules := make([]inboundRule, 0, len(blocks))
+
+	for _, b := range blocks {
+		block, ok := b.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		rule := inboundRule{
+			Action:   stringAttr(block, "action"),
+			Protocol: strings.ToUpper(stringAttr(block, "protocol")),
+			Source:   stringAttr(block, "ip_range"),
+		}
+
+		if rule.Source == "" {
+			rule.Source = stringAttr(block, "ip")
+		}
+
+		port, _ := block["port"].(float64)
+		rule.FromPort, rule.ToPort = int(port), int(port)
+
+		if port == 0 {
+			rule.FromPort, rule.ToPort = parsePortRange(stringAttr(block, "port_range"))
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules
+}
+
+func stringAttr(block map[string]any, name string) string {
+	s, _ := block[name].(string)
+
+	return s
+}
+
+// parsePortRange parses "30000-32767"; an empty or invalid range means every port.
+func parsePortRange(s string) (int, int) {
+	from, to, found := strings.Cut(s, "-")
+
+	low, errLow := strconv.Atoi(strings.TrimSpace(from))
+	if errLow != nil {
+		return 1, maxPort
+	}
+
+	if !found {
+		return low, low
+	}
+
+	high, errHigh := strconv.Atoi(strings.TrimSpace(to))
+	if errHigh != nil {
+		return 1, maxPort
+	}
+
+	return low, high
+}
+
+// String describes the rule, e.g. "TCP 22 from 0.0.0.0/0".
+func (r inboundRule) String() string {
+	ports := ""
+
+	switch {
+	case r.Protocol == "ICMP":
+	case r.Protocol == "ANY" || r.FromPort == 1 && r.ToPort == maxPort:
+		ports = " (all ports)"
+	case r.FromPort == r.ToPort:
+		ports = " " + strconv.Itoa(r.FromPort)
+	default:
+		ports = fmt.Sprintf(" %d-%d", r.FromPort, r.ToPort)
+	}
+
+	return fmt.Sprintf("%s%s from %s", r.Protocol, ports, r.Source)
+}
+
+func (r inboundRule) covers(port int) bool {
+	return r.Protocol != "ICMP" && (r.Protocol == "ANY" || r.FromPort <= port && port <= r.ToPort)
+}
+
+// audit returns the findings of an accepting rule, depending on its source range:
+// the world (/0), a wide public range (up to /16), or a narrow or private range (ignored).
+func (r inboundRule) audit(resource string) []Finding {
+	if !strings.EqualFold(r.Action, "accept") {
+		return nil
+	}
+
+	source, err := netip.ParsePrefix(r.Source)
+	if err != nil {
+		addr, addrErr := netip.ParseAddr(r.Source)
+		if addrErr != nil {
+			return nil
+		}
+
+		source = netip.PrefixFrom(addr, addr.BitLen())
+	}
+
+	world := source.Bits() == 0
+
+	const wideBits = 16
+	if !world && (source.Bits() > wideBits || source.Addr().IsPrivate()) {
+		return nil
+	}
+
+	finding := func(sev Severity, message, hint string) Finding {
+		return Finding{Severity: sev, Resource: resource, Rule: r.String(), Message: message, Hint: hint}
+	}
+
+	exposure := "open to the world"
+	if !world {
+		exposure = fmt.Sprintf("open to a /%d range", source.Bits())
+	}
+
+	var findings []Finding
+
+	for _, admin := range adminPorts {
+		if !r.covers(admin.port) {
+			continue
+		}
+
+		sev := SeverityHigh
+		if !world {
+			sev = SeverityMedium
+		}
+
+		findings = append(findings, finding(sev, fmt.Sprintf("%s (%d) %s", admin.name, admin.port, exposure),
+			hintAllowedCIDR))
+	}
+
+	if len(findings) > 0 || !world {
+		return findings
+	}
+
+	switch {
+	case r.Protocol == "ICMP":
+		return []Finding{finding(SeverityLow, "ICMP (ping) open to the world", hintNarrowRule)}
+	case r.FromPort == nodePortLow && r.ToPort == nodePortHigh:
+		return []Finding{finding(SeverityMedium, "NodePort services open to the world", hintNodePorts)}
+	default:
+		return []Finding{finding(SeverityMedium, "ports open to the world", hintNarrowRule)}
+	}
+}
@@ -0,0 +1,178 @@
+package audit
+
+import (
+	"errors"
+	"testing"
+
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/k8s-lab/get-cluster-info/clusterinfo/clusterinfotest"
+)
+
+// =============================================================================
+// Run tests
+// =============================================================================
+
+// rule returns an inbound_rule block as decoded from the state JSON.
+func rule(protocol string, port float64, portRange, ipRange string) map[string]any {
+	return map[string]any{
+		"action": "accept", "protocol": protocol, "port": port,
+		"port_range": portRange, "ip": "", "ip_range": ipRange,
+	}
+}
+
+// stateWith returns a state with a security group and its rules, in a child module.
+func stateWith(inboundPolicy string, rules ...map[string]any) *tfjson.State {
+	blocks := make([]any, 0, len(rules))
+	for _, r := range rules {
+		blocks = append(blocks, r)
+	}
+
+	return &tfjson.State{Values: &tfjson.StateValues{RootModule: &tfjson.StateModule{
+		ChildModules: []*tfjson.StateModule{{
+			Address: "module.network",
+			Resources: []*tfjson.StateResource{
+				{
+					Address:         "module.network.scaleway_instance_security_group.sg",
+					Mode:            tfjson.ManagedResourceMode,
+					Type:            securityGroupType,
+					AttributeValues: map[string]any{"inbound_default_policy": inboundPolicy},
+				},
+				{
+					Address:         "module.network.scaleway_instance_security_group_rules.rules",
+					Mode:            tfjson.ManagedResourceMode,
+					Type:            securityGroupRulesType,
+					AttributeValues: map[string]any{"inbound_rule": blocks},
+				},
+			},
+		}},
+	}}}
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		state        *tfjson.State
+		wantSeverity []Severity
+		wantMessages []string
+	}{
+		{name: "no state - no findings", state: nil},
+		{
+			name:  "restricted admin ports and private traffic - no findings",
+			state: stateWith("drop", rule("TCP", 22, "", "203.0.113.7/32"), rule("ANY", 0, "", "10.0.0.0/24")),
+		},
+		{
+			name:         "SSH open to the world - high",
+			state:        stateWith("drop", rule("TCP", 22, "", "0.0.0.0/0")),
+			wantSeverity: []Severity{SeverityHigh},
+			wantMessages: []string{"SSH (22) open to the world"},
+		},
+		{
+			name:         "API open to a /12 - medium",
+			state:        stateWith("drop", rule("TCP", 6443, "", "64.0.0.0/12")),
+			wantSeverity: []Severity{SeverityMedium},
+			wantMessages: []string{"Kubernetes API (6443) open to a /12 range"},
+		},
+		{
+			name:         "range covering the kubelet - high",
+			state:        stateWith("drop", rule("TCP", 0, "10000-11000", "::/0")),
+			wantSeverity: []Severity{SeverityHigh},
+			wantMessages: []string{"kubelet API (10250) open to the world"},
+		},
+		{
+			name:         "every protocol open - one finding per admin port",
+			state:        stateWith("drop", rule("ANY", 0, "", "0.0.0.0/0")),
+			wantSeverity: []Severity{SeverityHigh, SeverityHigh, SeverityHigh, SeverityHigh, SeverityHigh},
+		},
+		{
+			name:         "accept policy - high",
+			state:        stateWith("accept"),
+			wantSeverity: []Severity{SeverityHigh},
+			wantMessages: []string{"every port not explicitly dropped is open"},
+		},
+		{
+			name: "dropped rule - ignored",
+			state: stateWith("drop", map[string]any{
+				"action": "drop", "protocol": "TCP", "port": float64(22), "ip_range": "0.0.0.0/0",
+			}),
+		},
+		{
+			name: "findings sorted by severity",
+			state: stateWith("drop",
+				rule("ICMP", 0, "", "0.0.0.0/0"),
+				rule("TCP", 443, "", "0.0.0.0/0"),
+				rule("TCP", 22, "", "0.0.0.0/0")),
+			wantSeverity: []Severity{SeverityHigh, SeverityMedium, SeverityLow},
+			wantMessages: []string{"SSH (22) open to the world", "ports open to the world", "ICMP (ping) open to the world"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			report := Run(tt.state)
+
+			if len(report.Findings) != len(tt.wantSeverity) {
+				t.Fatalf("Run() = %d finding(s), want %d: %+v", len(report.Findings), len(tt.wantSeverity), report.Findings)
+			}
+
+			for i, f := range report.Findings {
+				if f.Severity != tt.wantSeverity[i] {
+					t.Errorf("finding %d severity = %s, want %s (%s)", i, f.Severity, tt.wantSeverity[i], f.Message)
+				}
+
+				if i < len(tt.wantMessages) && f.Message != tt.wantMessages[i] {
+					t.Errorf("finding %d message = %q, want %q", i, f.Message, tt.wantMessages[i])
+				}
+
+				if f.Hint == "" {
+					t.Errorf("finding %d has no hint", i)
+				}
+			}
+		})
+	}
+}
+
+func TestRunDeployedFixture(t *testing.T) {
+	t.Parallel()
+
+	state, err := clusterinfotest.LoadFixture(clusterinfotest.FixtureDeployed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report := Run(state)
+
+	if report.RulesChecked != 5 {
+		t.Errorf("RulesChecked = %d, want 5", report.RulesChecked)
+	}
+
+	want := map[Severity]int{SeverityHigh: 2, SeverityMedium: 1, SeverityLow: 1}
+	for sev, n := range want {
+		if got := report.Count(sev); got != n {
+			t.Errorf("Count(%s) = %d, want %d", sev, got, n)
+		}
+	}
+
+	if report.Findings[0].Rule != "TCP 22 from 0.0.0.0/0" {
+		t.Errorf("first finding rule = %q, want the SSH rule", report.Findings[0].Rule)
+	}
+
+	if got := report.CountAtLeast(SeverityMedium); got != 3 {
+		t.Errorf("CountAtLeast(medium) = %d, want 3", got)
+	}
+}
+
+func TestParseSeverity(t *testing.T) {
+	t.Parallel()
+
+	if got, err := ParseSeverity("MEDIUM"); err != nil || got != SeverityMedium {
+		t.Errorf("ParseSeverity(MEDIUM) = %q, %v", got, err)
+	}
+
+	if _, err := ParseSeverity("critical"); !errors.Is(err, ErrUnknownSeverity) {
+		t.Errorf("ParseSeverity(critical) error = %v, want %v", err, ErrUnknownSeverity)
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/k8s-lab/get-cluster-info/audit"
+	"github.com/k8s-lab/get-cluster-info/clusterinfo/clusterinfotest"
+)
+
+// =============================================================================
+// audit subcommand tests
+// =============================================================================
+
+func TestRunAuditGolden(t *testing.T) {
+	// Not parallel - modifies global config
+
+	out, fake := setupRun(t, clusterinfotest.FixtureDeployed)
+
+	if err := runAudit(nil, nil); !errors.Is(err, audit.ErrFindings) {
+		t.Fatalf("runAudit() error = %v, want %v", err, audit.ErrFindings)
+	}
+
+	assertGolden(t, "deployed_audit", out.Bytes())
+
+	if calls := fake.Calls(); calls[len(calls)-1] != "show" {
+		t.Errorf("calls = %v, want the state read with show", calls)
+	}
+}
+
+func TestRunAudit(t *testing.T) {
+	// Not parallel - modifies global config
+
+	tests := []struct {
+		name      string
+		fixture   string
+		failOn    string
+		wantErr   error
+		wantCount int
+	}{
+		{name: "deployed - fails on high", fixture: clusterinfotest.FixtureDeployed, failOn: "high",
+			wantErr: audit.ErrFindings, wantCount: 4},
+		{name: "not deployed - passes", fixture: clusterinfotest.FixtureNotDeployed, failOn: "high"},
+		{name: "invalid --fail-on", fixture: clusterinfotest.FixtureDeployed, failOn: "critical",
+			wantErr: audit.ErrUnknownSeverity, wantCount: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, _ := setupRun(t, tt.fixture)
+			config.JSONOutput = true
+
+			oldFailOn := auditFailOn
+			t.Cleanup(func() { auditFailOn = oldFailOn })
+
+			auditFailOn = tt.failOn
+
+			err := runAudit(nil, nil)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("runAudit() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantCount < 0 {
+				return
+			}
+
+			var report audit.Report
+			if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+				t.Fatalf("invalid JSON report: %v\n%s", err, out.String())
+			}
+
+			if len(report.Findings) != tt.wantCount {
+				t.Errorf("findings = %d, want %d", len(report.Findings), tt.wantCount)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/k8s-lab/get-cluster-info/bootstrap"
+	"github.com/k8s-lab/get-cluster-info/i18n"
+	"github.com/k8s-lab/get-cluster-info/render"
+	"github.com/spf13/cobra"
+)
+
+// =============================================================================
+// Bootstrap subcommand
+// =============================================================================
+//
+// Runs the steps of resources/installK8s.md on every node over SSH (see the
+// bootstrap package): the control plane first, with kubeadm init on its private
+// IP, then the workers, joined with a fresh token. Steps already done are
+// skipped, so the command resumes after a failure.
+
+var bootstrapCmd = &cobra.Command{
+	Use:   "bootstrap",
+	Short: "Install Kubernetes on the nodes over SSH",
+	Long: `Install Kubernetes on the nodes over SSH.
+
+Runs the steps of resources/installK8s.md on every node:
+  - containerd (SystemdCgroup, pause ` + bootstrap.PauseImage + `), swap off, IP forwarding
+  - kubeadm, kubelet and kubectl ` + bootstrap.KubernetesPackage + ` (held), kubelet on the private IP
+  - Helm on the control plane
+then "kubeadm init" on the control plane (API server on its private IP, no
+kube-proxy) and "kubeadm join" on the workers with a freshly created token.
+
+Every step checks whether it is already done: after a failure, fix the
+problem and run the command again, it resumes where it stopped.
+
+The nodes become Ready once a CNI is installed.
+
+Examples:
+  # Bootstrap the deployed lab
+  get-cluster-info bootstrap`,
+	Args: cobra.NoArgs,
+	RunE: runBootstrap,
+}
+
+func init() {
+	rootCmd.AddCommand(bootstrapCmd)
+}
+
+func runBootstrap(_ *cobra.Command, _ []string) error {
+	ctx := context.Background()
+
+	info, dialer, closeSrc, err := openCluster(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer closeSrc()
+
+	logInfo("Bootstrapping Kubernetes %s on %d node(s)...", bootstrap.KubernetesPackage, len(info.AllNodes()))
+
+	err = bootstrap.Run(ctx, info, bootstrap.Options{Dialer: dialer, Progress: logBootstrapEvent})
+	if errors.Is(err, bootstrap.ErrStep) {
+		return i18n.Errorf("%w\n\nFix the problem and run %s again: completed steps are skipped",
+			err, render.CmdStyle.Render("get-cluster-info bootstrap"))
+	}
+
+	if err != nil {
+		return err
+	}
+
+	logSuccess("Cluster bootstrapped - the nodes become Ready once a CNI is installed")
+
+	return nil
+}
+
+// logBootstrapEvent prints the progress of a step; failures are returned as errors.
+func logBootstrapEvent(ev bootstrap.Event) {
+	prefix := fmt.Sprintf("[%s %d/%d]", ev.Node, ev.Index, ev.Total)
+
+	switch ev.Status {
+	case bootstrap.StatusSkipped:
+		logInfo("%s %s %s", prefix, ev.Step, render.LabelStyle.Width(0).Render("(already done)"))
+	case bootstrap.StatusRunning:
+		logInfo("%s %s...", prefix, ev.Step)
+	case bootstrap.StatusDone:
+		logSuccess("%s %s (%s)", prefix, ev.Step, ev.Duration.Round(time.Second))
+	case bootstrap.StatusFailed:
+		// Returned by bootstrap.Run, and printed by main.
+	}
+}
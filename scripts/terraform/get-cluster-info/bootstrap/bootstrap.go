@@ -0,0 +1,507 @@
+// Package bootstrap installs Kubernetes on the nodes of a K8s-Lab cluster over
+// SSH, following resources/installK8s.md: containerd, kernel settings, pinned
+// kubeadm/kubelet/kubectl and Helm on every node, then kubeadm init on the
+// control plane and kubeadm join on the workers. Join adds a single worker to a
+// bootstrapped cluster, and WaitReady waits until Kubernetes reports it Ready.
+//
+// Every step has a check that tells whether it is already done, so running the
+// bootstrap again after a failure resumes where it stopped.
+package bootstrap
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/k8s-lab/get-cluster-info/clusterinfo"
+	"github.com/k8s-lab/get-cluster-info/remote"
+)
+
+// =============================================================================
+// Versions
+// =============================================================================
+
+// Versions and settings of resources/installK8s.md.
+const (
+	KubernetesMinor   = "1.33"
+	KubernetesPackage = "1.33.2-1.1"
+	PauseImage        = "registry.k8s.io/pause:3.10"
+	PodNetworkCIDR    = "192.168.0.0/16"
+	CRISocket         = "unix:///run/containerd/containerd.sock"
+
+	// joinTokenTTL is the lifetime of the tokens created to join the workers.
+	joinTokenTTL = "30m"
+)
+
+var (
+	// ErrNoControlPlane is returned when the cluster has no control-plane node.
+	ErrNoControlPlane = errors.New("no control-plane node in the cluster information")
+	// ErrStep is returned when a step fails; running the bootstrap again resumes from it.
+	ErrStep = errors.New("bootstrap step failed")
+	// ErrJoinCommand is returned when the control plane prints an unexpected join command.
+	ErrJoinCommand = errors.New("unexpected kubeadm join command")
+	// ErrUnknownNode is returned by Join for a node missing from the cluster information.
+	ErrUnknownNode = errors.New("unknown node")
+	// ErrNotWorker is returned by Join for the control plane.
+	ErrNotWorker = errors.New("not a worker node")
+	// ErrNodeNotReady is returned by WaitReady when the node is not Ready before the deadline.
+	ErrNodeNotReady = errors.New("node is not Ready")
+)
+
+// =============================================================================
+// Steps
+// =============================================================================
+
+// Step is an idempotent bootstrap step.
+type Step struct {
+	Name string
+	// Check exits with status 0 when the step is already done (empty: never done).
+	Check string
+	// Script returns the commands of the step, run with bash -euo pipefail.
+	Script func(ctx context.Context) (string, error)
+}
+
+// script returns a Step.Script of static commands.
+func script(s string) func(context.Context) (string, error) {
+	return func(context.Context) (string, error) { return s, nil }
+}
+
+// aptInstall installs packages, waiting for the apt lock held by cloud-init or unattended upgrades.
+const aptInstall = "sudo DEBIAN_FRONTEND=noninteractive apt-get install -y -q -o DPkg::Lock::Timeout=300"
+
+// NodeSteps prepares a node for kubeadm: container runtime, kernel settings,
+// Kubernetes packages, kubelet node IP, and Helm on the control plane.
+func NodeSteps(n clusterinfo.Node) []Step {
+	steps := []Step{
+		{
+			Name:   "wait for cloud-init",
+			Check:  "cloud-init status 2>/dev/null | grep -q 'status: done'",
+			Script: script("cloud-init status --wait >/dev/null || true"),
+		},
+		{
+			Name:  "disable swap",
+			Check: `[ -z "$(swapon --noheadings)" ] && ! grep -qE '^[^#].*\sswap\s' /etc/fstab`,
+			Script: script(`sudo swapoff -a
+sudo sed -i '/^[^#].*\sswap\s/s/^/#/' /etc/fstab`),
+		},
+		{
+			Name: "enable IP forwarding",
+			Check: `[ "$(sysctl -n net.ipv4.ip_forward)" = 1 ] && ` +
+				`grep -qs '^net.ipv4.ip_forward = 1' /etc/sysctl.d/99-kubernetes.conf`,
+			Script: script(`echo 'net.ipv4.ip_forward = 1' | sudo tee /etc/sysctl.d/99-kubernetes.conf >/dev/null
+sudo sysctl --system >/dev/null`),
+		},
+		{
+			Name: "install containerd",
+			Check: `systemctl is-active --quiet containerd && ` +
+				`grep -q 'SystemdCgroup = true' /etc/containerd/config.toml && ` +
+				`grep -qF 'sandbox_image = "` + PauseImage + `"' /etc/containerd/config.toml`,
+			Script: script(`sudo apt-get update -q
+` + aptInstall + ` containerd
+sudo mkdir -p /etc/containerd
+containerd config default \
+  | sed 's/SystemdCgroup = false/SystemdCgroup = true/' \
+  | sed 's|sandbox_image = ".*"|sandbox_image = "` + PauseImage + `"|' \
+  | sudo tee /etc/containerd/config.toml >/dev/null
+sudo systemctl restart containerd`),
+		},
+		{
+			Name: "install kubeadm, kubelet and kubectl " + KubernetesPackage,
+			Check: `for p in kubeadm kubelet kubectl; do ` +
+				`[ "$(dpkg-query -W -f='${Version}' "$p" 2>/dev/null)" = '` + KubernetesPackage + `' ] || exit 1; done`,
+			Script: script(`sudo apt-get update -q
+` + aptInstall + ` apt-transport-https ca-certificates curl gpg
+sudo mkdir -p -m 755 /etc/apt/keyrings
+curl -fsSL https://pkgs.k8s.io/core:/stable:/v` + KubernetesMinor + `/deb/Release.key \
+  | sudo gpg --dearmor --yes -o /etc/apt/keyrings/kubernetes-apt-keyring.gpg
+echo 'deb [signed-by=/etc/apt/keyrings/kubernetes-apt-keyring.gpg] ` +
+				`https://pkgs.k8s.io/core:/stable:/v` + KubernetesMinor + `/deb/ /' \
+  | sudo tee /etc/apt/sources.list.d/kubernetes.list >/dev/null
+sudo apt-get update -q
+sudo apt-mark unhold kubelet kubeadm kubectl >/dev/null 2>&1 || true
+` + aptInstall + ` --allow-downgrades --allow-change-held-packages \
+  kubelet=` + KubernetesPackage + ` kubeadm=` + KubernetesPackage + ` kubectl=` + KubernetesPackage + `
+sudo apt-mark hold kubelet kubeadm kubectl >/dev/null
+sudo systemctl enable kubelet`),
+		},
+		{
+			// /etc/default/kubelet, not a systemd drop-in: see resources/installK8s.md.
+			Name:  "advertise the private IP " + n.PrivateIP,
+			Check: "grep -qx " + remote.Quote("KUBELET_EXTRA_ARGS=--node-ip="+n.PrivateIP) + " /etc/default/kubelet",
+			Script: script(`hostname -I | tr ' ' '\n' | grep -qx ` + remote.Quote(n.PrivateIP) + ` || ` +
+				`{ echo "private IP ` + n.PrivateIP + ` not found on this node" >&2; exit 1; }
+echo ` + remote.Quote("KUBELET_EXTRA_ARGS=--node-ip="+n.PrivateIP) + ` | sudo tee /etc/default/kubelet >/dev/null`),
+		},
+	}
+
+	if n.Role == clusterinfo.RoleControlPlane {
+		steps = append(steps, Step{
+			Name:  "install Helm",
+			Check: "command -v helm >/dev/null",
+			Script: script(aptInstall + ` curl gpg apt-transport-https
+curl -fsSL https://packages.buildkite.com/helm-linux/helm-debian/gpgkey \
+  | gpg --dearmor | sudo tee /usr/share/keyrings/helm.gpg >/dev/null
+echo 'deb [signed-by=/usr/share/keyrings/helm.gpg] ` +
+				`https://packages.buildkite.com/helm-linux/helm-debian/any/ any main' \
+  | sudo tee /etc/apt/sources.list.d/helm-stable-debian.list >/dev/null
+sudo apt-get update -q
+` + aptInstall + ` helm`),
+		})
+	}
+
+	return steps
+}
+
+// InitSteps creates the cluster on the control plane, advertising its private
+// IP, without kube-proxy (replaced by Cilium).
+func InitSteps(n clusterinfo.Node) []Step {
+	return []Step{
+		{
+			Name:  "kubeadm init",
+			Check: "[ -f /etc/kubernetes/admin.conf ]",
+			Script: script("sudo kubeadm init" +
+				" --apiserver-advertise-address=" + n.PrivateIP +
+				" --pod-network-cidr=" + PodNetworkCIDR +
+				" --cri-socket=" + CRISocket +
+				" --skip-phases=addon/kube-proxy"),
+		},
+		{
+			Name:  "kubeconfig for " + remote.DefaultUser,
+			Check: `[ -s "$HOME/.kube/config" ]`,
+			Script: script(`mkdir -p "$HOME/.kube"
+sudo cp /etc/kubernetes/admin.conf "$HOME/.kube/config"
+sudo chown "$(id -u):$(id -g)" "$HOME/.kube/config"`),
+		},
+	}
+}
+
+// JoinStep joins a worker with a join command freshly created on the control plane.
+func JoinStep(controlPlane remote.Client) Step {
+	return Step{
+		Name:  "kubeadm join",
+		Check: "[ -f /etc/kubernetes/kubelet.conf ]",
+		Script: func(ctx context.Context) (string, error) {
+			join, err := JoinCommand(ctx, controlPlane)
+			if err != nil {
+				return "", err
+			}
+
+			return "sudo " + join + " --cri-socket=" + CRISocket, nil
+		},
+	}
+}
+
+// JoinCommand creates a join token on the control plane and returns the
+// `kubeadm join` command using it.
+func JoinCommand(ctx context.Context, controlPlane remote.Client) (string, error) {
+	out, err := controlPlane.Run(ctx, "sudo kubeadm token create --print-join-command --ttl "+joinTokenTTL)
+	if err != nil {
+		return "", fmt.Errorf("failed to create a join token on the control plane: %w", err)
+	}
+
+	join := strings.TrimSpace(string(out))
+	if !strings.HasPrefix(join, "kubeadm join ") || strings.ContainsAny(join, "\n;&|`$") {
+		return "", fmt.Errorf("%w: %q", ErrJoinCommand, join)
+	}
+
+	return join, nil
+}
+
+// =============================================================================
+// Run
+// =============================================================================
+
+// Status is the progress of a step.
+type Status string
+
+// Step statuses, reported in this order: skipped, or running then done or failed.
+const (
+	StatusSkipped Status = "skipped"
+	StatusRunning Status = "running"
+	StatusDone    Status = "done"
+	StatusFailed  Status = "failed"
+)
+
+// Event reports the progress of a step on a node.
+type Event struct {
+	Node     string
+	Step     string
+	Index    int // 1-based
+	Total    int
+	Status   Status
+	Duration time.Duration
+	Err      error
+}
+
+// Options configures Run.
+type Options struct {
+	// Dialer connects to the nodes by public IP.
+	Dialer remote.Dialer
+	// Progress receives the step events (nil: silent).
+	Progress func(Event)
+}
+
+// Run bootstraps the cluster: the control plane first, then every worker.
+func Run(ctx context.Context, info *clusterinfo.ClusterInfo, opts Options) error {
+	cp, err := ControlPlane(info)
+	if err != nil {
+		return err
+	}
+
+	cpClient, err := opts.Dialer.Dial(ctx, cp.PublicIP)
+	if err != nil {
+		return fmt.Errorf("%s: %w", cp.Name, err)
+	}
+
+	defer func() { _ = cpClient.Close() }()
+
+	if err := RunSteps(ctx, cpClient, cp.Name, append(NodeSteps(cp), InitSteps(cp)...), opts.Progress); err != nil {
+		return err
+	}
+
+	for _, n := range info.AllNodes() {
+		if n.Role == clusterinfo.RoleControlPlane && n.Name == cp.Name {
+			continue
+		}
+
+		if err := joinNode(ctx, opts, cpClient, n); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Join prepares the worker name and joins it to the cluster bootstrapped on the
+// control plane, like Run does for every worker.
+func Join(ctx context.Context, info *clusterinfo.ClusterInfo, name string, opts Options) error {
+	cp, err := ControlPlane(info)
+	if err != nil {
+		return err
+	}
+
+	idx := slices.IndexFunc(info.AllNodes(), func(n clusterinfo.Node) bool { return n.Name == name })
+	if idx < 0 {
+		names := make([]string, 0, len(info.AllNodes()))
+		for _, n := range info.AllNodes() {
+			names = append(names, n.Name)
+		}
+
+		return fmt.Errorf("%w %q (nodes: %s)", ErrUnknownNode, name, strings.Join(names, ", "))
+	}
+
+	n := info.AllNodes()[idx]
+	if n.Role != clusterinfo.RoleWorker {
+		return fmt.Errorf("%w: %s is a %s", ErrNotWorker, n.Name, n.Role)
+	}
+
+	cpClient, err := opts.Dialer.Dial(ctx, cp.PublicIP)
+	if err != nil {
+		return fmt.Errorf("%s: %w", cp.Name, err)
+	}
+
+	defer func() { _ = cpClient.Close() }()
+
+	return joinNode(ctx, opts, cpClient, n)
+}
+
+// ControlPlane returns the first control-plane node of the cluster.
+func ControlPlane(info *clusterinfo.ClusterInfo) (clusterinfo.Node, error) {
+	for _, n := range info.AllNodes() {
+		if n.Role == clusterinfo.RoleControlPlane {
+			return n, nil
+		}
+	}
+
+	return clusterinfo.Node{}, ErrNoControlPlane
+}
+
+func joinNode(ctx context.Context, opts Options, cpClient remote.Client, n clusterinfo.Node) error {
+	client, err := opts.Dialer.Dial(ctx, n.PublicIP)
+	if err != nil {
+		return fmt.Errorf("%s: %w", n.Name, err)
+	}
+
+	defer func() { _ = client.Close() }()
+
+	return RunSteps(ctx, client, n.Name, append(NodeSteps(n), JoinStep(cpClient)), opts.Progress)
+}
+
+// RunSteps runs the steps that are not done yet on a node, in order, and stops
+// at the first failure.
+func RunSteps(ctx context.Context, c remote.Client, node string, steps []Step, progress func(Event)) error {
+	if progress == nil {
+		progress = func(Event) {}
+	}
+
+	for i, s := range steps {
+		ev := Event{Node: node, Step: s.Name, Index: i + 1, Total: len(steps)}
+
+		done, err := s.done(ctx, c)
+		if err == nil && done {
+			ev.Status = StatusSkipped
+			progress(ev)
+
+			continue
+		}
+
+		start := time.Now()
+
+		if err == nil {
+			ev.Status = StatusRunning
+			progress(ev)
+
+			var cmds string
+
+			cmds, err = s.Script(ctx)
+			if err == nil {
+				_, err = c.Run(ctx, Shell(cmds))
+			}
+		}
+
+		ev.Duration = time.Since(start)
+
+		if err != nil {
+			ev.Status, ev.Err = StatusFailed, err
+			progress(ev)
+
+			return fmt.Errorf("%w on %s (%s): %w", ErrStep, node, s.Name, err)
+		}
+
+		ev.Status = StatusDone
+		progress(ev)
+	}
+
+	return nil
+}
+
+// =============================================================================
+// Node readiness
+// =============================================================================
+
+// NodeStatus is the state of a node registered in the cluster.
+type NodeStatus struct {
+	// Name is the Kubernetes node name (the hostname of the VM).
+	Name  string
+	Ready bool
+	// Reason is the message of the Ready condition when not Ready.
+	Reason string
+}
+
+// nodeList is the subset of `kubectl get nodes -o json` read by GetNodeStatus.
+type nodeList struct {
+	Items []struct {
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+		Status struct {
+			Addresses  []nodeAddress `json:"addresses"`
+			Conditions []struct {
+				Type    string `json:"type"`
+				Status  string `json:"status"`
+				Message string `json:"message"`
+			} `json:"conditions"`
+		} `json:"status"`
+	} `json:"items"`
+}
+
+// nodeAddress is an address of a node (InternalIP, Hostname...).
+type nodeAddress struct {
+	Type    string `json:"type"`
+	Address string `json:"address"`
+}
+
+// GetNodeStatus finds the node registered with the private IP of n, with kubectl
+// on the control plane. found is false until the kubelet registered the node.
+func GetNodeStatus(ctx context.Context, controlPlane remote.Client, n clusterinfo.Node) (NodeStatus, bool, error) {
+	out, err := controlPlane.Run(ctx, "kubectl get nodes -o json")
+	if err != nil {
+		return NodeStatus{}, false, fmt.Errorf("failed to list the nodes: %w", err)
+	}
+
+	var nodes nodeList
+	if err := json.Unmarshal(out, &nodes); err != nil {
+		return NodeStatus{}, false, fmt.Errorf("failed to parse kubectl output: %w", err)
+	}
+
+	for _, it := range nodes.Items {
+		internal := nodeAddress{Type: "InternalIP", Address: n.PrivateIP}
+		if !slices.Contains(it.Status.Addresses, internal) {
+			continue
+		}
+
+		status := NodeStatus{Name: it.Metadata.Name, Reason: "no Ready condition yet"}
+
+		for _, c := range it.Status.Conditions {
+			if c.Type == "Ready" {
+				status.Ready, status.Reason = c.Status == "True", c.Message
+			}
+		}
+
+		if status.Ready {
+			status.Reason = ""
+		}
+
+		return status, true, nil
+	}
+
+	return NodeStatus{}, false, nil
+}
+
+// WaitReady polls the node n every interval until it is Ready, reporting each
+// status to progress (optional). When ctx is done, it returns ErrNodeNotReady.
+func WaitReady(ctx context.Context, controlPlane remote.Client, n clusterinfo.Node, interval time.Duration,
+	progress func(NodeStatus),
+) (NodeStatus, error) {
+	last := NodeStatus{Reason: "not registered"}
+
+	for {
+		// The API server may be briefly unreachable: retry.
+		if s, found, err := GetNodeStatus(ctx, controlPlane, n); err == nil && found {
+			last = s
+
+			if progress != nil {
+				progress(s)
+			}
+
+			if s.Ready {
+				return s, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return last, fmt.Errorf("%w: %s (%s): %w", ErrNodeNotReady, n.Name, last.Reason, ctx.Err())
+		case <-time.After(interval):
+		}
+	}
+}
+
+// done runs the check of the step: exit status 0 means done.
+func (s Step) done(ctx context.Context, c remote.Client) (bool, error) {
+	if s.Check == "" {
+		return false, nil
+	}
+
+	_, err := c.Run(ctx, Shell(s.Check))
+
+	var exitErr *remote.ExitError
+
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.As(err, &exitErr):
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+// Shell wraps commands for the login shell of the node: bash, failing on the first error.
+func Shell(cmds string) string {
+	return "bash -euo pipefail -c " + remote.Quote(cmds)
+}
@@ -0,0 +1,356 @@
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/k8s-lab/get-cluster-info/clusterinfo"
+	"github.com/k8s-lab/get-cluster-info/remote"
+	"github.com/k8s-lab/get-cluster-info/remote/remotetest"
+)
+
+// =============================================================================
+// Bootstrap tests
+// =============================================================================
+
+const testJoin = "kubeadm join 10.0.0.10:6443 --token abcdef.0123456789abcdef " +
+	"--discovery-token-ca-cert-hash sha256:0000000000000000000000000000000000000000000000000000000000000000"
+
+var testInfo = &clusterinfo.ClusterInfo{Nodes: []clusterinfo.Node{
+	{Name: "worker", Role: clusterinfo.RoleWorker, NodeInfo: clusterinfo.NodeInfo{
+		PublicIP: "203.0.113.11", PrivateIP: "10.0.0.11",
+	}},
+	{Name: "control-plane", Role: clusterinfo.RoleControlPlane, NodeInfo: clusterinfo.NodeInfo{
+		PublicIP: "203.0.113.10", PrivateIP: "10.0.0.10",
+	}},
+}}
+
+// lab simulates the nodes: a step is done once its script ran (or if listed in done).
+type lab struct {
+	checks  map[string]string // check command -> step name
+	scripts map[string]string // static script command -> step name
+	done    map[string]bool   // host + step name
+	fail    string            // step name whose script fails
+}
+
+func newLab(t *testing.T) *lab {
+	t.Helper()
+
+	l := &lab{checks: map[string]string{}, scripts: map[string]string{}, done: map[string]bool{}}
+
+	for _, n := range testInfo.AllNodes() {
+		for _, s := range append(append(NodeSteps(n), InitSteps(n)...), JoinStep(nil)) {
+			l.checks[Shell(s.Check)] = s.Name
+
+			if s.Name != "kubeadm join" {
+				cmds, err := s.Script(context.Background())
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				l.scripts[Shell(cmds)] = s.Name
+			}
+		}
+	}
+
+	return l
+}
+
+func (l *lab) handler(host, cmd string) ([]byte, error) {
+	if strings.HasPrefix(cmd, "sudo kubeadm token create") {
+		return []byte(testJoin + "\n"), nil
+	}
+
+	if name, ok := l.checks[cmd]; ok {
+		if l.done[host+name] {
+			return nil, nil
+		}
+
+		return nil, remotetest.Exit(1)
+	}
+
+	name, ok := l.scripts[cmd]
+	if !ok && strings.Contains(cmd, "kubeadm join") {
+		name = "kubeadm join"
+	}
+
+	if name == l.fail {
+		return nil, &remote.ExitError{Status: 100, Stderr: "E: Unable to locate package containerd"}
+	}
+
+	l.done[host+name] = true
+
+	return nil, nil
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	l := newLab(t)
+	dialer := &remotetest.FakeDialer{Handler: l.handler}
+
+	var events []Event
+
+	err := Run(context.Background(), testInfo, Options{Dialer: dialer, Progress: func(ev Event) {
+		events = append(events, ev)
+	}})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	// Control plane first, then the worker.
+	cp, worker := dialer.Commands("203.0.113.10"), dialer.Commands("203.0.113.11")
+	if len(events) == 0 || events[0].Node != "control-plane" || events[len(events)-1].Node != "worker" {
+		t.Errorf("events not ordered control-plane then worker: %+v", events)
+	}
+
+	if !slices.ContainsFunc(cp, func(c string) bool {
+		return strings.Contains(c, "kubeadm init --apiserver-advertise-address=10.0.0.10 ")
+	}) {
+		t.Errorf("kubeadm init on the private IP not run on the control plane: %v", cp)
+	}
+
+	if !slices.Contains(cp, "sudo kubeadm token create --print-join-command --ttl "+joinTokenTTL) {
+		t.Errorf("no join token created on the control plane: %v", cp)
+	}
+
+	if want := Shell("sudo " + testJoin + " --cri-socket=" + CRISocket); !slices.Contains(worker, want) {
+		t.Errorf("worker commands = %v, want the join command", worker)
+	}
+
+	if slices.ContainsFunc(worker, func(c string) bool { return strings.Contains(c, "kubeadm init") }) {
+		t.Error("kubeadm init run on the worker")
+	}
+
+	// Helm on the control plane only.
+	if slices.ContainsFunc(worker, func(c string) bool { return strings.Contains(c, "helm") }) {
+		t.Error("Helm installed on the worker")
+	}
+
+	total := map[string]int{}
+	for _, ev := range events {
+		total[ev.Node] = ev.Total
+	}
+
+	if total["control-plane"] != len(NodeSteps(testInfo.Nodes[1]))+len(InitSteps(testInfo.Nodes[1])) ||
+		total["worker"] != len(NodeSteps(testInfo.Nodes[0]))+1 {
+		t.Errorf("step totals = %v", total)
+	}
+
+	if dialer.Closed() != 2 {
+		t.Errorf("closed clients = %d, want 2", dialer.Closed())
+	}
+}
+
+func TestRunResumes(t *testing.T) {
+	t.Parallel()
+
+	l := newLab(t)
+	l.fail = "install containerd"
+	dialer := &remotetest.FakeDialer{Handler: l.handler}
+
+	err := Run(context.Background(), testInfo, Options{Dialer: dialer})
+	if !errors.Is(err, ErrStep) || !strings.Contains(err.Error(), "control-plane (install containerd)") {
+		t.Fatalf("Run() error = %v, want %v on the control plane", err, ErrStep)
+	}
+
+	if len(dialer.Commands("203.0.113.11")) != 0 {
+		t.Error("worker bootstrapped after a control plane failure")
+	}
+
+	// Second run: the steps done before the failure are skipped.
+	l.fail = ""
+
+	var skipped []string
+
+	err = Run(context.Background(), testInfo, Options{Dialer: dialer, Progress: func(ev Event) {
+		if ev.Node == "control-plane" && ev.Status == StatusSkipped {
+			skipped = append(skipped, ev.Step)
+		}
+	}})
+	if err != nil {
+		t.Fatalf("Run() after fix error = %v", err)
+	}
+
+	if want := []string{"wait for cloud-init", "disable swap", "enable IP forwarding"}; !slices.Equal(skipped, want) {
+		t.Errorf("skipped = %v, want %v", skipped, want)
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no control plane", func(t *testing.T) {
+		t.Parallel()
+
+		info := &clusterinfo.ClusterInfo{Nodes: []clusterinfo.Node{{Name: "w", Role: clusterinfo.RoleWorker}}}
+
+		err := Run(context.Background(), info, Options{Dialer: &remotetest.FakeDialer{}})
+		if !errors.Is(err, ErrNoControlPlane) {
+			t.Errorf("Run() error = %v, want %v", err, ErrNoControlPlane)
+		}
+	})
+
+	t.Run("unreachable node", func(t *testing.T) {
+		t.Parallel()
+
+		errRefused := errors.New("connection refused")
+		dialer := &remotetest.FakeDialer{DialErr: map[string]error{"203.0.113.10": errRefused}}
+
+		if err := Run(context.Background(), testInfo, Options{Dialer: dialer}); !errors.Is(err, errRefused) {
+			t.Errorf("Run() error = %v, want %v", err, errRefused)
+		}
+	})
+}
+
+func TestJoinCommand(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		out     string
+		wantErr error
+	}{
+		{name: "valid", out: testJoin + "\n"},
+		{name: "unexpected output", out: "error: cluster not initialized", wantErr: ErrJoinCommand},
+		{name: "shell injection", out: "kubeadm join 10.0.0.10:6443; reboot", wantErr: ErrJoinCommand},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dialer := &remotetest.FakeDialer{Handler: func(_, _ string) ([]byte, error) { return []byte(tt.out), nil }}
+			client, _ := dialer.Dial(context.Background(), "203.0.113.10")
+
+			got, err := JoinCommand(context.Background(), client)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("JoinCommand() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr == nil && got != testJoin {
+				t.Errorf("JoinCommand() = %q, want %q", got, testJoin)
+			}
+		})
+	}
+}
+
+func TestJoin(t *testing.T) {
+	t.Parallel()
+
+	t.Run("worker", func(t *testing.T) {
+		t.Parallel()
+
+		l := newLab(t)
+		dialer := &remotetest.FakeDialer{Handler: l.handler}
+
+		if err := Join(context.Background(), testInfo, "worker", Options{Dialer: dialer}); err != nil {
+			t.Fatalf("Join() error = %v", err)
+		}
+
+		// The control plane only creates the token.
+		if cp := dialer.Commands("203.0.113.10"); len(cp) != 1 || !strings.Contains(cp[0], "kubeadm token create") {
+			t.Errorf("control plane commands = %v, want the token creation only", cp)
+		}
+
+		if want := Shell("sudo " + testJoin + " --cri-socket=" + CRISocket); !slices.Contains(
+			dialer.Commands("203.0.113.11"), want) {
+			t.Errorf("worker commands = %v, want the join command", dialer.Commands("203.0.113.11"))
+		}
+	})
+
+	tests := []struct {
+		name    string
+		node    string
+		wantErr error
+	}{
+		{name: "unknown node", node: "worker-9", wantErr: ErrUnknownNode},
+		{name: "control plane", node: "control-plane", wantErr: ErrNotWorker},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dialer := &remotetest.FakeDialer{}
+
+			if err := Join(context.Background(), testInfo, tt.node, Options{Dialer: dialer}); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Join() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if len(dialer.Calls()) != 0 {
+				t.Errorf("commands run: %v", dialer.Calls())
+			}
+		})
+	}
+}
+
+// nodesJSON is `kubectl get nodes -o json` with the worker in the given Ready state.
+func nodesJSON(ready string) []byte {
+	return []byte(`{"items": [
+  {"metadata": {"name": "k8s-lab-control-plane"}, "status": {
+    "addresses": [{"type": "InternalIP", "address": "10.0.0.10"}],
+    "conditions": [{"type": "Ready", "status": "True", "message": "kubelet is posting ready status"}]}},
+  {"metadata": {"name": "k8s-lab-worker-1"}, "status": {
+    "addresses": [{"type": "InternalIP", "address": "10.0.0.11"}, {"type": "Hostname", "address": "k8s-lab-worker-1"}],
+    "conditions": [{"type": "Ready", "status": "` + ready + `", "message": "container runtime network not ready"}]}}
+]}`)
+}
+
+func TestWaitReady(t *testing.T) {
+	t.Parallel()
+
+	worker := testInfo.Nodes[0]
+
+	t.Run("ready after registration", func(t *testing.T) {
+		t.Parallel()
+
+		answers := [][]byte{[]byte(`{"items": []}`), nodesJSON("False"), nodesJSON("True")}
+		polls := 0
+
+		dialer := &remotetest.FakeDialer{Handler: func(_, _ string) ([]byte, error) {
+			out := answers[min(polls, len(answers)-1)]
+			polls++
+
+			return out, nil
+		}}
+		client, _ := dialer.Dial(context.Background(), "203.0.113.10")
+
+		var reported []NodeStatus
+
+		s, err := WaitReady(context.Background(), client, worker, time.Millisecond, func(s NodeStatus) {
+			reported = append(reported, s)
+		})
+		if err != nil {
+			t.Fatalf("WaitReady() error = %v", err)
+		}
+
+		if want := (NodeStatus{Name: "k8s-lab-worker-1", Ready: true}); s != want {
+			t.Errorf("WaitReady() = %+v, want %+v", s, want)
+		}
+
+		// Not reported before the kubelet registered the node.
+		if len(reported) != 2 || reported[0].Ready {
+			t.Errorf("reported = %+v, want not ready then ready", reported)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		t.Parallel()
+
+		dialer := &remotetest.FakeDialer{Handler: func(_, _ string) ([]byte, error) { return nodesJSON("False"), nil }}
+		client, _ := dialer.Dial(context.Background(), "203.0.113.10")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		_, err := WaitReady(ctx, client, worker, time.Millisecond, nil)
+		if !errors.Is(err, ErrNodeNotReady) || !strings.Contains(err.Error(), "container runtime network not ready") {
+			t.Errorf("WaitReady() error = %v, want %v with the kubelet message", err, ErrNodeNotReady)
+		}
+	})
+}
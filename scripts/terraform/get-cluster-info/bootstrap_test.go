@@ -0,0 +1,85 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/k8s-lab/get-cluster-info/bootstrap"
+	"github.com/k8s-lab/get-cluster-info/clusterinfo/clusterinfotest"
+	"github.com/k8s-lab/get-cluster-info/remote"
+	"github.com/k8s-lab/get-cluster-info/remote/remotetest"
+)
+
+// =============================================================================
+// bootstrap subcommand tests
+// =============================================================================
+
+// setupBootstrap is setupRun with a fake SSH dialer answering with handler.
+func setupBootstrap(t *testing.T, handler func(host, cmd string) ([]byte, error)) (*remotetest.FakeDialer,
+	func() string,
+) {
+	t.Helper()
+
+	out, _ := setupRun(t, clusterinfotest.FixtureDeployed)
+	config.Quiet = false
+
+	dialer := &remotetest.FakeDialer{Handler: handler}
+
+	oldDialer := remoteDialer
+	t.Cleanup(func() { remoteDialer = oldDialer })
+
+	remoteDialer = dialer
+
+	return dialer, out.String
+}
+
+func TestRunBootstrap(t *testing.T) {
+	// Not parallel - modifies global config
+
+	t.Run("already bootstrapped", func(t *testing.T) {
+		// Every check succeeds: nothing to run.
+		dialer, out := setupBootstrap(t, nil)
+
+		must(t, runBootstrap(nil, nil))
+
+		for _, c := range dialer.Calls() {
+			if !strings.HasPrefix(c.Cmd, "bash -euo pipefail -c ") {
+				t.Errorf("unexpected command on %s: %s", c.Host, c.Cmd)
+			}
+		}
+
+		if n := strings.Count(out(), "(already done)"); n != len(dialer.Calls()) {
+			t.Errorf("%d steps reported done, want %d:\n%s", n, len(dialer.Calls()), out())
+		}
+
+		for _, want := range []string{"[control-plane 1/", "[worker 1/", "Cluster bootstrapped"} {
+			if !strings.Contains(out(), want) {
+				t.Errorf("output missing %q:\n%s", want, out())
+			}
+		}
+	})
+
+	t.Run("failure hints at resuming", func(t *testing.T) {
+		_, out := setupBootstrap(t, func(_, cmd string) ([]byte, error) {
+			if strings.Contains(cmd, "swapon") {
+				return nil, remotetest.Exit(1) // swap still on
+			}
+
+			if strings.Contains(cmd, "swapoff") {
+				return nil, &remote.ExitError{Status: 1, Stderr: "swapoff: Not superuser."}
+			}
+
+			return nil, nil
+		})
+
+		err := runBootstrap(nil, nil)
+		if !errors.Is(err, bootstrap.ErrStep) || !strings.Contains(err.Error(), "completed steps are skipped") {
+			t.Fatalf("runBootstrap() error = %v, want %v with a resume hint", err, bootstrap.ErrStep)
+		}
+
+		if !strings.Contains(out(), "[control-plane 2/") || !strings.Contains(out(), "disable swap...") {
+			t.Errorf("running step not reported:\n%s", out())
+		}
+	})
+}
@@ -0,0 +1,212 @@
+// Package bundle collects a support bundle from the nodes of a K8s-Lab
+// cluster over SSH: the logs and state needed to debug a broken lab, with the
+// secrets scrubbed, packaged as a tar.gz.
+//
+// Collection is best effort: a failing command or an unreachable node is
+// recorded in the bundle (errors.txt) instead of aborting it, since a broken
+// lab is precisely when a bundle is needed.
+package bundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/k8s-lab/get-cluster-info/clusterinfo"
+	"github.com/k8s-lab/get-cluster-info/remote"
+)
+
+// =============================================================================
+// Items
+// =============================================================================
+
+const (
+	// journalLines bounds the journal of each unit.
+	journalLines = 5000
+	// fileMode is the mode of the files in the archive.
+	fileMode = 0o600
+	// dirMode is the mode of the directories in the archive.
+	dirMode = 0o700
+	// TimeFormat names the bundles.
+	TimeFormat = "2006-01-02T150405Z"
+)
+
+// Item is a file of the bundle, produced by a command on a node.
+type Item struct {
+	File string
+	Cmd  string
+	// ControlPlaneOnly items need the admin kubeconfig.
+	ControlPlaneOnly bool
+}
+
+// Items are collected from every node, in this order.
+var Items = []Item{
+	{File: "kubelet.log", Cmd: journal("kubelet")},
+	{File: "containerd.log", Cmd: journal("containerd")},
+	{File: "cloud-init.log", Cmd: "sudo cat /var/log/cloud-init.log"},
+	{File: "cloud-init-output.log", Cmd: "sudo cat /var/log/cloud-init-output.log"},
+	{File: "crictl-ps.txt", Cmd: "sudo crictl ps -a"},
+	{File: "ip-addr.txt", Cmd: "ip addr"},
+	{File: "ip-route.txt", Cmd: "ip route"},
+	{File: "sysctl.txt", Cmd: "sudo sysctl -a"},
+	{
+		File:             "kubeadm-config.yaml",
+		Cmd:              "kubectl -n kube-system get configmap kubeadm-config -o yaml",
+		ControlPlaneOnly: true,
+	},
+}
+
+func journal(unit string) string {
+	return "sudo journalctl -u " + unit + " --no-pager -n " + strconv.Itoa(journalLines)
+}
+
+// =============================================================================
+// Collect
+// =============================================================================
+
+// File is a file of the bundle, relative to its root directory.
+type File struct {
+	Path string
+	Data []byte
+}
+
+// Options configures Collect.
+type Options struct {
+	// Dialer connects to the nodes by public IP.
+	Dialer remote.Dialer
+	// Progress receives each item before it is collected (nil: silent).
+	Progress func(node, file string)
+}
+
+// ClusterInfoFile is the redacted cluster information in the bundle.
+const ClusterInfoFile = "cluster-info.json"
+
+// ErrorsFile lists what could not be collected.
+const ErrorsFile = "errors.txt"
+
+// Collect runs the items on every node and returns the scrubbed files of the
+// bundle: cluster-info.json, <node>/<item>, and errors.txt when something
+// could not be collected.
+func Collect(ctx context.Context, info *clusterinfo.ClusterInfo, opts Options) []File {
+	progress := opts.Progress
+	if progress == nil {
+		progress = func(string, string) {}
+	}
+
+	scrub := NewScrubber(info)
+	redacted := info.Redacted()
+
+	data, _ := json.MarshalIndent(redacted, "", "  ")
+	files := []File{{Path: ClusterInfoFile, Data: append(data, '\n')}}
+
+	var failures []string
+
+	for _, n := range info.AllNodes() {
+		client, err := opts.Dialer.Dial(ctx, n.PublicIP)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", n.Name, err))
+
+			continue
+		}
+
+		for _, it := range Items {
+			if it.ControlPlaneOnly && n.Role != clusterinfo.RoleControlPlane {
+				continue
+			}
+
+			progress(n.Name, it.File)
+
+			out, err := client.Run(ctx, it.Cmd)
+			if err != nil {
+				failures = append(failures, fmt.Sprintf("%s/%s: %s: %v", n.Name, it.File, it.Cmd, err))
+			}
+
+			if err == nil || len(out) > 0 {
+				files = append(files, File{Path: path.Join(n.Name, it.File), Data: out})
+			}
+		}
+
+		_ = client.Close()
+	}
+
+	if len(failures) > 0 {
+		files = append(files, File{Path: ErrorsFile, Data: []byte(strings.Join(failures, "\n") + "\n")})
+	}
+
+	for i := range files {
+		files[i].Data = scrub.Scrub(files[i].Data)
+	}
+
+	return files
+}
+
+// =============================================================================
+// Archive
+// =============================================================================
+
+// Name returns the name of the bundle created at t, also its root directory.
+func Name(t time.Time) string {
+	return "k8s-lab-support-" + t.UTC().Format(TimeFormat)
+}
+
+// Write packages the files as a tar.gz under the root directory Name(t).
+func Write(w io.Writer, files []File, t time.Time) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	root := Name(t)
+	dirs := map[string]bool{}
+
+	header := func(name string, mode int64, size int) *tar.Header {
+		typ := byte(tar.TypeReg)
+		if size < 0 {
+			typ, size = tar.TypeDir, 0
+		}
+
+		return &tar.Header{Typeflag: typ, Name: name, Mode: mode, Size: int64(size), ModTime: t}
+	}
+
+	for _, f := range files {
+		for dir := path.Dir(path.Join(root, f.Path)); !dirs[dir]; dir = path.Dir(dir) {
+			if dir == "." {
+				break
+			}
+
+			dirs[dir] = true
+		}
+	}
+
+	for _, dir := range slices.Sorted(maps.Keys(dirs)) {
+		if err := tw.WriteHeader(header(dir+"/", dirMode, -1)); err != nil {
+			return fmt.Errorf("failed to write the archive: %w", err)
+		}
+	}
+
+	for _, f := range files {
+		if err := tw.WriteHeader(header(path.Join(root, f.Path), fileMode, len(f.Data))); err != nil {
+			return fmt.Errorf("failed to write the archive: %w", err)
+		}
+
+		if _, err := tw.Write(f.Data); err != nil {
+			return fmt.Errorf("failed to write the archive: %w", err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to write the archive: %w", err)
+	}
+
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("failed to write the archive: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,186 @@
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/k8s-lab/get-cluster-info/clusterinfo"
+	"github.com/k8s-lab/get-cluster-info/remote"
+	"github.com/k8s-lab/get-cluster-info/remote/remotetest"
+)
+
+// =============================================================================
+// Support bundle tests
+// =============================================================================
+
+var testInfo = &clusterinfo.ClusterInfo{Nodes: []clusterinfo.Node{
+	{Name: "control-plane", Role: clusterinfo.RoleControlPlane, NodeInfo: clusterinfo.NodeInfo{
+		PublicIP: "203.0.113.10", PrivateIP: "10.0.0.10",
+	}},
+	{Name: "worker", Role: clusterinfo.RoleWorker, NodeInfo: clusterinfo.NodeInfo{
+		PublicIP: "203.0.113.11", PrivateIP: "10.0.0.11",
+	}},
+}}
+
+func paths(files []File) []string {
+	var p []string
+	for _, f := range files {
+		p = append(p, f.Path)
+	}
+
+	return p
+}
+
+func find(files []File, path string) string {
+	for _, f := range files {
+		if f.Path == path {
+			return string(f.Data)
+		}
+	}
+
+	return ""
+}
+
+func TestCollect(t *testing.T) {
+	t.Parallel()
+
+	t.Run("every item", func(t *testing.T) {
+		t.Parallel()
+
+		dialer := &remotetest.FakeDialer{Handler: func(host, cmd string) ([]byte, error) {
+			if strings.HasPrefix(cmd, "sudo journalctl -u kubelet") {
+				return []byte("I0301 kubelet on " + host + ": joined with --token abcdef.0123456789abcdef\n"), nil
+			}
+
+			return []byte(cmd + "\n"), nil
+		}}
+
+		var progress []string
+
+		files := Collect(context.Background(), testInfo, Options{Dialer: dialer, Progress: func(node, file string) {
+			progress = append(progress, node+"/"+file)
+		}})
+
+		got := paths(files)
+		if got[0] != ClusterInfoFile || slices.Contains(got, ErrorsFile) {
+			t.Errorf("files = %v, want cluster-info.json first and no errors", got)
+		}
+
+		for _, want := range []string{"control-plane/kubelet.log", "control-plane/kubeadm-config.yaml", "worker/sysctl.txt"} {
+			if !slices.Contains(got, want) {
+				t.Errorf("files missing %s: %v", want, got)
+			}
+		}
+
+		if slices.Contains(got, "worker/kubeadm-config.yaml") {
+			t.Error("kubeadm config collected on the worker (no kubeconfig there)")
+		}
+
+		if len(progress) != len(got)-1 {
+			t.Errorf("progress reported %d items, want %d", len(progress), len(got)-1)
+		}
+
+		kubelet := find(files, "worker/kubelet.log")
+		if want := "I0301 kubelet on <public-ip:worker>: joined with --token <redacted>\n"; kubelet != want {
+			t.Errorf("worker/kubelet.log = %q, want %q", kubelet, want)
+		}
+
+		info := find(files, ClusterInfoFile)
+		if strings.Contains(info, "203.0.113.") || !strings.Contains(info, "10.0.0.11") {
+			t.Errorf("cluster-info.json not redacted:\n%s", info)
+		}
+	})
+
+	t.Run("failures are recorded", func(t *testing.T) {
+		t.Parallel()
+
+		errRefused := errors.New("connection refused")
+		dialer := &remotetest.FakeDialer{
+			DialErr: map[string]error{"203.0.113.11": errRefused},
+			Handler: func(_, cmd string) ([]byte, error) {
+				if cmd == "sudo crictl ps -a" {
+					return []byte("partial\n"), &remote.ExitError{Status: 1, Stderr: "crictl: command not found"}
+				}
+
+				if strings.HasPrefix(cmd, "kubectl") {
+					return nil, remotetest.Exit(1)
+				}
+
+				return nil, nil
+			},
+		}
+
+		files := Collect(context.Background(), testInfo, Options{Dialer: dialer})
+
+		errs := find(files, ErrorsFile)
+		for _, want := range []string{"worker: connection refused", "control-plane/crictl-ps.txt", "command not found"} {
+			if !strings.Contains(errs, want) {
+				t.Errorf("errors.txt missing %q:\n%s", want, errs)
+			}
+		}
+
+		// The partial output is kept, a failure without output is not.
+		got := paths(files)
+		if !slices.Contains(got, "control-plane/crictl-ps.txt") || slices.Contains(got, "control-plane/kubeadm-config.yaml") {
+			t.Errorf("files = %v", got)
+		}
+	})
+}
+
+func TestWrite(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	files := []File{
+		{Path: ClusterInfoFile, Data: []byte("{}\n")},
+		{Path: "control-plane/kubelet.log", Data: []byte("log\n")},
+		{Path: "worker/kubelet.log", Data: []byte("log\n")},
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, files, now); err != nil {
+		t.Fatal(err)
+	}
+
+	gz, err := gzip.NewReader(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tr := tar.NewReader(gz)
+
+	var names []string
+
+	for {
+		h, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		names = append(names, h.Name)
+	}
+
+	want := []string{
+		"k8s-lab-support-2025-03-01T120000Z/",
+		"k8s-lab-support-2025-03-01T120000Z/control-plane/",
+		"k8s-lab-support-2025-03-01T120000Z/worker/",
+		"k8s-lab-support-2025-03-01T120000Z/cluster-info.json",
+		"k8s-lab-support-2025-03-01T120000Z/control-plane/kubelet.log",
+		"k8s-lab-support-2025-03-01T120000Z/worker/kubelet.log",
+	}
+	if !slices.Equal(names, want) {
+		t.Errorf("archive = %v, want %v", names, want)
+	}
+}
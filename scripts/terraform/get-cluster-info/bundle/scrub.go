@@ -0,0 +1,77 @@
+package bundle
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/k8s-lab/get-cluster-info/clusterinfo"
+)
+
+// =============================================================================
+// Scrubbing
+// =============================================================================
+
+// secretPatterns match the secrets that show up in the collected logs; the
+// first group, when present, is kept.
+var secretPatterns = []*regexp.Regexp{
+	// PEM private keys (cloud-init, kubeadm).
+	regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`),
+	// kubeadm bootstrap tokens (kubeadm join --token, kubeadm token create).
+	regexp.MustCompile(`\b[a-z0-9]{6}\.[a-z0-9]{16}\b`),
+	// kubeadm certificate keys.
+	regexp.MustCompile(`(--certificate-key[= ])[0-9a-f]{64}`),
+	// Base64 keys and certificates of kubeconfig files.
+	regexp.MustCompile(`((?:client-key|client-certificate|certificate-authority)-data:\s*)\S+`),
+	// Scaleway access keys.
+	regexp.MustCompile(`\bSCW[A-Z0-9]{17}\b`),
+	// key: value or key=value secrets.
+	regexp.MustCompile(`(?i)((?:password|passwd|secret|secret_key|access_key|api_key|token)["']?\s*[:=]\s*)` +
+		`["']?[^\s"',]+["']?`),
+}
+
+// Scrubber removes the secrets and the public IPs of the nodes from the
+// collected files.
+type Scrubber struct {
+	ips         *regexp.Regexp // nil without public IPs
+	placeholder map[string]string
+}
+
+// NewScrubber returns a scrubber replacing the public IPs of the nodes of info
+// like ClusterInfo.Redacted.
+func NewScrubber(info *clusterinfo.ClusterInfo) Scrubber {
+	s := Scrubber{placeholder: map[string]string{}}
+
+	var ips []string
+
+	for _, n := range info.AllNodes() {
+		if n.PublicIP != "" {
+			ips = append(ips, regexp.QuoteMeta(n.PublicIP))
+			s.placeholder[n.PublicIP] = clusterinfo.RedactedPublicIP(n.Name)
+		}
+	}
+
+	// Whole addresses only: 203.0.113.1 must not match 203.0.113.10.
+	if len(ips) > 0 {
+		s.ips = regexp.MustCompile(`\b(?:` + strings.Join(ips, "|") + `)\b`)
+	}
+
+	return s
+}
+
+// Scrub returns data without secrets.
+func (s Scrubber) Scrub(data []byte) []byte {
+	for _, re := range secretPatterns {
+		repl := []byte(clusterinfo.Redacted)
+		if re.NumSubexp() > 0 {
+			repl = []byte("${1}" + clusterinfo.Redacted)
+		}
+
+		data = re.ReplaceAll(data, repl)
+	}
+
+	if s.ips == nil {
+		return data
+	}
+
+	return s.ips.ReplaceAllFunc(data, func(ip []byte) []byte { return []byte(s.placeholder[string(ip)]) })
+}
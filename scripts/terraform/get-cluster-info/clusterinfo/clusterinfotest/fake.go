@@ -0,0 +1,341 @@
+// Package clusterinfotest provides a fake Terraform client and fixture states,
+// to test code built on clusterinfo without terraform installed.
+//
+//	fake := clusterinfotest.MustFixture(t, clusterinfotest.FixtureDeployed)
+//	src, err := clusterinfo.NewTerraformSource(ctx, clusterinfo.TerraformConfig{
+//		Dir:         t.TempDir(),
+//		Client:      fake,
+//		Credentials: clusterinfo.StaticCredentials{AccessKey: "a", SecretKey: "s"},
+//	})
+package clusterinfotest
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-exec/tfexec"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/k8s-lab/get-cluster-info/clusterinfo"
+)
+
+// =============================================================================
+// Fixtures
+// =============================================================================
+
+// Fixture states, in the `terraform show -json` format.
+const (
+	// FixtureDeployed is a fully applied stack: both nodes, their IPs and the SSH key.
+	FixtureDeployed = "deployed"
+	// FixtureNotDeployed is an empty state (never applied, or destroyed).
+	FixtureNotDeployed = "not_deployed"
+	// FixtureHalfApplied is an interrupted apply: worker_public_ip and the SSH key are missing.
+	FixtureHalfApplied = "half_applied"
+)
+
+// Fixture plans, in the `terraform show -json <planfile>` format.
+const (
+	// FixturePlanChanges replaces the control plane (new image), destroys the
+	// worker and its IP, creates worker-2 and its IP, and updates the firewall rules.
+	FixturePlanChanges = "plan_changes"
+	// FixturePlanDrift is a refresh-only plan: the worker was resized and tagged
+	// in the console, and its IP deleted.
+	FixturePlanDrift = "plan_drift"
+)
+
+//go:embed fixtures/*.json
+var fixtures embed.FS
+
+// LoadFixture parses the named fixture state.
+func LoadFixture(name string) (*tfjson.State, error) {
+	data, err := fixtures.ReadFile("fixtures/" + name + ".json")
+	if err != nil {
+		return nil, fmt.Errorf("unknown fixture %q: %w", name, err)
+	}
+
+	var state tfjson.State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse fixture %q: %w", name, err)
+	}
+
+	return &state, nil
+}
+
+// LoadPlanFixture parses the named fixture plan.
+func LoadPlanFixture(name string) (*tfjson.Plan, error) {
+	data, err := fixtures.ReadFile("fixtures/" + name + ".json")
+	if err != nil {
+		return nil, fmt.Errorf("unknown fixture %q: %w", name, err)
+	}
+
+	var plan tfjson.Plan
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return nil, fmt.Errorf("failed to parse fixture %q: %w", name, err)
+	}
+
+	return &plan, nil
+}
+
+// MustFixture returns a FakeTerraform serving the named fixture, failing the test on error.
+func MustFixture(tb testing.TB, name string) *FakeTerraform {
+	tb.Helper()
+
+	state, err := LoadFixture(name)
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	return NewFake(state)
+}
+
+// =============================================================================
+// Fake client
+// =============================================================================
+
+// FakeTerraform implements clusterinfo.TerraformClient from an in-memory state.
+// Set the *Err fields to simulate failures; Calls records the commands run.
+type FakeTerraform struct {
+	State     *tfjson.State
+	Workspace string
+	// Planned is returned by ShowPlanFile (nil: a plan without changes).
+	Planned *tfjson.Plan
+
+	InitErr      error
+	OutputErr    error
+	ShowErr      error
+	PlanErr      error
+	DestroyErr   error
+	WorkspaceErr error
+
+	mu    sync.Mutex
+	env   map[string]string
+	calls []string
+}
+
+var _ clusterinfo.TerraformClient = (*FakeTerraform)(nil)
+
+// NewFake returns a FakeTerraform serving state in the "default" workspace.
+func NewFake(state *tfjson.State) *FakeTerraform {
+	return &FakeTerraform{State: state, Workspace: "default"}
+}
+
+// SetEnv implements clusterinfo.TerraformClient.
+func (f *FakeTerraform) SetEnv(env map[string]string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.env = env
+
+	return nil
+}
+
+// Init implements clusterinfo.TerraformClient.
+func (f *FakeTerraform) Init(_ context.Context, _ ...tfexec.InitOption) error {
+	f.record("init")
+
+	return f.InitErr
+}
+
+// Output implements clusterinfo.TerraformClient, converting the state outputs
+// the way `terraform output -json` does.
+func (f *FakeTerraform) Output(_ context.Context, _ ...tfexec.OutputOption) (map[string]tfexec.OutputMeta, error) {
+	f.record("output")
+
+	if f.OutputErr != nil {
+		return nil, f.OutputErr
+	}
+
+	return StateOutputs(f.State)
+}
+
+// Show implements clusterinfo.TerraformClient.
+func (f *FakeTerraform) Show(_ context.Context, _ ...tfexec.ShowOption) (*tfjson.State, error) {
+	f.record("show")
+
+	if f.ShowErr != nil {
+		return nil, f.ShowErr
+	}
+
+	return f.State, nil
+}
+
+// Plan implements clusterinfo.TerraformClient. It reports changes when Planned has
+// drift, or resource changes other than no-op and read. A refresh-only plan is
+// recorded as "plan -refresh-only".
+func (f *FakeTerraform) Plan(_ context.Context, opts ...tfexec.PlanOption) (bool, error) {
+	call := "plan"
+
+	for _, o := range opts {
+		if _, ok := o.(*tfexec.RefreshOnlyOption); ok {
+			call = "plan -refresh-only"
+		}
+	}
+
+	f.record(call)
+
+	if f.PlanErr != nil {
+		return false, f.PlanErr
+	}
+
+	if len(f.plan().ResourceDrift) > 0 {
+		return true, nil
+	}
+
+	for _, rc := range f.plan().ResourceChanges {
+		if rc.Change != nil && !rc.Change.Actions.NoOp() && !rc.Change.Actions.Read() {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// ShowPlanFile implements clusterinfo.TerraformClient.
+func (f *FakeTerraform) ShowPlanFile(_ context.Context, _ string, _ ...tfexec.ShowOption) (*tfjson.Plan, error) {
+	f.record("show plan")
+
+	if f.ShowErr != nil {
+		return nil, f.ShowErr
+	}
+
+	return f.plan(), nil
+}
+
+func (f *FakeTerraform) plan() *tfjson.Plan {
+	if f.Planned == nil {
+		return &tfjson.Plan{FormatVersion: "1.2"}
+	}
+
+	return f.Planned
+}
+
+// Destroy implements clusterinfo.TerraformClient. On success the state becomes empty.
+func (f *FakeTerraform) Destroy(_ context.Context, _ ...tfexec.DestroyOption) error {
+	f.record("destroy")
+
+	if f.DestroyErr != nil {
+		return f.DestroyErr
+	}
+
+	f.State = &tfjson.State{FormatVersion: "1.0"}
+
+	return nil
+}
+
+// WorkspaceShow implements clusterinfo.TerraformClient.
+func (f *FakeTerraform) WorkspaceShow(_ context.Context) (string, error) {
+	f.record("workspace show")
+
+	return f.Workspace, f.WorkspaceErr
+}
+
+// Env returns the environment passed to SetEnv.
+func (f *FakeTerraform) Env() map[string]string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.env
+}
+
+// Calls returns the commands run so far, e.g. ["init", "output"].
+func (f *FakeTerraform) Calls() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]string(nil), f.calls...)
+}
+
+func (f *FakeTerraform) record(call string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, call)
+}
+
+// StateOutputs converts the root module outputs of a state to tfexec.OutputMeta.
+func StateOutputs(state *tfjson.State) (map[string]tfexec.OutputMeta, error) {
+	outputs := map[string]tfexec.OutputMeta{}
+	if state == nil || state.Values == nil {
+		return outputs, nil
+	}
+
+	for name, out := range state.Values.Outputs {
+		value, err := json.Marshal(out.Value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode output %q: %w", name, err)
+		}
+
+		typ, err := out.Type.MarshalJSON()
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode type of output %q: %w", name, err)
+		}
+
+		outputs[name] = tfexec.OutputMeta{Sensitive: out.Sensitive, Type: typ, Value: value}
+	}
+
+	return outputs, nil
+}
+
+// =============================================================================
+// Lifetime store
+// =============================================================================
+
+// MemoryLifetimeStore is an in-memory clusterinfo.LifetimeStore. Err, if set, is
+// returned by every method.
+type MemoryLifetimeStore struct {
+	Value *clusterinfo.Lifetime
+	Err   error
+
+	mu sync.Mutex
+}
+
+var _ clusterinfo.LifetimeStore = (*MemoryLifetimeStore)(nil)
+
+// Lifetime implements clusterinfo.LifetimeStore.
+func (m *MemoryLifetimeStore) Lifetime(_ context.Context) (*clusterinfo.Lifetime, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return nil, m.Err
+	}
+
+	if m.Value == nil {
+		return nil, clusterinfo.ErrNoLifetime
+	}
+
+	l := *m.Value
+
+	return &l, nil
+}
+
+// SetLifetime implements clusterinfo.LifetimeStore.
+func (m *MemoryLifetimeStore) SetLifetime(_ context.Context, l clusterinfo.Lifetime) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return m.Err
+	}
+
+	m.Value = &l
+
+	return nil
+}
+
+// DeleteLifetime implements clusterinfo.LifetimeStore.
+func (m *MemoryLifetimeStore) DeleteLifetime(_ context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return m.Err
+	}
+
+	m.Value = nil
+
+	return nil
+}
@@ -0,0 +1,224 @@
+package clusterinfotest
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/k8s-lab/get-cluster-info/clusterinfo"
+)
+
+// =============================================================================
+// Fixture tests
+// =============================================================================
+
+func TestFixtures(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		wantOutputs []string
+	}{
+		{
+			name: FixtureDeployed,
+			wantOutputs: []string{
+				"control_plane_private_ip", "control_plane_public_ip",
+				"nodes", "ssh_private_key", "worker_private_ip", "worker_public_ip",
+			},
+		},
+		{
+			name:        FixtureNotDeployed,
+			wantOutputs: nil,
+		},
+		{
+			name:        FixtureHalfApplied,
+			wantOutputs: []string{"control_plane_private_ip", "control_plane_public_ip", "worker_private_ip"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			outputs, err := MustFixture(t, tt.name).Output(context.Background())
+			if err != nil {
+				t.Fatalf("Output() unexpected error = %v", err)
+			}
+
+			var names []string
+			for name := range outputs {
+				names = append(names, name)
+			}
+
+			slices.Sort(names)
+
+			if !slices.Equal(names, tt.wantOutputs) {
+				t.Errorf("outputs = %v, want %v", names, tt.wantOutputs)
+			}
+		})
+	}
+}
+
+func TestLoadFixtureUnknown(t *testing.T) {
+	t.Parallel()
+
+	if _, err := LoadFixture("nope"); err == nil {
+		t.Error("LoadFixture() expected error, got nil")
+	}
+}
+
+// =============================================================================
+// FakeTerraform tests
+// =============================================================================
+
+func TestFakeTerraformOutputMeta(t *testing.T) {
+	t.Parallel()
+
+	outputs, err := MustFixture(t, FixtureDeployed).Output(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key := outputs["ssh_private_key"]
+	if !key.Sensitive {
+		t.Error("ssh_private_key should be sensitive")
+	}
+
+	if got := string(outputs["control_plane_public_ip"].Value); got != `"203.0.113.10"` {
+		t.Errorf("control_plane_public_ip = %s, want %q", got, "203.0.113.10")
+	}
+
+	if got := string(outputs["control_plane_public_ip"].Type); got != `"string"` {
+		t.Errorf("type = %s, want %q", got, "string")
+	}
+}
+
+func TestFakeTerraformWithSource(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	fake := MustFixture(t, FixtureDeployed)
+
+	src, err := clusterinfo.NewTerraformSource(ctx, clusterinfo.TerraformConfig{
+		Dir:         t.TempDir(),
+		Client:      fake,
+		Credentials: clusterinfo.StaticCredentials{AccessKey: "AKIA", SecretKey: "secret"},
+	})
+	if err != nil {
+		t.Fatalf("NewTerraformSource() unexpected error = %v", err)
+	}
+
+	if _, err := src.Outputs(ctx); err != nil {
+		t.Fatalf("Outputs() unexpected error = %v", err)
+	}
+
+	if got, want := fake.Calls(), []string{"init", "output"}; !slices.Equal(got, want) {
+		t.Errorf("Calls() = %v, want %v", got, want)
+	}
+
+	if got := fake.Env()[clusterinfo.EnvAccessKey]; got != "AKIA" {
+		t.Errorf("env %s = %q, want %q", clusterinfo.EnvAccessKey, got, "AKIA")
+	}
+}
+
+func TestFakeTerraformErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	errBoom := errors.New("boom")
+
+	fake := MustFixture(t, FixtureDeployed)
+	fake.InitErr = errBoom
+
+	_, err := clusterinfo.NewTerraformSource(ctx, clusterinfo.TerraformConfig{
+		Dir:         t.TempDir(),
+		Client:      fake,
+		Credentials: clusterinfo.StaticCredentials{AccessKey: "a", SecretKey: "s"},
+	})
+	if !errors.Is(err, errBoom) {
+		t.Errorf("NewTerraformSource() error = %v, want %v", err, errBoom)
+	}
+
+	fake.OutputErr = errBoom
+	if _, err := fake.Output(ctx); !errors.Is(err, errBoom) {
+		t.Errorf("Output() error = %v, want %v", err, errBoom)
+	}
+}
+
+func TestFakeTerraformPlan(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	fake := MustFixture(t, FixtureDeployed)
+
+	// No planned changes by default.
+	if changes, err := fake.Plan(ctx); err != nil || changes {
+		t.Errorf("Plan() = %v, %v, want no changes", changes, err)
+	}
+
+	plan, err := LoadPlanFixture(FixturePlanChanges)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fake.Planned = plan
+
+	if changes, err := fake.Plan(ctx); err != nil || !changes {
+		t.Errorf("Plan() = %v, %v, want changes", changes, err)
+	}
+
+	got, err := fake.ShowPlanFile(ctx, "tfplan")
+	if err != nil || got != plan {
+		t.Errorf("ShowPlanFile() = %v, %v, want the planned fixture", got, err)
+	}
+
+	if want := []string{"plan", "plan", "show plan"}; !slices.Equal(fake.Calls(), want) {
+		t.Errorf("Calls() = %v, want %v", fake.Calls(), want)
+	}
+}
+
+func TestFakeTerraformDestroy(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	fake := MustFixture(t, FixtureDeployed)
+
+	if err := fake.Destroy(ctx); err != nil {
+		t.Fatalf("Destroy() error = %v", err)
+	}
+
+	state, err := fake.Show(ctx)
+	if err != nil || state.Values != nil {
+		t.Errorf("Show() after Destroy() = %+v, %v, want an empty state", state, err)
+	}
+
+	if want := []string{"destroy", "show"}; !slices.Equal(fake.Calls(), want) {
+		t.Errorf("Calls() = %v, want %v", fake.Calls(), want)
+	}
+}
+
+func TestMemoryLifetimeStore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := &MemoryLifetimeStore{}
+
+	if _, err := store.Lifetime(ctx); !errors.Is(err, clusterinfo.ErrNoLifetime) {
+		t.Errorf("Lifetime() error = %v, want %v", err, clusterinfo.ErrNoLifetime)
+	}
+
+	l := clusterinfo.Lifetime{ExpiresAt: time.Date(2025, 3, 1, 17, 0, 0, 0, time.UTC)}
+	if err := store.SetLifetime(ctx, l); err != nil {
+		t.Fatalf("SetLifetime() error = %v", err)
+	}
+
+	if got, err := store.Lifetime(ctx); err != nil || !got.ExpiresAt.Equal(l.ExpiresAt) {
+		t.Errorf("Lifetime() = %+v, %v, want %+v", got, err, l)
+	}
+
+	if err := store.DeleteLifetime(ctx); err != nil || store.Value != nil {
+		t.Errorf("DeleteLifetime() error = %v, value = %+v", err, store.Value)
+	}
+}
@@ -0,0 +1,110 @@
+package clusterinfo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// =============================================================================
+// Credentials
+// =============================================================================
+
+// Environment variables read by EnvCredentials (and by the Terraform S3 backend).
+const (
+	EnvAccessKey = "AWS_ACCESS_KEY_ID"
+	EnvSecretKey = "AWS_SECRET_ACCESS_KEY" //nolint:gosec // variable name, not a secret
+)
+
+// ErrCredentialsIncomplete is returned when the access key or the secret key is empty.
+var ErrCredentialsIncomplete = errors.New("access_key or secret_key missing")
+
+// Credentials holds S3 credentials from the backend file (YAML or JSON).
+// Tags use snake_case to match the expected file format.
+type Credentials struct {
+	AccessKey string `json:"access_key" yaml:"access_key"` //nolint:tagliatelle
+	SecretKey string `json:"secret_key" yaml:"secret_key"` //nolint:tagliatelle
+}
+
+// CredentialProvider returns the S3 backend credentials.
+type CredentialProvider interface {
+	Credentials(ctx context.Context) (*Credentials, error)
+}
+
+// FileCredentials reads credentials from a backend file (YAML if .yaml/.yml, JSON otherwise).
+type FileCredentials struct {
+	Path string
+}
+
+// Credentials implements CredentialProvider.
+func (f FileCredentials) Credentials(_ context.Context) (*Credentials, error) {
+	data, err := os.ReadFile(f.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read credentials file: %w", err)
+	}
+
+	var creds Credentials
+
+	ext := strings.ToLower(filepath.Ext(f.Path))
+	switch ext {
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(data, &creds); err != nil {
+			return nil, fmt.Errorf("failed to parse YAML file: %w", err)
+		}
+	default:
+		if err := json.Unmarshal(data, &creds); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON file: %w", err)
+		}
+	}
+
+	if creds.AccessKey == "" || creds.SecretKey == "" {
+		return nil, fmt.Errorf("%w in %s", ErrCredentialsIncomplete, f.Path)
+	}
+
+	return &creds, nil
+}
+
+// EnvCredentials reads credentials from AWS_ACCESS_KEY_ID / AWS_SECRET_ACCESS_KEY.
+type EnvCredentials struct{}
+
+// Credentials implements CredentialProvider.
+func (EnvCredentials) Credentials(_ context.Context) (*Credentials, error) {
+	creds := &Credentials{
+		AccessKey: os.Getenv(EnvAccessKey),
+		SecretKey: os.Getenv(EnvSecretKey),
+	}
+
+	if creds.AccessKey == "" || creds.SecretKey == "" {
+		return nil, fmt.Errorf("%w in %s / %s", ErrCredentialsIncomplete, EnvAccessKey, EnvSecretKey)
+	}
+
+	return creds, nil
+}
+
+// StaticCredentials returns fixed credentials (e.g. already loaded by the caller).
+type StaticCredentials Credentials
+
+// Credentials implements CredentialProvider.
+func (s StaticCredentials) Credentials(_ context.Context) (*Credentials, error) {
+	creds := Credentials(s)
+
+	return &creds, nil
+}
+
+// ResolveCredentialsFile returns the path to the first existing backend file (YAML then JSON).
+func ResolveCredentialsFile(terraformDir string) string {
+	for _, name := range []string{"backend.yaml", "backend.yml", "backend.json"} {
+		p := filepath.Join(terraformDir, name)
+		if _, err := os.Stat(p); err == nil {
+			return p
+		}
+	}
+
+	return filepath.Join(terraformDir, "backend.yaml")
+}
@@ -0,0 +1,125 @@
+package clusterinfo
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// =============================================================================
+// Credential providers tests
+// =============================================================================
+
+func TestFileCredentials(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		fileName    string
+		fileContent string
+		wantAccess  string
+		wantErrIs   error
+		wantErr     bool
+	}{
+		{
+			name:        "YAML file",
+			fileName:    "backend.yaml",
+			fileContent: "access_key: AKIA\nsecret_key: secret\n",
+			wantAccess:  "AKIA",
+		},
+		{
+			name:        "JSON file",
+			fileName:    "backend.json",
+			fileContent: `{"access_key": "AKIA", "secret_key": "secret"}`,
+			wantAccess:  "AKIA",
+		},
+		{
+			name:        "incomplete credentials",
+			fileName:    "backend.yml",
+			fileContent: "access_key: AKIA\n",
+			wantErrIs:   ErrCredentialsIncomplete,
+			wantErr:     true,
+		},
+		{
+			name:        "invalid YAML",
+			fileName:    "backend.yaml",
+			fileContent: "access_key: [",
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), tt.fileName)
+			must(t, os.WriteFile(path, []byte(tt.fileContent), 0o600))
+
+			creds, err := FileCredentials{Path: path}.Credentials(context.Background())
+
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Credentials() expected error, got nil")
+				}
+
+				if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+					t.Errorf("Credentials() error = %v, want %v", err, tt.wantErrIs)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Credentials() unexpected error = %v", err)
+			}
+
+			if creds.AccessKey != tt.wantAccess {
+				t.Errorf("AccessKey = %q, want %q", creds.AccessKey, tt.wantAccess)
+			}
+		})
+	}
+}
+
+func TestEnvCredentials(t *testing.T) {
+	// Not parallel - modifies environment
+
+	t.Setenv(EnvAccessKey, "AKIA")
+	t.Setenv(EnvSecretKey, "")
+
+	if _, err := (EnvCredentials{}).Credentials(context.Background()); !errors.Is(err, ErrCredentialsIncomplete) {
+		t.Errorf("Credentials() error = %v, want %v", err, ErrCredentialsIncomplete)
+	}
+
+	t.Setenv(EnvSecretKey, "secret")
+
+	creds, err := (EnvCredentials{}).Credentials(context.Background())
+	if err != nil || creds.AccessKey != "AKIA" || creds.SecretKey != "secret" {
+		t.Errorf("Credentials() = %+v, %v", creds, err)
+	}
+}
+
+func TestStaticCredentials(t *testing.T) {
+	t.Parallel()
+
+	creds, err := StaticCredentials{AccessKey: "a", SecretKey: "s"}.Credentials(context.Background())
+	if err != nil || creds.AccessKey != "a" || creds.SecretKey != "s" {
+		t.Errorf("Credentials() = %+v, %v", creds, err)
+	}
+}
+
+func TestResolveCredentialsFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	if got := ResolveCredentialsFile(dir); got != filepath.Join(dir, "backend.yaml") {
+		t.Errorf("ResolveCredentialsFile() without file = %q, want backend.yaml default", got)
+	}
+
+	must(t, os.WriteFile(filepath.Join(dir, "backend.json"), []byte(`{}`), 0o600))
+
+	if got := ResolveCredentialsFile(dir); got != filepath.Join(dir, "backend.json") {
+		t.Errorf("ResolveCredentialsFile() = %q, want backend.json", got)
+	}
+}
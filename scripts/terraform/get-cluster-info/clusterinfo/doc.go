@@ -0,0 +1,19 @@
+// Package clusterinfo reads K8s-Lab cluster information from a Terraform state.
+//
+// It is the library behind the get-cluster-info command, and can be embedded
+// by other Go programs (operators, test harnesses):
+//
+//	info, err := clusterinfo.Load(ctx, clusterinfo.Options{
+//		TerraformDir: "/path/to/k8s-lab/terraform",
+//	})
+//
+// Every step is pluggable through Options:
+//   - Source: where the Terraform outputs come from (default: TerraformSource, using tfexec)
+//   - Credentials: S3 backend credentials used by the default source (default: FileCredentials)
+//   - Logger: progress messages (default: silent)
+//
+// TerraformConfig.Client accepts any TerraformClient; the clusterinfotest package
+// provides a fake one and fixture states for tests that run without terraform.
+//
+// Rendering (summary box, JSON, GitHub Actions) lives in the render package.
+package clusterinfo
@@ -0,0 +1,375 @@
+package clusterinfo
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+// =============================================================================
+// Init detection
+// =============================================================================
+//
+// `terraform init` pulls providers and configures the S3 backend, which takes
+// several seconds. It is only needed when the data dir (.terraform) does not
+// match the configuration:
+//   - backend: the static attributes of the backend block differ from the ones
+//     recorded in <data dir>/terraform.tfstate (compared through a hash)
+//   - providers: a required provider is missing from .terraform.lock.hcl, or
+//     the locked version is not installed for this platform
+//   - modules: a module block has not been installed
+
+// EnvDataDir is the variable Terraform uses to relocate the .terraform directory.
+const EnvDataDir = "TF_DATA_DIR"
+
+const (
+	lockFileName       = ".terraform.lock.hcl"
+	defaultRegistry    = "registry.terraform.io"
+	backendStateFile   = "terraform.tfstate"
+	modulesManifest    = "modules/modules.json"
+	reasonUpToDate     = "backend, providers and modules up to date"
+	providerPathFormat = "providers/%s/%s/%s_%s"
+)
+
+// InitStatus tells whether `terraform init` is needed, and why.
+type InitStatus struct {
+	Needed bool
+	Reason string
+}
+
+// DataDir returns the Terraform data dir of dir: $TF_DATA_DIR (relative to dir) or dir/.terraform.
+func DataDir(dir string) string {
+	dataDir := os.Getenv(EnvDataDir)
+	if dataDir == "" {
+		return filepath.Join(dir, ".terraform")
+	}
+
+	if !filepath.IsAbs(dataDir) {
+		dataDir = filepath.Join(dir, dataDir)
+	}
+
+	return dataDir
+}
+
+// CheckInit compares the data dir with the configuration of dir.
+func CheckInit(dir, dataDir string) InitStatus {
+	if _, err := os.Stat(dataDir); err != nil {
+		return InitStatus{Needed: true, Reason: filepath.Base(dataDir) + " directory not found"}
+	}
+
+	checks := []func(dir, dataDir string) (string, error){
+		checkBackend,
+		checkProviders,
+		checkModules,
+	}
+
+	for _, check := range checks {
+		reason, err := check(dir, dataDir)
+		if err != nil {
+			return InitStatus{Needed: true, Reason: err.Error()}
+		}
+
+		if reason != "" {
+			return InitStatus{Needed: true, Reason: reason}
+		}
+	}
+
+	return InitStatus{Reason: reasonUpToDate}
+}
+
+// -----------------------------------------------------------------------------
+// Backend
+// -----------------------------------------------------------------------------
+
+// backendState is the part of <data dir>/terraform.tfstate written by init.
+type backendState struct {
+	Backend *struct {
+		Type   string         `json:"type"`
+		Config map[string]any `json:"config"`
+	} `json:"backend"`
+}
+
+func checkBackend(dir, dataDir string) (string, error) {
+	wantType, wantConfig, err := loadBackendConfig(dir)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := os.ReadFile(filepath.Join(dataDir, backendStateFile)) //nolint:gosec // terraform data dir
+	if err != nil {
+		if wantType == "" {
+			return "", nil // local state, nothing recorded
+		}
+
+		return "backend not initialized", nil
+	}
+
+	var state backendState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return "", fmt.Errorf("failed to parse %s: %w", backendStateFile, err)
+	}
+
+	if state.Backend == nil || state.Backend.Type != wantType {
+		return "backend type changed", nil
+	}
+
+	// Only the attributes set in the configuration are compared: the recorded config also
+	// holds schema defaults (null) and -backend-config overrides (e.g. credentials).
+	recorded := map[string]any{}
+	for name := range wantConfig {
+		recorded[name] = pruneNulls(state.Backend.Config[name])
+	}
+
+	if configHash(recorded) != configHash(wantConfig) {
+		return "backend configuration changed", nil
+	}
+
+	return "", nil
+}
+
+// loadBackendConfig returns the type and static attributes of the backend block, if any.
+func loadBackendConfig(dir string) (string, map[string]any, error) {
+	var (
+		backendType string
+		config      = map[string]any{}
+		convErr     error
+	)
+
+	err := forEachConfigBlock(dir, "terraform", func(block *hclsyntax.Block) {
+		for _, inner := range block.Body.Blocks {
+			if inner.Type != "backend" || len(inner.Labels) != 1 {
+				continue
+			}
+
+			backendType = inner.Labels[0]
+
+			for name, attr := range inner.Body.Attributes {
+				val, diags := attr.Expr.Value(nil)
+				if diags.HasErrors() {
+					continue
+				}
+
+				data, err := ctyjson.Marshal(val, val.Type())
+				if err != nil {
+					convErr = err
+
+					continue
+				}
+
+				var v any
+				if err := json.Unmarshal(data, &v); err != nil {
+					convErr = err
+
+					continue
+				}
+
+				config[name] = v
+			}
+		}
+	})
+	if err == nil {
+		err = convErr
+	}
+
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read backend configuration: %w", err)
+	}
+
+	return backendType, config, nil
+}
+
+// configHash hashes a decoded JSON value. encoding/json sorts map keys, so the hash is stable.
+func configHash(v any) string {
+	data, _ := json.Marshal(v)
+
+	return fmt.Sprintf("%x", sha256.Sum256(data))
+}
+
+// pruneNulls removes null map entries, recursively.
+func pruneNulls(v any) any {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return v
+	}
+
+	pruned := map[string]any{}
+
+	for k, val := range m {
+		if val != nil {
+			pruned[k] = pruneNulls(val)
+		}
+	}
+
+	return pruned
+}
+
+// -----------------------------------------------------------------------------
+// Providers
+// -----------------------------------------------------------------------------
+
+func checkProviders(dir, dataDir string) (string, error) {
+	required, err := loadRequiredProviders(dir)
+	if err != nil {
+		return "", err
+	}
+
+	lockFile := filepath.Join(dir, lockFileName)
+	if _, err := os.Stat(lockFile); err != nil {
+		if len(required) == 0 {
+			return "", nil
+		}
+
+		return "dependency lock file not found", nil
+	}
+
+	body, err := parseHCLFile(lockFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", lockFileName, err)
+	}
+
+	locked := map[string]string{}
+
+	for _, block := range body.Blocks {
+		if block.Type == "provider" && len(block.Labels) == 1 {
+			locked[strings.ToLower(block.Labels[0])] = staticStringAttribute(block.Body, "version")
+		}
+	}
+
+	for _, source := range required {
+		if _, ok := locked[source]; !ok {
+			return fmt.Sprintf("provider %s not in %s", source, lockFileName), nil
+		}
+	}
+
+	for source, version := range locked {
+		path := filepath.Join(dataDir, fmt.Sprintf(providerPathFormat, source, version, runtime.GOOS, runtime.GOARCH))
+		if _, err := os.Stat(path); err != nil {
+			return fmt.Sprintf("provider %s %s not installed", source, version), nil
+		}
+	}
+
+	return "", nil
+}
+
+// loadRequiredProviders returns the fully qualified sources of the required_providers entries.
+func loadRequiredProviders(dir string) ([]string, error) {
+	var sources []string
+
+	err := forEachConfigBlock(dir, "terraform", func(block *hclsyntax.Block) {
+		for _, inner := range block.Body.Blocks {
+			if inner.Type != "required_providers" {
+				continue
+			}
+
+			for name, attr := range inner.Body.Attributes {
+				source := "hashicorp/" + name
+
+				val, diags := attr.Expr.Value(nil)
+				if !diags.HasErrors() && val.Type().IsObjectType() && val.Type().HasAttribute("source") {
+					if s := val.GetAttr("source"); !s.IsNull() && s.Type().Equals(cty.String) {
+						source = s.AsString()
+					}
+				}
+
+				sources = append(sources, qualifyProviderSource(source))
+			}
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read required providers: %w", err)
+	}
+
+	return sources, nil
+}
+
+// qualifyProviderSource turns "scaleway/scaleway" into "registry.terraform.io/scaleway/scaleway".
+func qualifyProviderSource(source string) string {
+	source = strings.ToLower(source)
+	if strings.Count(source, "/") == 1 {
+		return defaultRegistry + "/" + source
+	}
+
+	return source
+}
+
+// -----------------------------------------------------------------------------
+// Modules
+// -----------------------------------------------------------------------------
+
+// modulesManifestFile is <data dir>/modules/modules.json.
+type modulesManifestFile struct {
+	Modules []struct {
+		Key string `json:"Key"` //nolint:tagliatelle // Terraform format
+	} `json:"Modules"` //nolint:tagliatelle // Terraform format
+}
+
+func checkModules(dir, dataDir string) (string, error) {
+	var modules []string
+
+	err := forEachConfigBlock(dir, "module", func(block *hclsyntax.Block) {
+		if len(block.Labels) == 1 {
+			modules = append(modules, block.Labels[0])
+		}
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to read module blocks: %w", err)
+	}
+
+	if len(modules) == 0 {
+		return "", nil
+	}
+
+	data, err := os.ReadFile(filepath.Join(dataDir, modulesManifest)) //nolint:gosec // terraform data dir
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "modules not installed", nil
+		}
+
+		return "", err
+	}
+
+	var manifest modulesManifestFile
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return "", fmt.Errorf("failed to parse %s: %w", modulesManifest, err)
+	}
+
+	installed := map[string]bool{}
+	for _, m := range manifest.Modules {
+		installed[m.Key] = true
+	}
+
+	for _, name := range modules {
+		if !installed[name] {
+			return fmt.Sprintf("module %s not installed", name), nil
+		}
+	}
+
+	return "", nil
+}
+
+// -----------------------------------------------------------------------------
+// Read-only directories
+// -----------------------------------------------------------------------------
+
+// dirWritable reports whether files can be created in dir.
+func dirWritable(dir string) bool {
+	f, err := os.CreateTemp(dir, ".get-cluster-info-*")
+	if err != nil {
+		return false
+	}
+
+	name := f.Name()
+	_ = f.Close()
+	_ = os.Remove(name)
+
+	return true
+}
@@ -0,0 +1,212 @@
+package clusterinfo
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// =============================================================================
+// CheckInit tests
+// =============================================================================
+
+const testMainTF = `terraform {
+  required_providers {
+    scaleway = {
+      source  = "scaleway/scaleway"
+      version = "~> 2.53"
+    }
+  }
+
+  backend "s3" {
+    bucket    = "k8s-lab-terraform"
+    key       = "k8s-lab/terraform.tfstate"
+    endpoints = { s3 = "https://s3.fr-par.scw.cloud" }
+  }
+}
+`
+
+const testLockFile = `provider "registry.terraform.io/scaleway/scaleway" {
+  version     = "2.53.0"
+  constraints = "~> 2.53"
+}
+`
+
+// testBackendState is what init records: every schema attribute (null if unset)
+// and the -backend-config overrides.
+const testBackendState = `{
+  "version": 3,
+  "backend": {
+    "type": "s3",
+    "config": {
+      "access_key": "AKIA-from-backend-config",
+      "bucket": "%s",
+      "key": "k8s-lab/terraform.tfstate",
+      "endpoints": {"s3": "https://s3.fr-par.scw.cloud", "dynamodb": null},
+      "region": null
+    },
+    "hash": 1234
+  }
+}`
+
+// initializedDir creates a terraform dir whose .terraform matches testMainTF.
+func initializedDir(t *testing.T) (string, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	dataDir := filepath.Join(dir, ".terraform")
+	providerDir := filepath.Join(dataDir, "providers", "registry.terraform.io", "scaleway", "scaleway", "2.53.0",
+		runtime.GOOS+"_"+runtime.GOARCH)
+
+	must(t, os.WriteFile(filepath.Join(dir, "main.tf"), []byte(testMainTF), 0o600))
+	must(t, os.WriteFile(filepath.Join(dir, lockFileName), []byte(testLockFile), 0o600))
+	must(t, os.MkdirAll(providerDir, 0o755))
+	must(t, os.WriteFile(filepath.Join(dataDir, backendStateFile),
+		[]byte(fmt.Sprintf(testBackendState, "k8s-lab-terraform")), 0o600))
+
+	return dir, dataDir
+}
+
+func TestCheckInit(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		setup      func(t *testing.T, dir, dataDir string)
+		wantNeeded bool
+		wantReason string
+	}{
+		{
+			name:       "up to date - init skipped",
+			setup:      func(_ *testing.T, _, _ string) {},
+			wantReason: reasonUpToDate,
+		},
+		{
+			name: "no data dir - init needed",
+			setup: func(t *testing.T, _, dataDir string) {
+				t.Helper()
+				must(t, os.RemoveAll(dataDir))
+			},
+			wantNeeded: true,
+			wantReason: ".terraform directory not found",
+		},
+		{
+			name: "backend never initialized - init needed",
+			setup: func(t *testing.T, _, dataDir string) {
+				t.Helper()
+				must(t, os.Remove(filepath.Join(dataDir, backendStateFile)))
+			},
+			wantNeeded: true,
+			wantReason: "backend not initialized",
+		},
+		{
+			name: "bucket changed - init needed",
+			setup: func(t *testing.T, _, dataDir string) {
+				t.Helper()
+				must(t, os.WriteFile(filepath.Join(dataDir, backendStateFile),
+					[]byte(fmt.Sprintf(testBackendState, "old-bucket")), 0o600))
+			},
+			wantNeeded: true,
+			wantReason: "backend configuration changed",
+		},
+		{
+			name: "lock file missing - init needed",
+			setup: func(t *testing.T, dir, _ string) {
+				t.Helper()
+				must(t, os.Remove(filepath.Join(dir, lockFileName)))
+			},
+			wantNeeded: true,
+			wantReason: "dependency lock file not found",
+		},
+		{
+			name: "new required provider - init needed",
+			setup: func(t *testing.T, dir, _ string) {
+				t.Helper()
+				tf := strings.Replace(testMainTF, "required_providers {",
+					"required_providers {\n    tls = { source = \"hashicorp/tls\" }", 1)
+				must(t, os.WriteFile(filepath.Join(dir, "main.tf"), []byte(tf), 0o600))
+			},
+			wantNeeded: true,
+			wantReason: "provider registry.terraform.io/hashicorp/tls not in .terraform.lock.hcl",
+		},
+		{
+			name: "locked version not installed - init needed",
+			setup: func(t *testing.T, dir, _ string) {
+				t.Helper()
+				lock := strings.Replace(testLockFile, "2.53.0", "2.54.0", 1)
+				must(t, os.WriteFile(filepath.Join(dir, lockFileName), []byte(lock), 0o600))
+			},
+			wantNeeded: true,
+			wantReason: "provider registry.terraform.io/scaleway/scaleway 2.54.0 not installed",
+		},
+		{
+			name: "module not installed - init needed",
+			setup: func(t *testing.T, dir, _ string) {
+				t.Helper()
+				must(t, os.WriteFile(filepath.Join(dir, "modules.tf"),
+					[]byte("module \"vpc\" {\n  source = \"./vpc\"\n}\n"), 0o600))
+			},
+			wantNeeded: true,
+			wantReason: "modules not installed",
+		},
+		{
+			name: "module installed - init skipped",
+			setup: func(t *testing.T, dir, dataDir string) {
+				t.Helper()
+				must(t, os.WriteFile(filepath.Join(dir, "modules.tf"),
+					[]byte("module \"vpc\" {\n  source = \"./vpc\"\n}\n"), 0o600))
+				must(t, os.MkdirAll(filepath.Join(dataDir, "modules"), 0o755))
+				must(t, os.WriteFile(filepath.Join(dataDir, modulesManifest),
+					[]byte(`{"Modules":[{"Key":"","Source":"","Dir":"."},{"Key":"vpc","Source":"./vpc","Dir":"vpc"}]}`),
+					0o600))
+			},
+			wantReason: reasonUpToDate,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir, dataDir := initializedDir(t)
+			tt.setup(t, dir, dataDir)
+
+			got := CheckInit(dir, dataDir)
+
+			if got.Needed != tt.wantNeeded {
+				t.Errorf("Needed = %v, want %v (reason %q)", got.Needed, tt.wantNeeded, got.Reason)
+			}
+
+			if got.Reason != tt.wantReason {
+				t.Errorf("Reason = %q, want %q", got.Reason, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestDataDir(t *testing.T) {
+	// Not parallel - modifies environment
+
+	tests := []struct {
+		name string
+		env  string
+		want string
+	}{
+		{name: "default - .terraform", env: "", want: "/tf/.terraform"},
+		{name: "absolute TF_DATA_DIR", env: "/tmp/data", want: "/tmp/data"},
+		{name: "relative TF_DATA_DIR - relative to dir", env: "data", want: "/tf/data"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(EnvDataDir, tt.env)
+
+			if got := DataDir("/tf"); got != tt.want {
+				t.Errorf("DataDir() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
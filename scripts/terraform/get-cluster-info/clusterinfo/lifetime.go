@@ -0,0 +1,262 @@
+package clusterinfo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// =============================================================================
+// Lab lifetime (TTL)
+// =============================================================================
+//
+// A lab can be given a time-to-live when it is brought up. The lifetime is
+// stored in the S3 backend, in a small JSON file next to the state key
+// (<key>.ttl.json), so that every machine reading the state sees it, and the
+// reap subcommand can destroy expired labs from cron or CI.
+
+// LifetimeKeySuffix is appended to the state key to name the lifetime file.
+const LifetimeKeySuffix = ".ttl.json"
+
+// hoursPerDay converts the "d" unit of ParseTTL.
+const hoursPerDay = 24
+
+var (
+	// ErrNoLifetime is returned when no TTL was set for the lab.
+	ErrNoLifetime = errors.New("no TTL set")
+	// ErrNoS3Backend is returned when the Terraform configuration has no S3 backend.
+	ErrNoS3Backend = errors.New("no s3 backend in the terraform configuration")
+	// ErrInvalidTTL is returned by ParseTTL.
+	ErrInvalidTTL = errors.New("invalid TTL (e.g. 8h, 90m, 2d)")
+)
+
+// Lifetime is the TTL of a lab.
+// JSON tags use snake_case for consistency with Terraform outputs.
+type Lifetime struct {
+	CreatedAt time.Time `json:"created_at"`       //nolint:tagliatelle
+	ExpiresAt time.Time `json:"expires_at"`       //nolint:tagliatelle
+	SetBy     string    `json:"set_by,omitempty"` //nolint:tagliatelle
+}
+
+// NewLifetime returns a lifetime expiring ttl after now. The creation time of
+// prev is kept when the TTL is extended, but not once prev has expired: the
+// lab was destroyed (outside reap, which clears the lifetime) and re-applied.
+func NewLifetime(now time.Time, ttl time.Duration, setBy string, prev *Lifetime) Lifetime {
+	created := now
+	if prev != nil && !prev.CreatedAt.IsZero() && !prev.Expired(now) {
+		created = prev.CreatedAt
+	}
+
+	return Lifetime{CreatedAt: created.UTC(), ExpiresAt: now.Add(ttl).UTC(), SetBy: setBy}
+}
+
+// Remaining returns the time left before expiry (negative once expired).
+func (l Lifetime) Remaining(now time.Time) time.Duration {
+	return l.ExpiresAt.Sub(now)
+}
+
+// Expired reports whether the lab has outlived its TTL.
+func (l Lifetime) Expired(now time.Time) bool {
+	return !now.Before(l.ExpiresAt)
+}
+
+// ParseTTL parses a positive duration, accepting days ("2d", "1d12h") on top of time.ParseDuration units.
+func ParseTTL(s string) (time.Duration, error) {
+	var days time.Duration
+
+	if before, after, found := strings.Cut(s, "d"); found {
+		n, err := strconv.Atoi(before)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidTTL, s)
+		}
+
+		days = time.Duration(n) * hoursPerDay * time.Hour
+		s = after
+	}
+
+	var rest time.Duration
+
+	if s != "" {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %w", ErrInvalidTTL, err)
+		}
+
+		rest = d
+	}
+
+	ttl := days + rest
+	if ttl <= 0 {
+		return 0, fmt.Errorf("%w: must be positive", ErrInvalidTTL)
+	}
+
+	return ttl, nil
+}
+
+// LifetimeStore reads and writes the lifetime of a lab.
+type LifetimeStore interface {
+	// Lifetime returns ErrNoLifetime when no TTL was set.
+	Lifetime(ctx context.Context) (*Lifetime, error)
+	SetLifetime(ctx context.Context, l Lifetime) error
+	DeleteLifetime(ctx context.Context) error
+}
+
+// -----------------------------------------------------------------------------
+// S3 backend
+// -----------------------------------------------------------------------------
+
+// S3Backend is the location of the state, from the backend "s3" block.
+type S3Backend struct {
+	Bucket    string
+	Key       string
+	Region    string
+	Endpoint  string // URL, e.g. https://s3.fr-par.scw.cloud
+	PathStyle bool
+}
+
+// LoadS3Backend reads the static attributes of the backend "s3" block in dir.
+func LoadS3Backend(dir string) (S3Backend, error) {
+	backendType, cfg, err := loadBackendConfig(dir)
+	if err != nil {
+		return S3Backend{}, err
+	}
+
+	if backendType != "s3" {
+		return S3Backend{}, ErrNoS3Backend
+	}
+
+	str := func(v any) string {
+		s, _ := v.(string)
+
+		return s
+	}
+
+	b := S3Backend{
+		Bucket:   str(cfg["bucket"]),
+		Key:      str(cfg["key"]),
+		Region:   str(cfg["region"]),
+		Endpoint: str(cfg["endpoint"]), // deprecated before Terraform 1.6
+	}
+
+	if endpoints, ok := cfg["endpoints"].(map[string]any); ok && str(endpoints["s3"]) != "" {
+		b.Endpoint = str(endpoints["s3"])
+	}
+
+	b.PathStyle, _ = cfg["use_path_style"].(bool)
+
+	if b.Bucket == "" || b.Key == "" {
+		return S3Backend{}, fmt.Errorf("%w: bucket and key must be set in the backend block", ErrNoS3Backend)
+	}
+
+	return b, nil
+}
+
+// S3LifetimeStore stores the lifetime next to the state key in the S3 backend.
+type S3LifetimeStore struct {
+	client *minio.Client
+	bucket string
+	key    string
+}
+
+var _ LifetimeStore = (*S3LifetimeStore)(nil)
+
+// NewS3LifetimeStore connects to the backend bucket with the backend credentials.
+func NewS3LifetimeStore(b S3Backend, creds Credentials) (*S3LifetimeStore, error) {
+	client, err := NewS3Client(b, creds)
+	if err != nil {
+		return nil, err
+	}
+
+	return &S3LifetimeStore{client: client, bucket: b.Bucket, key: b.Key + LifetimeKeySuffix}, nil
+}
+
+// NewS3Client returns a client of the backend endpoint, with the backend credentials.
+func NewS3Client(b S3Backend, creds Credentials) (*minio.Client, error) {
+	endpoint := b.Endpoint
+	if endpoint == "" {
+		endpoint = "https://s3.amazonaws.com"
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid S3 endpoint %q", endpoint)
+	}
+
+	lookup := minio.BucketLookupAuto
+	if b.PathStyle {
+		lookup = minio.BucketLookupPath
+	}
+
+	client, err := minio.New(u.Host, &minio.Options{
+		Creds:        credentials.NewStaticV4(creds.AccessKey, creds.SecretKey, ""),
+		Secure:       u.Scheme != "http",
+		Region:       b.Region,
+		BucketLookup: lookup,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 client: %w", err)
+	}
+
+	return client, nil
+}
+
+// Lifetime implements LifetimeStore.
+func (s *S3LifetimeStore) Lifetime(ctx context.Context) (*Lifetime, error) {
+	obj, err := s.client.GetObject(ctx, s.bucket, s.key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read s3://%s/%s: %w", s.bucket, s.key, err)
+	}
+
+	defer func() { _ = obj.Close() }()
+
+	data, err := io.ReadAll(obj)
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, ErrNoLifetime
+		}
+
+		return nil, fmt.Errorf("failed to read s3://%s/%s: %w", s.bucket, s.key, err)
+	}
+
+	var l Lifetime
+	if err := json.Unmarshal(data, &l); err != nil {
+		return nil, fmt.Errorf("failed to parse s3://%s/%s: %w", s.bucket, s.key, err)
+	}
+
+	return &l, nil
+}
+
+// SetLifetime implements LifetimeStore.
+func (s *S3LifetimeStore) SetLifetime(ctx context.Context, l Lifetime) error {
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode lifetime: %w", err)
+	}
+
+	_, err = s.client.PutObject(ctx, s.bucket, s.key, bytes.NewReader(data), int64(len(data)),
+		minio.PutObjectOptions{ContentType: "application/json"})
+	if err != nil {
+		return fmt.Errorf("failed to write s3://%s/%s: %w", s.bucket, s.key, err)
+	}
+
+	return nil
+}
+
+// DeleteLifetime implements LifetimeStore.
+func (s *S3LifetimeStore) DeleteLifetime(ctx context.Context) error {
+	if err := s.client.RemoveObject(ctx, s.bucket, s.key, minio.RemoveObjectOptions{}); err != nil {
+		return fmt.Errorf("failed to delete s3://%s/%s: %w", s.bucket, s.key, err)
+	}
+
+	return nil
+}
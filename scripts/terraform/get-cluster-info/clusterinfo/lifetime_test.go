@@ -0,0 +1,176 @@
+package clusterinfo
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// =============================================================================
+// Lifetime tests
+// =============================================================================
+
+func TestParseTTL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{in: "8h", want: 8 * time.Hour},
+		{in: "90m", want: 90 * time.Minute},
+		{in: "2d", want: 48 * time.Hour},
+		{in: "1d12h", want: 36 * time.Hour},
+		{in: "", wantErr: true},
+		{in: "0h", wantErr: true},
+		{in: "-1h", wantErr: true},
+		{in: "xd", wantErr: true},
+		{in: "tomorrow", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseTTL(tt.in)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidTTL) {
+					t.Errorf("ParseTTL(%q) error = %v, want %v", tt.in, err, ErrInvalidTTL)
+				}
+
+				return
+			}
+
+			if err != nil || got != tt.want {
+				t.Errorf("ParseTTL(%q) = %v, %v, want %v", tt.in, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLifetime(t *testing.T) {
+	t.Parallel()
+
+	created := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	l := NewLifetime(created, 8*time.Hour, "alice", nil)
+
+	if !l.CreatedAt.Equal(created) || !l.ExpiresAt.Equal(created.Add(8*time.Hour)) || l.SetBy != "alice" {
+		t.Errorf("NewLifetime() = %+v", l)
+	}
+
+	if l.Expired(created.Add(7*time.Hour)) || l.Remaining(created.Add(7*time.Hour)) != time.Hour {
+		t.Errorf("lifetime expired or wrong remaining time after 7h")
+	}
+
+	if !l.Expired(created.Add(8 * time.Hour)) {
+		t.Errorf("lifetime not expired after 8h")
+	}
+
+	// Extending keeps the creation time.
+	later := created.Add(6 * time.Hour)
+	extended := NewLifetime(later, 4*time.Hour, "bob", &l)
+
+	if !extended.CreatedAt.Equal(created) || !extended.ExpiresAt.Equal(later.Add(4*time.Hour)) {
+		t.Errorf("NewLifetime() extended = %+v, want created %v", extended, created)
+	}
+
+	// Once expired, the lab was recreated: the creation time is reset.
+	recreated := created.Add(24 * time.Hour)
+	renewed := NewLifetime(recreated, 4*time.Hour, "bob", &extended)
+
+	if !renewed.CreatedAt.Equal(recreated) {
+		t.Errorf("NewLifetime() after expiry = %+v, want created %v", renewed, recreated)
+	}
+}
+
+func TestLoadS3Backend(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		mainTF  string
+		want    S3Backend
+		wantErr error
+	}{
+		{
+			name:   "endpoints block",
+			mainTF: testMainTF,
+			want: S3Backend{
+				Bucket:   "k8s-lab-terraform",
+				Key:      "k8s-lab/terraform.tfstate",
+				Endpoint: "https://s3.fr-par.scw.cloud",
+			},
+		},
+		{
+			name: "legacy endpoint, path style",
+			mainTF: `terraform {
+  backend "s3" {
+    bucket         = "lab"
+    key            = "state"
+    region         = "nl-ams"
+    endpoint       = "https://s3.nl-ams.scw.cloud"
+    use_path_style = true
+  }
+}`,
+			want: S3Backend{
+				Bucket:    "lab",
+				Key:       "state",
+				Region:    "nl-ams",
+				Endpoint:  "https://s3.nl-ams.scw.cloud",
+				PathStyle: true,
+			},
+		},
+		{
+			name:    "local backend",
+			mainTF:  `terraform {` + "\n" + `  backend "local" {}` + "\n" + `}`,
+			wantErr: ErrNoS3Backend,
+		},
+		{
+			name:    "bucket passed with -backend-config",
+			mainTF:  `terraform {` + "\n" + `  backend "s3" {}` + "\n" + `}`,
+			wantErr: ErrNoS3Backend,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			must(t, os.WriteFile(filepath.Join(dir, "main.tf"), []byte(tt.mainTF), 0o600))
+
+			got, err := LoadS3Backend(dir)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("LoadS3Backend() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("LoadS3Backend() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewS3LifetimeStore(t *testing.T) {
+	t.Parallel()
+
+	store, err := NewS3LifetimeStore(S3Backend{
+		Bucket:   "k8s-lab-terraform",
+		Key:      "k8s-lab/terraform.tfstate",
+		Endpoint: "https://s3.fr-par.scw.cloud",
+	}, Credentials{AccessKey: "AKIATEST", SecretKey: "secretTEST"})
+	if err != nil {
+		t.Fatalf("NewS3LifetimeStore() error = %v", err)
+	}
+
+	if want := "k8s-lab/terraform.tfstate" + LifetimeKeySuffix; store.key != want {
+		t.Errorf("key = %q, want %q", store.key, want)
+	}
+
+	if _, err := NewS3LifetimeStore(S3Backend{Endpoint: "not a url"}, Credentials{}); err == nil {
+		t.Error("NewS3LifetimeStore() with an invalid endpoint: want error")
+	}
+}
@@ -0,0 +1,198 @@
+package clusterinfo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/netip"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/terraform-exec/tfexec"
+)
+
+// =============================================================================
+// Loading
+// =============================================================================
+
+// ErrNotDeployed is returned when the state holds no cluster outputs.
+var ErrNotDeployed = errors.New("no data found - the cluster may not be deployed")
+
+var errNoSource = errors.New("clusterinfo: Options.TerraformDir or Options.Source is required")
+
+// Options configures Load.
+type Options struct {
+	// TerraformDir is the directory containing the Terraform files. It is used by the
+	// default Source, and to resolve private_network_cidr. Optional if Source is set.
+	TerraformDir string
+	// Source provides the Terraform outputs (default: TerraformSource over TerraformDir).
+	Source StateSource
+	// Credentials are used by the default Source (default: FileCredentials in TerraformDir).
+	Credentials CredentialProvider
+	// SkipInit skips `terraform init` in the default Source.
+	SkipInit bool
+	// ForceInit always runs `terraform init` in the default Source.
+	ForceInit bool
+
+	// OutputMap overrides output names (see DefaultOutputMap for the keys).
+	OutputMap map[string]string
+	// Discover infers the nodes from the nodes output, else from <name>_public_ip /
+	// <name>_private_ip pairs, instead of the mapped control-plane and worker outputs.
+	Discover bool
+	// Lenient reports invalid outputs to the Logger instead of failing.
+	Lenient bool
+	// PrivateNetwork is the expected private network (default: private_network_cidr from TerraformDir).
+	PrivateNetwork netip.Prefix
+
+	// SSHKeyPath is copied into ClusterInfo.SSHKeyPath.
+	SSHKeyPath string
+	// Logger receives progress messages (default: silent).
+	Logger Logger
+}
+
+// Load reads and validates the cluster information.
+func Load(ctx context.Context, opts Options) (*ClusterInfo, error) {
+	log := loggerOrNop(opts.Logger)
+
+	src, closeSource, err := opts.source(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = closeSource() }()
+
+	mapping, err := ResolveOutputMap(opts.OutputMap)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("Retrieving cluster information...")
+
+	outputs, err := src.Outputs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(outputs) == 0 {
+		return nil, ErrNotDeployed
+	}
+
+	privateNetwork := opts.PrivateNetwork
+	if !privateNetwork.IsValid() && opts.TerraformDir != "" {
+		privateNetwork, err = LoadPrivateNetwork(opts.TerraformDir)
+		if err != nil {
+			log.Warnf("Private network check skipped: %v", err)
+		}
+	}
+
+	info := &ClusterInfo{
+		SSHKeyPath: opts.SSHKeyPath,
+	}
+
+	if opts.Discover {
+		err = discoverClusterInfo(info, outputs, mapping[FieldNodes], privateNetwork)
+	} else {
+		err = fillClusterInfo(info, outputs, mapping, privateNetwork)
+	}
+
+	if err != nil {
+		if !opts.Lenient {
+			return nil, err
+		}
+
+		log.Warnf("%v", err)
+	}
+
+	if info.ControlPlane == (NodeInfo{}) && info.Worker == (NodeInfo{}) {
+		return nil, ErrNotDeployed
+	}
+
+	log.Successf("Information retrieved")
+
+	return info, nil
+}
+
+// ReadSSHKey returns the SSH private key output, or "" if the state has none.
+// outputName defaults to the ssh_private_key entry of DefaultOutputMap.
+func ReadSSHKey(ctx context.Context, src StateSource, outputName string) (string, error) {
+	if outputName == "" {
+		outputName = DefaultOutputMap()[FieldSSHPrivateKey]
+	}
+
+	outputs, err := src.Outputs(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return extractStringOutput(outputs, outputName), nil
+}
+
+// source returns the configured Source, or builds the default TerraformSource.
+// The close function releases the default source (its temporary data dir); the
+// configured Source is left to the caller.
+func (o Options) source(ctx context.Context) (StateSource, func() error, error) {
+	if o.Source != nil {
+		return o.Source, func() error { return nil }, nil
+	}
+
+	if o.TerraformDir == "" {
+		return nil, nil, errNoSource
+	}
+
+	src, err := NewTerraformSource(ctx, TerraformConfig{
+		Dir:         o.TerraformDir,
+		Credentials: o.Credentials,
+		SkipInit:    o.SkipInit,
+		ForceInit:   o.ForceInit,
+		Logger:      o.Logger,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return src, src.Close, nil
+}
+
+func extractStringOutput(outputs map[string]tfexec.OutputMeta, key string) string {
+	v, ok := outputs[key]
+	if !ok {
+		return ""
+	}
+
+	var result string
+	if err := json.Unmarshal(v.Value, &result); err != nil {
+		return ""
+	}
+
+	return result
+}
+
+// FindProjectRoot walks up from start (default: the working directory) to the
+// first directory containing terraform/main.tf.
+func FindProjectRoot(start string) (string, error) {
+	if start == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return "", err
+		}
+
+		start = cwd
+	}
+
+	dir := start
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "terraform", "main.tf")); err == nil {
+			return dir, nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+
+		dir = parent
+	}
+
+	return "", fmt.Errorf("could not find terraform/main.tf from %s", start)
+}
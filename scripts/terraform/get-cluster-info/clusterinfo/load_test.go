@@ -0,0 +1,316 @@
+package clusterinfo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-exec/tfexec"
+)
+
+// =============================================================================
+// extractStringOutput tests
+// =============================================================================
+
+func TestExtractStringOutput(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		outputs  map[string]tfexec.OutputMeta
+		key      string
+		expected string
+	}{
+		{
+			name:     "empty outputs - returns empty string",
+			outputs:  map[string]tfexec.OutputMeta{},
+			key:      "some_key",
+			expected: "",
+		},
+		{
+			name: "key not found - returns empty string",
+			outputs: map[string]tfexec.OutputMeta{
+				"other_key": {Value: json.RawMessage(`"value"`)},
+			},
+			key:      "some_key",
+			expected: "",
+		},
+		{
+			name: "valid string value - returns value",
+			outputs: map[string]tfexec.OutputMeta{
+				"ip_address": {Value: json.RawMessage(`"192.168.1.1"`)},
+			},
+			key:      "ip_address",
+			expected: "192.168.1.1",
+		},
+		{
+			name: "invalid json - returns empty string",
+			outputs: map[string]tfexec.OutputMeta{
+				"bad_value": {Value: json.RawMessage(`not valid json`)},
+			},
+			key:      "bad_value",
+			expected: "",
+		},
+		{
+			name: "non-string json value - returns empty string",
+			outputs: map[string]tfexec.OutputMeta{
+				"number": {Value: json.RawMessage(`123`)},
+			},
+			key:      "number",
+			expected: "",
+		},
+		{
+			name: "null json value - returns empty string",
+			outputs: map[string]tfexec.OutputMeta{
+				"null_value": {Value: json.RawMessage(`null`)},
+			},
+			key:      "null_value",
+			expected: "",
+		},
+		{
+			name: "empty string value - returns empty string",
+			outputs: map[string]tfexec.OutputMeta{
+				"empty": {Value: json.RawMessage(`""`)},
+			},
+			key:      "empty",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			result := extractStringOutput(tt.outputs, tt.key)
+			if result != tt.expected {
+				t.Errorf("extractStringOutput() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+// =============================================================================
+// FindProjectRoot tests
+// =============================================================================
+
+func TestFindProjectRoot(t *testing.T) {
+	// Not parallel - modifies working directory
+
+	tests := []struct {
+		name        string
+		setupFunc   func(t *testing.T) (tmpDir string, cleanup func())
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name: "finds project root from nested subdirectory",
+			setupFunc: func(t *testing.T) (string, func()) {
+				t.Helper()
+
+				tmpDir := t.TempDir()
+				tmpDir, _ = filepath.EvalSymlinks(tmpDir) // macOS /var -> /private/var
+
+				terraformDir := filepath.Join(tmpDir, "terraform")
+				subDir := filepath.Join(tmpDir, "some", "nested", "dir")
+
+				must(t, os.MkdirAll(terraformDir, 0o755))
+				must(t, os.MkdirAll(subDir, 0o755))
+				must(t, os.WriteFile(filepath.Join(terraformDir, "main.tf"), []byte("# tf"), 0o644))
+
+				originalWd, _ := os.Getwd()
+				must(t, os.Chdir(subDir))
+
+				return tmpDir, func() { _ = os.Chdir(originalWd) }
+			},
+			wantErr: false,
+		},
+		{
+			name: "finds project root from terraform directory itself",
+			setupFunc: func(t *testing.T) (string, func()) {
+				t.Helper()
+
+				tmpDir := t.TempDir()
+				tmpDir, _ = filepath.EvalSymlinks(tmpDir)
+
+				terraformDir := filepath.Join(tmpDir, "terraform")
+
+				must(t, os.MkdirAll(terraformDir, 0o755))
+				must(t, os.WriteFile(filepath.Join(terraformDir, "main.tf"), []byte("# tf"), 0o644))
+
+				originalWd, _ := os.Getwd()
+				must(t, os.Chdir(tmpDir))
+
+				return tmpDir, func() { _ = os.Chdir(originalWd) }
+			},
+			wantErr: false,
+		},
+		{
+			name: "no terraform directory - returns error",
+			setupFunc: func(t *testing.T) (string, func()) {
+				t.Helper()
+
+				tmpDir := t.TempDir()
+				tmpDir, _ = filepath.EvalSymlinks(tmpDir)
+
+				originalWd, _ := os.Getwd()
+				must(t, os.Chdir(tmpDir))
+
+				return "", func() { _ = os.Chdir(originalWd) }
+			},
+			wantErr:     true,
+			errContains: "could not find terraform/main.tf",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Not parallel - modifies working directory
+
+			expectedDir, cleanup := tt.setupFunc(t)
+			t.Cleanup(cleanup)
+
+			got, err := FindProjectRoot("")
+
+			if tt.wantErr {
+				if err == nil {
+					t.Error("FindProjectRoot() expected error, got nil")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Errorf("FindProjectRoot() unexpected error = %v", err)
+			}
+
+			if got != expectedDir {
+				t.Errorf("FindProjectRoot() = %q, want %q", got, expectedDir)
+			}
+		})
+	}
+}
+
+// =============================================================================
+// Test helpers
+// =============================================================================
+
+func must(t *testing.T, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// =============================================================================
+// Load tests
+// =============================================================================
+
+// staticSource is a StateSource returning fixed outputs.
+type staticSource map[string]tfexec.OutputMeta
+
+func (s staticSource) Outputs(_ context.Context) (map[string]tfexec.OutputMeta, error) {
+	return s, nil
+}
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	str := func(s string) tfexec.OutputMeta { return tfexec.OutputMeta{Value: json.RawMessage(`"` + s + `"`)} }
+
+	deployed := staticSource{
+		"control_plane_public_ip":  str("51.15.1.1"),
+		"control_plane_private_ip": str("10.0.0.10"),
+		"worker_public_ip":         str("51.15.1.2"),
+		"worker_private_ip":        str("10.0.0.11"),
+	}
+
+	tests := []struct {
+		name       string
+		opts       Options
+		wantErr    error
+		wantWorker string
+	}{
+		{
+			name:       "deployed cluster",
+			opts:       Options{Source: deployed, SSHKeyPath: "/k.pem"},
+			wantWorker: "51.15.1.2",
+		},
+		{
+			name:    "empty state - not deployed",
+			opts:    Options{Source: staticSource{}},
+			wantErr: ErrNotDeployed,
+		},
+		{
+			name:    "missing output - validation error",
+			opts:    Options{Source: deployed, OutputMap: map[string]string{FieldWorkerPublicIP: "other"}},
+			wantErr: ErrOutputMissing,
+		},
+		{
+			name:       "missing output with Lenient - partial info",
+			opts:       Options{Source: deployed, OutputMap: map[string]string{FieldWorkerPrivateIP: "other"}, Lenient: true},
+			wantWorker: "51.15.1.2",
+		},
+		{
+			name:    "private network mismatch",
+			opts:    Options{Source: deployed, PrivateNetwork: netip.MustParsePrefix("192.168.0.0/24")},
+			wantErr: ErrOutsideNetwork,
+		},
+		{
+			name:    "no source and no terraform dir",
+			opts:    Options{},
+			wantErr: errNoSource,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			info, err := Load(context.Background(), tt.opts)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Load() error = %v, want %v", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Load() unexpected error = %v", err)
+			}
+
+			if info.Worker.PublicIP != tt.wantWorker {
+				t.Errorf("Worker.PublicIP = %q, want %q", info.Worker.PublicIP, tt.wantWorker)
+			}
+
+			if info.SSHKeyPath != tt.opts.SSHKeyPath {
+				t.Errorf("SSHKeyPath = %q, want %q", info.SSHKeyPath, tt.opts.SSHKeyPath)
+			}
+		})
+	}
+}
+
+func TestReadSSHKey(t *testing.T) {
+	t.Parallel()
+
+	src := staticSource{
+		"ssh_private_key": {Sensitive: true, Value: json.RawMessage(`"-----BEGIN KEY-----"`)},
+		"custom_key":      {Sensitive: true, Value: json.RawMessage(`"custom"`)},
+	}
+
+	key, err := ReadSSHKey(context.Background(), src, "")
+	if err != nil || key != "-----BEGIN KEY-----" {
+		t.Errorf("ReadSSHKey(default) = %q, %v", key, err)
+	}
+
+	key, err = ReadSSHKey(context.Background(), src, "custom_key")
+	if err != nil || key != "custom" {
+		t.Errorf("ReadSSHKey(custom_key) = %q, %v", key, err)
+	}
+}
@@ -0,0 +1,23 @@
+package clusterinfo
+
+// Logger receives progress messages. The get-cluster-info command prints them
+// with its icons; library users can plug their own logger or keep the silent default.
+type Logger interface {
+	Infof(format string, args ...any)
+	Successf(format string, args ...any)
+	Warnf(format string, args ...any)
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Infof(string, ...any)    {}
+func (nopLogger) Successf(string, ...any) {}
+func (nopLogger) Warnf(string, ...any)    {}
+
+func loggerOrNop(l Logger) Logger {
+	if l == nil {
+		return nopLogger{}
+	}
+
+	return l
+}
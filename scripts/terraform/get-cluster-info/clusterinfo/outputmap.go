@@ -0,0 +1,149 @@
+package clusterinfo
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-exec/tfexec"
+	"gopkg.in/yaml.v3"
+)
+
+// =============================================================================
+// Output-name mapping
+// =============================================================================
+//
+// Forks of the terraform module may name their outputs differently. The names
+// read by the tool can be remapped from the config file or with --output-map:
+//
+//   get-cluster-info --output-map control_plane.public_ip=cp_ip
+//
+// Every node of local.nodes, including the extra workers, is read from the
+// nodes output (name => role, public_ip, private_ip). Without it (older
+// module), --discover infers the nodes from <name>_public_ip /
+// <name>_private_ip output pairs and guesses their role from <name>.
+
+// Mapping keys (ClusterInfo fields) and default config file name.
+const (
+	FieldControlPlanePublicIP  = "control_plane.public_ip"
+	FieldControlPlanePrivateIP = "control_plane.private_ip"
+	FieldWorkerPublicIP        = "worker.public_ip"
+	FieldWorkerPrivateIP       = "worker.private_ip"
+	FieldSSHPrivateKey         = "ssh_private_key"
+	FieldNodes                 = "nodes"
+
+	ConfigFileName = "get-cluster-info.yaml"
+
+	publicIPSuffix  = "_public_ip"
+	privateIPSuffix = "_private_ip"
+)
+
+var ErrUnknownMappingKey = errors.New("unknown output-map key")
+
+// FileConfig is the optional get-cluster-info configuration file (YAML).
+// Tags use snake_case to match the expected file format.
+type FileConfig struct {
+	OutputMap map[string]string `yaml:"output_map"` //nolint:tagliatelle
+	Discover  bool              `yaml:"discover"`
+}
+
+// DefaultOutputMap returns the output names of the k8s-lab terraform module.
+func DefaultOutputMap() map[string]string {
+	return map[string]string{
+		FieldControlPlanePublicIP:  "control_plane_public_ip",
+		FieldControlPlanePrivateIP: "control_plane_private_ip",
+		FieldWorkerPublicIP:        "worker_public_ip",
+		FieldWorkerPrivateIP:       "worker_private_ip",
+		FieldSSHPrivateKey:         "ssh_private_key",
+		FieldNodes:                 "nodes",
+	}
+}
+
+// ResolveOutputMap merges the defaults with the config file, then the flags (highest priority).
+func ResolveOutputMap(overrides ...map[string]string) (map[string]string, error) {
+	mapping := DefaultOutputMap()
+
+	for _, override := range overrides {
+		for key, name := range override {
+			if _, ok := mapping[key]; !ok {
+				return nil, fmt.Errorf("%w %q (valid keys: %s)", ErrUnknownMappingKey, key, strings.Join(mappingKeys(), ", "))
+			}
+
+			if name == "" {
+				return nil, fmt.Errorf("empty output name for %q", key)
+			}
+
+			mapping[key] = name
+		}
+	}
+
+	return mapping, nil
+}
+
+func mappingKeys() []string {
+	keys := []string{}
+	for k := range DefaultOutputMap() {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// ResolveConfigFile returns <terraform-dir>/get-cluster-info.yaml if it exists, or "".
+func ResolveConfigFile(terraformDir string) string {
+	p := filepath.Join(terraformDir, ConfigFileName)
+	if _, err := os.Stat(p); err == nil {
+		return p
+	}
+
+	return ""
+}
+
+// LoadFileConfig reads the tool configuration file. An empty path returns an empty config.
+func LoadFileConfig(path string) (*FileConfig, error) {
+	fc := &FileConfig{}
+	if path == "" {
+		return fc, nil
+	}
+
+	data, err := os.ReadFile(path) //nolint:gosec // path provided by the user
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, fc); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	return fc, nil
+}
+
+// discoverNodeNames returns the sorted <name> prefixes of every <name>_public_ip output.
+func discoverNodeNames(outputs map[string]tfexec.OutputMeta) []string {
+	names := []string{}
+
+	for key := range outputs {
+		if name, ok := strings.CutSuffix(key, publicIPSuffix); ok && name != "" {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// roleFromName guesses the role of a discovered node from its output prefix.
+func roleFromName(name string) string {
+	n := strings.ToLower(strings.NewReplacer("-", "", "_", "").Replace(name))
+	if n == "cp" || strings.HasPrefix(n, "controlplane") || strings.HasPrefix(n, "master") {
+		return RoleControlPlane
+	}
+
+	return RoleWorker
+}
@@ -0,0 +1,264 @@
+package clusterinfo
+
+import (
+	"encoding/json"
+	"errors"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-exec/tfexec"
+)
+
+// =============================================================================
+// ResolveOutputMap tests
+// =============================================================================
+
+func TestResolveOutputMap(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		file      map[string]string
+		flags     map[string]string
+		wantKey   string
+		wantName  string
+		wantErrIs error
+		wantErr   bool
+	}{
+		{
+			name:     "defaults",
+			wantKey:  FieldControlPlanePublicIP,
+			wantName: "control_plane_public_ip",
+		},
+		{
+			name:     "config file overrides default",
+			file:     map[string]string{FieldControlPlanePublicIP: "cp_ip"},
+			wantKey:  FieldControlPlanePublicIP,
+			wantName: "cp_ip",
+		},
+		{
+			name:     "flag overrides config file",
+			file:     map[string]string{FieldControlPlanePublicIP: "cp_ip"},
+			flags:    map[string]string{FieldControlPlanePublicIP: "master_ip"},
+			wantKey:  FieldControlPlanePublicIP,
+			wantName: "master_ip",
+		},
+		{
+			name:      "unknown key - error",
+			flags:     map[string]string{"control_plane.ip": "x"},
+			wantErrIs: ErrUnknownMappingKey,
+			wantErr:   true,
+		},
+		{
+			name:    "empty output name - error",
+			flags:   map[string]string{FieldWorkerPublicIP: ""},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mapping, err := ResolveOutputMap(tt.file, tt.flags)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("ResolveOutputMap() expected error, got nil")
+				}
+
+				if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+					t.Errorf("ResolveOutputMap() error = %v, want %v", err, tt.wantErrIs)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ResolveOutputMap() unexpected error = %v", err)
+			}
+
+			if mapping[tt.wantKey] != tt.wantName {
+				t.Errorf("mapping[%q] = %q, want %q", tt.wantKey, mapping[tt.wantKey], tt.wantName)
+			}
+
+			if len(mapping) != len(DefaultOutputMap()) {
+				t.Errorf("mapping has %d keys, want %d", len(mapping), len(DefaultOutputMap()))
+			}
+		})
+	}
+}
+
+// =============================================================================
+// LoadFileConfig tests
+// =============================================================================
+
+func TestLoadFileConfig(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, ConfigFileName)
+	must(t, os.WriteFile(path, []byte("output_map:\n  worker.public_ip: w_ip\ndiscover: true\n"), 0o600))
+
+	fc, err := LoadFileConfig(path)
+	if err != nil {
+		t.Fatalf("LoadFileConfig() unexpected error = %v", err)
+	}
+
+	if !fc.Discover || fc.OutputMap[FieldWorkerPublicIP] != "w_ip" {
+		t.Errorf("LoadFileConfig() = %+v", fc)
+	}
+
+	empty, err := LoadFileConfig("")
+	if err != nil || empty.Discover || len(empty.OutputMap) != 0 {
+		t.Errorf("LoadFileConfig(\"\") = %+v, %v, want empty config", empty, err)
+	}
+}
+
+// =============================================================================
+// roleFromName tests
+// =============================================================================
+
+func TestRoleFromName(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"control_plane":   RoleControlPlane,
+		"control-plane-2": RoleControlPlane,
+		"controlplane":    RoleControlPlane,
+		"master":          RoleControlPlane,
+		"cp":              RoleControlPlane,
+		"worker":          RoleWorker,
+		"worker_1":        RoleWorker,
+		"cpu_node":        RoleWorker,
+	}
+
+	for name, want := range tests {
+		if got := roleFromName(name); got != want {
+			t.Errorf("roleFromName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+// =============================================================================
+// discoverClusterInfo tests
+// =============================================================================
+
+func TestDiscoverClusterInfo(t *testing.T) {
+	t.Parallel()
+
+	str := func(s string) tfexec.OutputMeta { return tfexec.OutputMeta{Value: json.RawMessage(`"` + s + `"`)} }
+
+	tests := []struct {
+		name          string
+		outputs       map[string]tfexec.OutputMeta
+		wantNodes     []string
+		wantCP        string
+		wantWorker    string
+		wantErrIs     error
+		wantProblemsN int
+	}{
+		{
+			name: "control-plane and two workers",
+			outputs: map[string]tfexec.OutputMeta{
+				"master_public_ip":    str("1.1.1.1"),
+				"master_private_ip":   str("10.0.0.10"),
+				"worker_b_public_ip":  str("3.3.3.3"),
+				"worker_b_private_ip": str("10.0.0.12"),
+				"worker_a_public_ip":  str("2.2.2.2"),
+				"worker_a_private_ip": str("10.0.0.11"),
+				"ssh_private_key":     str("key"),
+			},
+			wantNodes:  []string{"master", "worker-a", "worker-b"},
+			wantCP:     "1.1.1.1",
+			wantWorker: "2.2.2.2",
+		},
+		{
+			name: "missing private IP of a pair",
+			outputs: map[string]tfexec.OutputMeta{
+				"control_plane_public_ip":  str("1.1.1.1"),
+				"control_plane_private_ip": str("10.0.0.10"),
+				"worker_public_ip":         str("2.2.2.2"),
+			},
+			wantNodes:     []string{"control-plane", "worker"},
+			wantCP:        "1.1.1.1",
+			wantWorker:    "2.2.2.2",
+			wantErrIs:     ErrOutputMissing,
+			wantProblemsN: 1,
+		},
+		{
+			name: "no control-plane",
+			outputs: map[string]tfexec.OutputMeta{
+				"worker_public_ip":  str("2.2.2.2"),
+				"worker_private_ip": str("10.0.0.11"),
+			},
+			wantNodes:     []string{"worker"},
+			wantWorker:    "2.2.2.2",
+			wantErrIs:     ErrNoControlPlane,
+			wantProblemsN: 1,
+		},
+		{
+			name: "nodes output wins over the pairs",
+			outputs: map[string]tfexec.OutputMeta{
+				"nodes": {Value: json.RawMessage(`{
+  "worker-2": {"role": "worker", "public_ip": "3.3.3.3", "private_ip": "10.0.0.12"},
+  "control-plane": {"role": "control-plane", "public_ip": "1.1.1.1", "private_ip": "10.0.0.10"},
+  "worker": {"role": "worker", "public_ip": "2.2.2.2", "private_ip": "10.0.0.11"}}`)},
+				"master_public_ip":  str("9.9.9.9"),
+				"master_private_ip": str("10.0.0.9"),
+			},
+			wantNodes:  []string{"control-plane", "worker", "worker-2"},
+			wantCP:     "1.1.1.1",
+			wantWorker: "2.2.2.2",
+		},
+		{
+			name:          "no pairs at all",
+			outputs:       map[string]tfexec.OutputMeta{"something": str("x")},
+			wantNodes:     []string{},
+			wantErrIs:     ErrOutputMissing,
+			wantProblemsN: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			info := &ClusterInfo{}
+			err := discoverClusterInfo(info, tt.outputs, "nodes", netip.MustParsePrefix("10.0.0.0/24"))
+
+			if tt.wantErrIs == nil {
+				if err != nil {
+					t.Fatalf("discoverClusterInfo() unexpected error = %v", err)
+				}
+			} else {
+				var verr *ValidationError
+				if !errors.As(err, &verr) || !errors.Is(err, tt.wantErrIs) || len(verr.Problems) != tt.wantProblemsN {
+					t.Fatalf("discoverClusterInfo() error = %v, want %d problem(s) including %v", err, tt.wantProblemsN, tt.wantErrIs)
+				}
+			}
+
+			names := []string{}
+			for _, n := range info.Nodes {
+				names = append(names, n.Name)
+			}
+
+			if len(names) != len(tt.wantNodes) {
+				t.Fatalf("nodes = %v, want %v", names, tt.wantNodes)
+			}
+
+			for i := range names {
+				if names[i] != tt.wantNodes[i] {
+					t.Errorf("nodes = %v, want %v", names, tt.wantNodes)
+				}
+			}
+
+			if info.ControlPlane.PublicIP != tt.wantCP || info.Worker.PublicIP != tt.wantWorker {
+				t.Errorf("ControlPlane=%q Worker=%q, want %q %q",
+					info.ControlPlane.PublicIP, info.Worker.PublicIP, tt.wantCP, tt.wantWorker)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package clusterinfo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-exec/tfexec"
+)
+
+// =============================================================================
+// Outputs listing
+// =============================================================================
+
+// SensitivePlaceholder replaces the value of masked sensitive outputs.
+const SensitivePlaceholder = "(sensitive)"
+
+// OutputEntry describes a single Terraform output.
+// JSON tags use snake_case for consistency with Terraform outputs.
+type OutputEntry struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Description string `json:"description,omitempty"`
+	Sensitive   bool   `json:"sensitive"`
+	Value       any    `json:"value"`
+}
+
+// BuildOutputEntries decodes outputs, sorted by name, masking sensitive values if requested.
+func BuildOutputEntries(
+	outputs map[string]tfexec.OutputMeta,
+	descriptions map[string]string,
+	revealSensitive bool,
+) ([]OutputEntry, error) {
+	entries := make([]OutputEntry, 0, len(outputs))
+
+	for name, meta := range outputs {
+		entry := OutputEntry{
+			Name:        name,
+			Type:        FormatOutputType(meta.Type),
+			Description: descriptions[name],
+			Sensitive:   meta.Sensitive,
+		}
+
+		if meta.Sensitive && !revealSensitive {
+			entry.Value = SensitivePlaceholder
+		} else {
+			value, err := DecodeOutputValue(meta.Value)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode output %q: %w", name, err)
+			}
+
+			entry.Value = value
+		}
+
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+
+	return entries, nil
+}
+
+// DecodeOutputValue decodes a JSON value, keeping numbers as json.Number to avoid float rounding.
+func DecodeOutputValue(raw json.RawMessage) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// FormatOutputType converts a Terraform JSON type ("string", ["list","string"], ...)
+// into its HCL notation (string, list(string), ...).
+func FormatOutputType(raw json.RawMessage) string {
+	var t any
+	if err := json.Unmarshal(raw, &t); err != nil {
+		return "unknown"
+	}
+
+	return formatTypeValue(t)
+}
+
+func formatTypeValue(t any) string {
+	switch v := t.(type) {
+	case string:
+		return v
+	case []any:
+		if len(v) == 0 {
+			return "unknown"
+		}
+
+		kind, _ := v[0].(string)
+		switch kind {
+		case "list", "set", "map":
+			if len(v) > 1 {
+				return fmt.Sprintf("%s(%s)", kind, formatTypeValue(v[1]))
+			}
+
+			return kind
+		case "":
+			return "unknown"
+		default:
+			// object and tuple: the attribute list is too verbose for a summary
+			return kind
+		}
+	default:
+		return "unknown"
+	}
+}
+
+// FormatOutputValue renders a decoded value on a single line.
+// Strings are printed as-is, everything else as compact JSON.
+func FormatOutputValue(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return val
+	case json.Number:
+		return val.String()
+	default:
+		data, err := json.Marshal(val)
+		if err != nil {
+			return fmt.Sprint(val)
+		}
+
+		return string(data)
+	}
+}
@@ -0,0 +1,148 @@
+package clusterinfo
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-exec/tfexec"
+)
+
+// =============================================================================
+// FormatOutputType tests
+// =============================================================================
+
+func TestFormatOutputType(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		raw      string
+		expected string
+	}{
+		{name: "primitive string", raw: `"string"`, expected: "string"},
+		{name: "primitive number", raw: `"number"`, expected: "number"},
+		{name: "list of strings", raw: `["list","string"]`, expected: "list(string)"},
+		{name: "map of numbers", raw: `["map","number"]`, expected: "map(number)"},
+		{name: "nested collection", raw: `["list",["map","string"]]`, expected: "list(map(string))"},
+		{name: "object", raw: `["object",{"a":"string"}]`, expected: "object"},
+		{name: "tuple", raw: `["tuple",["string","number"]]`, expected: "tuple"},
+		{name: "invalid json", raw: `not json`, expected: "unknown"},
+		{name: "empty array", raw: `[]`, expected: "unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			result := FormatOutputType(json.RawMessage(tt.raw))
+			if result != tt.expected {
+				t.Errorf("FormatOutputType(%s) = %q, want %q", tt.raw, result, tt.expected)
+			}
+		})
+	}
+}
+
+// =============================================================================
+// FormatOutputValue tests
+// =============================================================================
+
+func TestFormatOutputValue(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		raw      string
+		expected string
+	}{
+		{name: "string", raw: `"1.2.3.4"`, expected: "1.2.3.4"},
+		{name: "integer keeps precision", raw: `12345678901234567890`, expected: "12345678901234567890"},
+		{name: "float", raw: `1.5`, expected: "1.5"},
+		{name: "bool", raw: `true`, expected: "true"},
+		{name: "null", raw: `null`, expected: "null"},
+		{name: "list", raw: `["a", "b"]`, expected: `["a","b"]`},
+		{name: "map", raw: `{"b": 2, "a": 1}`, expected: `{"a":1,"b":2}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			v, err := DecodeOutputValue(json.RawMessage(tt.raw))
+			if err != nil {
+				t.Fatalf("DecodeOutputValue() unexpected error = %v", err)
+			}
+
+			if result := FormatOutputValue(v); result != tt.expected {
+				t.Errorf("FormatOutputValue() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+// =============================================================================
+// BuildOutputEntries tests
+// =============================================================================
+
+func TestBuildOutputEntries(t *testing.T) {
+	t.Parallel()
+
+	outputs := map[string]tfexec.OutputMeta{
+		"worker_public_ip": {
+			Type:  json.RawMessage(`"string"`),
+			Value: json.RawMessage(`"5.6.7.8"`),
+		},
+		"ssh_private_key": {
+			Sensitive: true,
+			Type:      json.RawMessage(`"string"`),
+			Value:     json.RawMessage(`"-----BEGIN KEY-----"`),
+		},
+		"node_count": {
+			Type:  json.RawMessage(`"number"`),
+			Value: json.RawMessage(`2`),
+		},
+	}
+	descriptions := map[string]string{"worker_public_ip": "IP publique du worker"}
+
+	tests := []struct {
+		name            string
+		revealSensitive bool
+		wantKeyValue    any
+	}{
+		{name: "sensitive values masked by default", revealSensitive: false, wantKeyValue: SensitivePlaceholder},
+		{name: "sensitive values revealed on demand", revealSensitive: true, wantKeyValue: "-----BEGIN KEY-----"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			entries, err := BuildOutputEntries(outputs, descriptions, tt.revealSensitive)
+			if err != nil {
+				t.Fatalf("BuildOutputEntries() unexpected error = %v", err)
+			}
+
+			names := []string{}
+			for _, e := range entries {
+				names = append(names, e.Name)
+			}
+
+			wantNames := []string{"node_count", "ssh_private_key", "worker_public_ip"}
+			if !reflect.DeepEqual(names, wantNames) {
+				t.Fatalf("entries = %v, want sorted %v", names, wantNames)
+			}
+
+			if entries[0].Value != json.Number("2") || entries[0].Type != "number" {
+				t.Errorf("node_count = %#v (%s), want 2 (number)", entries[0].Value, entries[0].Type)
+			}
+
+			if !entries[1].Sensitive || entries[1].Value != tt.wantKeyValue {
+				t.Errorf("ssh_private_key = %#v, want %#v", entries[1].Value, tt.wantKeyValue)
+			}
+
+			if entries[2].Description != "IP publique du worker" {
+				t.Errorf("worker_public_ip description = %q", entries[2].Description)
+			}
+		})
+	}
+}
@@ -0,0 +1,244 @@
+package clusterinfo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-exec/tfexec"
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+// =============================================================================
+// State sources
+// =============================================================================
+
+// ErrTerraformNotFound is returned when the terraform binary is not in PATH.
+var ErrTerraformNotFound = errors.New("terraform is not installed or not in PATH")
+
+// StateSource provides the Terraform outputs of a deployed stack.
+type StateSource interface {
+	Outputs(ctx context.Context) (map[string]tfexec.OutputMeta, error)
+}
+
+// CachedSource fetches the outputs of a StateSource once and serves that snapshot
+// to every later caller (cluster info, SSH key, renderers), so that the remote
+// state is pulled a single time per run. Errors are cached as well.
+type CachedSource struct {
+	src StateSource
+
+	once    sync.Once
+	outputs map[string]tfexec.OutputMeta
+	err     error
+}
+
+// NewCachedSource wraps src.
+func NewCachedSource(src StateSource) *CachedSource {
+	return &CachedSource{src: src}
+}
+
+// Outputs implements StateSource. Only the first call reaches the wrapped source.
+func (c *CachedSource) Outputs(ctx context.Context) (map[string]tfexec.OutputMeta, error) {
+	c.once.Do(func() {
+		c.outputs, c.err = c.src.Outputs(ctx)
+	})
+
+	return c.outputs, c.err
+}
+
+// TerraformClient is the subset of *tfexec.Terraform used by the tool.
+// It allows hermetic tests with a fake client (see the clusterinfotest package).
+type TerraformClient interface {
+	SetEnv(env map[string]string) error
+	Init(ctx context.Context, opts ...tfexec.InitOption) error
+	Output(ctx context.Context, opts ...tfexec.OutputOption) (map[string]tfexec.OutputMeta, error)
+	Show(ctx context.Context, opts ...tfexec.ShowOption) (*tfjson.State, error)
+	Plan(ctx context.Context, opts ...tfexec.PlanOption) (bool, error)
+	ShowPlanFile(ctx context.Context, planPath string, opts ...tfexec.ShowOption) (*tfjson.Plan, error)
+	Destroy(ctx context.Context, opts ...tfexec.DestroyOption) error
+	WorkspaceShow(ctx context.Context) (string, error)
+}
+
+var _ TerraformClient = (*tfexec.Terraform)(nil)
+
+// TerraformConfig configures a TerraformSource.
+type TerraformConfig struct {
+	// Dir is the directory containing the Terraform files (required).
+	Dir string
+	// ExecPath is the terraform binary (default: looked up in PATH).
+	ExecPath string
+	// Client replaces the tfexec client built from Dir and ExecPath (e.g. a fake in tests).
+	Client TerraformClient
+	// Credentials are the S3 backend credentials (default: FileCredentials in Dir).
+	Credentials CredentialProvider
+	// SkipInit never runs `terraform init`. By default init only runs when
+	// the data dir is out of date (see CheckInit).
+	SkipInit bool
+	// ForceInit always runs `terraform init`.
+	ForceInit bool
+	// Logger receives progress messages (default: silent).
+	Logger Logger
+}
+
+// TerraformSource reads outputs by running terraform through tfexec.
+type TerraformSource struct {
+	tf TerraformClient
+	// tmpDataDir is the temporary TF_DATA_DIR used when Dir is read-only.
+	tmpDataDir string
+}
+
+// NewTerraformSource configures terraform with the backend credentials and runs
+// `terraform init` if the data dir is out of date. Call Close when done.
+func NewTerraformSource(ctx context.Context, cfg TerraformConfig) (*TerraformSource, error) {
+	log := loggerOrNop(cfg.Logger)
+
+	provider := cfg.Credentials
+	if provider == nil {
+		provider = FileCredentials{Path: ResolveCredentialsFile(cfg.Dir)}
+	}
+
+	creds, err := provider.Credentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tf := cfg.Client
+	if tf == nil {
+		tf, err = newTfexecClient(cfg.Dir, cfg.ExecPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	src := &TerraformSource{tf: tf}
+	env := terraformEnv(creds)
+
+	status := InitStatus{Needed: !cfg.SkipInit, Reason: "forced"}
+	if !cfg.SkipInit && !cfg.ForceInit {
+		status = CheckInit(cfg.Dir, DataDir(cfg.Dir))
+	}
+
+	if status.Needed && !dirWritable(cfg.Dir) {
+		dataDir, err := os.MkdirTemp("", "get-cluster-info-tfdata-*")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create temporary data dir: %w", err)
+		}
+
+		log.Warnf("%s is read-only - initializing in a temporary data dir", cfg.Dir)
+
+		env[EnvDataDir] = dataDir
+		src.tmpDataDir = dataDir
+	}
+
+	if err := tf.SetEnv(env); err != nil {
+		_ = src.Close()
+
+		return nil, fmt.Errorf("failed to configure environment variables: %w", err)
+	}
+
+	switch {
+	case cfg.SkipInit:
+	case !status.Needed:
+		log.Successf("Terraform already initialized (%s) - skipping init", status.Reason)
+	default:
+		log.Infof("Initializing Terraform (%s)...", status.Reason)
+
+		if err := tf.Init(ctx, tfexec.Upgrade(false)); err != nil {
+			_ = src.Close()
+
+			return nil, fmt.Errorf("terraform init failed: %w", err)
+		}
+
+		log.Successf("Terraform initialized")
+	}
+
+	return src, nil
+}
+
+// Close removes the temporary data dir, if one was needed.
+func (s *TerraformSource) Close() error {
+	if s.tmpDataDir == "" {
+		return nil
+	}
+
+	return os.RemoveAll(s.tmpDataDir)
+}
+
+// Outputs implements StateSource.
+func (s *TerraformSource) Outputs(ctx context.Context) (map[string]tfexec.OutputMeta, error) {
+	outputs, err := s.tf.Output(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("terraform output failed: %w", err)
+	}
+
+	return outputs, nil
+}
+
+// Client returns the underlying Terraform client, for commands beyond outputs (show, plan, destroy...).
+func (s *TerraformSource) Client() TerraformClient {
+	return s.tf
+}
+
+// newTfexecClient creates a tfexec client, looking up terraform in PATH if execPath is empty.
+func newTfexecClient(dir, execPath string) (*tfexec.Terraform, error) {
+	if execPath == "" {
+		p, err := exec.LookPath("terraform")
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrTerraformNotFound, err)
+		}
+
+		execPath = p
+	}
+
+	tf, err := tfexec.NewTerraform(dir, execPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Terraform instance: %w", err)
+	}
+
+	return tf, nil
+}
+
+// terraformEnv builds the subprocess environment: backend credentials, PATH, and the
+// provider variables (SCW_*, and HOME for ~/.config/scw/config.yaml) needed by plan.
+func terraformEnv(creds *Credentials) map[string]string {
+	env := map[string]string{
+		EnvAccessKey: creds.AccessKey,
+		EnvSecretKey: creds.SecretKey,
+	}
+	if dataDir := os.Getenv(EnvDataDir); dataDir != "" {
+		env[EnvDataDir] = dataDir
+	}
+	// tfexec replaces the subprocess env, so pass PATH so Terraform can find getent etc.
+	if path := os.Getenv("PATH"); path != "" {
+		env["PATH"] = path
+	} else {
+		env["PATH"] = "/usr/bin:/usr/local/bin:/bin"
+	}
+
+	if home := os.Getenv("HOME"); home != "" {
+		env["HOME"] = home
+	}
+
+	for _, kv := range os.Environ() {
+		if name, val, ok := strings.Cut(kv, "="); ok && strings.HasPrefix(name, "SCW_") {
+			env[name] = val
+		}
+	}
+
+	openStackVars := []string{
+		"OS_AUTH_URL", "OS_TENANT_ID", "OS_TENANT_NAME",
+		"OS_USERNAME", "OS_PASSWORD", "OS_REGION_NAME",
+	}
+
+	for _, v := range openStackVars {
+		if val := os.Getenv(v); val != "" {
+			env[v] = val
+		}
+	}
+
+	return env
+}
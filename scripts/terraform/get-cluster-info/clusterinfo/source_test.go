@@ -0,0 +1,217 @@
+package clusterinfo
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-exec/tfexec"
+)
+
+// =============================================================================
+// CachedSource tests
+// =============================================================================
+
+// countingSource counts the calls to Outputs.
+type countingSource struct {
+	calls   int
+	outputs map[string]tfexec.OutputMeta
+	err     error
+}
+
+func (c *countingSource) Outputs(_ context.Context) (map[string]tfexec.OutputMeta, error) {
+	c.calls++
+
+	return c.outputs, c.err
+}
+
+func TestCachedSource(t *testing.T) {
+	t.Parallel()
+
+	errBoom := errors.New("boom")
+
+	tests := []struct {
+		name    string
+		src     *countingSource
+		wantErr error
+	}{
+		{
+			name: "outputs - fetched once",
+			src:  &countingSource{outputs: map[string]tfexec.OutputMeta{"a": {}}},
+		},
+		{
+			name:    "error - cached too",
+			src:     &countingSource{err: errBoom},
+			wantErr: errBoom,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			cached := NewCachedSource(tt.src)
+
+			for range 3 {
+				outputs, err := cached.Outputs(ctx)
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Outputs() error = %v, want %v", err, tt.wantErr)
+				}
+
+				if len(outputs) != len(tt.src.outputs) {
+					t.Errorf("Outputs() = %v, want %v", outputs, tt.src.outputs)
+				}
+			}
+
+			if tt.src.calls != 1 {
+				t.Errorf("wrapped source called %d times, want 1", tt.src.calls)
+			}
+		})
+	}
+}
+
+// =============================================================================
+// NewTerraformSource init tests
+// =============================================================================
+
+// recordingClient is a TerraformClient recording the init calls and environment.
+type recordingClient struct {
+	TerraformClient // nil: other methods are not used by these tests
+
+	inits int
+	env   map[string]string
+}
+
+func (r *recordingClient) SetEnv(env map[string]string) error {
+	r.env = env
+
+	return nil
+}
+
+func (r *recordingClient) Init(_ context.Context, _ ...tfexec.InitOption) error {
+	r.inits++
+
+	return nil
+}
+
+func TestNewTerraformSourceInit(t *testing.T) {
+	// Not parallel - modifies environment (TF_DATA_DIR)
+
+	tests := []struct {
+		name      string
+		upToDate  bool
+		skipInit  bool
+		forceInit bool
+		wantInits int
+	}{
+		{name: "not initialized - runs init", wantInits: 1},
+		{name: "up to date - skips init", upToDate: true, wantInits: 0},
+		{name: "up to date with force - runs init", upToDate: true, forceInit: true, wantInits: 1},
+		{name: "skip init - never runs init", skipInit: true, wantInits: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(EnvDataDir, "")
+
+			dir := t.TempDir()
+			if tt.upToDate {
+				dir, _ = initializedDir(t)
+			}
+
+			client := &recordingClient{}
+
+			src, err := NewTerraformSource(context.Background(), TerraformConfig{
+				Dir:         dir,
+				Client:      client,
+				Credentials: StaticCredentials{AccessKey: "a", SecretKey: "s"},
+				SkipInit:    tt.skipInit,
+				ForceInit:   tt.forceInit,
+			})
+			if err != nil {
+				t.Fatalf("NewTerraformSource() unexpected error = %v", err)
+			}
+
+			must(t, src.Close())
+
+			if client.inits != tt.wantInits {
+				t.Errorf("init ran %d times, want %d", client.inits, tt.wantInits)
+			}
+
+			if _, ok := client.env[EnvDataDir]; ok {
+				t.Errorf("unexpected %s for a writable dir", EnvDataDir)
+			}
+		})
+	}
+}
+
+func TestNewTerraformSourceReadOnlyDir(t *testing.T) {
+	// Not parallel - modifies environment (TF_DATA_DIR)
+
+	if os.Geteuid() == 0 {
+		t.Skip("root can write to read-only directories")
+	}
+
+	t.Setenv(EnvDataDir, "")
+
+	dir := t.TempDir()
+	must(t, os.Chmod(dir, 0o555))
+	t.Cleanup(func() { _ = os.Chmod(dir, 0o755) })
+
+	client := &recordingClient{}
+
+	src, err := NewTerraformSource(context.Background(), TerraformConfig{
+		Dir:         dir,
+		Client:      client,
+		Credentials: StaticCredentials{AccessKey: "a", SecretKey: "s"},
+	})
+	if err != nil {
+		t.Fatalf("NewTerraformSource() unexpected error = %v", err)
+	}
+
+	dataDir := client.env[EnvDataDir]
+	if dataDir == "" {
+		t.Fatalf("%s not set for a read-only dir", EnvDataDir)
+	}
+
+	if _, err := os.Stat(dataDir); err != nil {
+		t.Errorf("temporary data dir missing: %v", err)
+	}
+
+	must(t, src.Close())
+
+	if _, err := os.Stat(dataDir); !os.IsNotExist(err) {
+		t.Errorf("temporary data dir not removed by Close: %v", err)
+	}
+}
+
+func TestTerraformEnv(t *testing.T) {
+	// Not parallel - modifies environment
+
+	t.Setenv("SCW_DEFAULT_PROJECT_ID", "project")
+	t.Setenv("SCW_ACCESS_KEY", "scw-access")
+	t.Setenv("HOME", "/home/lab")
+	t.Setenv("AWS_PROFILE", "other")
+
+	env := terraformEnv(&Credentials{AccessKey: "a", SecretKey: "s"})
+
+	want := map[string]string{
+		EnvAccessKey:             "a",
+		EnvSecretKey:             "s",
+		"SCW_DEFAULT_PROJECT_ID": "project",
+		"SCW_ACCESS_KEY":         "scw-access",
+		"HOME":                   "/home/lab",
+	}
+
+	for k, v := range want {
+		if env[k] != v {
+			t.Errorf("env[%s] = %q, want %q", k, env[k], v)
+		}
+	}
+
+	if _, ok := env["AWS_PROFILE"]; ok {
+		t.Error("unrelated variables should not be passed to terraform")
+	}
+}
@@ -0,0 +1,135 @@
+package clusterinfo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// =============================================================================
+// State version
+// =============================================================================
+//
+// Fetching the outputs means `terraform output`, which downloads the whole
+// state. To poll it (--watch), a cheap version token is read first and the
+// outputs are only fetched again when it changes:
+//   - S3 backend: the ETag of the state object (a single HEAD request)
+//   - local backend: the lineage and serial of the state file, which
+//     Terraform increments on every write
+
+// ErrUnsupportedBackend is returned by NewStateVersioner for backends other than s3 and local.
+var ErrUnsupportedBackend = errors.New("unsupported backend")
+
+// StateVersioner returns a token that changes whenever the state is written.
+type StateVersioner interface {
+	StateVersion(ctx context.Context) (string, error)
+}
+
+// NewStateVersioner returns the versioner matching the backend of the configuration in dir.
+func NewStateVersioner(dir string, creds Credentials) (StateVersioner, error) {
+	backendType, cfg, err := loadBackendConfig(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	switch backendType {
+	case "s3":
+		b, err := LoadS3Backend(dir)
+		if err != nil {
+			return nil, err
+		}
+
+		v, err := NewS3StateVersioner(b, creds)
+		if err != nil {
+			return nil, err
+		}
+
+		return v, nil
+	case "", "local":
+		path, _ := cfg["path"].(string)
+		if path == "" {
+			path = backendStateFile
+		}
+
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+
+		return LocalStateVersioner{Path: path}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedBackend, backendType)
+	}
+}
+
+// -----------------------------------------------------------------------------
+// S3 backend
+// -----------------------------------------------------------------------------
+
+// S3StateVersioner reads the ETag of the state object.
+type S3StateVersioner struct {
+	client *minio.Client
+	bucket string
+	key    string
+}
+
+var _ StateVersioner = (*S3StateVersioner)(nil)
+
+// NewS3StateVersioner connects to the backend bucket with the backend credentials.
+func NewS3StateVersioner(b S3Backend, creds Credentials) (*S3StateVersioner, error) {
+	client, err := NewS3Client(b, creds)
+	if err != nil {
+		return nil, err
+	}
+
+	return &S3StateVersioner{client: client, bucket: b.Bucket, key: b.Key}, nil
+}
+
+// StateVersion implements StateVersioner.
+func (s *S3StateVersioner) StateVersion(ctx context.Context) (string, error) {
+	obj, err := s.client.StatObject(ctx, s.bucket, s.key, minio.StatObjectOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to stat s3://%s/%s: %w", s.bucket, s.key, err)
+	}
+
+	return obj.ETag, nil
+}
+
+// -----------------------------------------------------------------------------
+// Local backend
+// -----------------------------------------------------------------------------
+
+// LocalStateVersioner reads the lineage and serial of a local state file.
+type LocalStateVersioner struct {
+	Path string
+}
+
+var _ StateVersioner = LocalStateVersioner{}
+
+// StateVersion implements StateVersioner. A missing state file (nothing
+// applied yet) is a version of its own.
+func (l LocalStateVersioner) StateVersion(_ context.Context) (string, error) {
+	data, err := os.ReadFile(l.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("failed to read the state: %w", err)
+	}
+
+	var state struct {
+		Lineage string `json:"lineage"`
+		Serial  uint64 `json:"serial"`
+	}
+
+	if err := json.Unmarshal(data, &state); err != nil {
+		return "", fmt.Errorf("failed to parse %s: %w", l.Path, err)
+	}
+
+	return fmt.Sprintf("%s/%d", state.Lineage, state.Serial), nil
+}
@@ -0,0 +1,108 @@
+package clusterinfo
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// =============================================================================
+// State version tests
+// =============================================================================
+
+func TestNewStateVersioner(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		mainTF   string
+		wantPath string // LocalStateVersioner path, relative to the directory
+		wantS3   bool
+		wantErr  error
+	}{
+		{name: "s3 backend", mainTF: testMainTF, wantS3: true},
+		{name: "no backend", mainTF: `terraform {}`, wantPath: "terraform.tfstate"},
+		{
+			name:     "local backend with a path",
+			mainTF:   `terraform {` + "\n" + `  backend "local" { path = "state/lab.tfstate" }` + "\n" + `}`,
+			wantPath: "state/lab.tfstate",
+		},
+		{
+			name:    "other backend",
+			mainTF:  `terraform {` + "\n" + `  backend "http" {}` + "\n" + `}`,
+			wantErr: ErrUnsupportedBackend,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			must(t, os.WriteFile(filepath.Join(dir, "main.tf"), []byte(tt.mainTF), 0o600))
+
+			got, err := NewStateVersioner(dir, Credentials{AccessKey: "AKIATEST", SecretKey: "secretTEST"})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewStateVersioner() error = %v, want %v", err, tt.wantErr)
+			}
+
+			switch v := got.(type) {
+			case *S3StateVersioner:
+				if !tt.wantS3 {
+					t.Errorf("NewStateVersioner() = S3, want local")
+				}
+
+				if v.key != "k8s-lab/terraform.tfstate" {
+					t.Errorf("key = %q, want the state key", v.key)
+				}
+			case LocalStateVersioner:
+				if want := filepath.Join(dir, tt.wantPath); v.Path != want {
+					t.Errorf("Path = %q, want %q", v.Path, want)
+				}
+			}
+		})
+	}
+}
+
+func TestLocalStateVersioner(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	v := LocalStateVersioner{Path: filepath.Join(t.TempDir(), "terraform.tfstate")}
+
+	version := func() string {
+		t.Helper()
+
+		got, err := v.StateVersion(ctx)
+		if err != nil {
+			t.Fatalf("StateVersion() error = %v", err)
+		}
+
+		return got
+	}
+
+	if got := version(); got != "" {
+		t.Errorf("StateVersion() without a state = %q, want empty", got)
+	}
+
+	must(t, os.WriteFile(v.Path, []byte(`{"version": 4, "serial": 3, "lineage": "abc"}`), 0o600))
+	first := version()
+
+	if first != "abc/3" {
+		t.Errorf("StateVersion() = %q, want abc/3", first)
+	}
+
+	must(t, os.WriteFile(v.Path, []byte(`{"version": 4, "serial": 4, "lineage": "abc"}`), 0o600))
+
+	if got := version(); got == first {
+		t.Errorf("StateVersion() = %q after a write, want a new version", got)
+	}
+
+	must(t, os.WriteFile(v.Path, []byte(`not json`), 0o600))
+
+	if _, err := v.StateVersion(ctx); err == nil {
+		t.Error("StateVersion() with a corrupt state: want error")
+	}
+}
@@ -0,0 +1,172 @@
+package clusterinfo
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// =============================================================================
+// Terraform configuration (HCL) reading
+// =============================================================================
+//
+// Some information is not available from `terraform output -json` (output
+// descriptions, variable values), so it is read statically from the *.tf and
+// terraform.tfvars files. Only literal values are resolved: expressions that
+// need evaluation (var.x, interpolations, functions) are ignored.
+
+const TFVarsFile = "terraform.tfvars"
+
+// parseHCLFile parses a native-syntax HCL file (*.tf or *.tfvars).
+func parseHCLFile(path string) (*hclsyntax.Body, error) {
+	src, err := os.ReadFile(path) //nolint:gosec // files from the terraform directory
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, diags := hclsyntax.ParseConfig(src, path, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	body, ok := parsed.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil, errors.New("unexpected HCL body type in " + path)
+	}
+
+	return body, nil
+}
+
+// forEachConfigBlock calls fn for every top-level block of the given type in the *.tf files of dir.
+func forEachConfigBlock(dir, blockType string, fn func(block *hclsyntax.Block)) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.tf"))
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		body, err := parseHCLFile(file)
+		if err != nil {
+			return err
+		}
+
+		for _, block := range body.Blocks {
+			if block.Type == blockType {
+				fn(block)
+			}
+		}
+	}
+
+	return nil
+}
+
+// LoadOutputDescriptions reads the description of each output block in the *.tf files.
+// `terraform output -json` does not expose descriptions, so they come from the configuration.
+func LoadOutputDescriptions(dir string) (map[string]string, error) {
+	descriptions := map[string]string{}
+
+	err := forEachConfigBlock(dir, "output", func(block *hclsyntax.Block) {
+		if len(block.Labels) != 1 {
+			return
+		}
+
+		if desc := staticStringAttribute(block.Body, "description"); desc != "" {
+			descriptions[block.Labels[0]] = desc
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return descriptions, nil
+}
+
+// LoadVariableValue resolves a string variable the way Terraform would for a plain run:
+// terraform.tfvars first, then the default value of the variable block.
+// It returns "" if the variable is unknown or not a literal string.
+func LoadVariableValue(dir, name string) (string, error) {
+	tfvars := filepath.Join(dir, TFVarsFile)
+	if _, err := os.Stat(tfvars); err == nil {
+		body, err := parseHCLFile(tfvars)
+		if err != nil {
+			return "", err
+		}
+
+		if v := staticStringAttribute(body, name); v != "" {
+			return v, nil
+		}
+	}
+
+	var value string
+
+	err := forEachConfigBlock(dir, "variable", func(block *hclsyntax.Block) {
+		if len(block.Labels) == 1 && block.Labels[0] == name {
+			value = staticStringAttribute(block.Body, "default")
+		}
+	})
+
+	return value, err
+}
+
+// staticStringAttribute returns the value of a literal string attribute, or "" if it is absent or dynamic.
+func staticStringAttribute(body *hclsyntax.Body, name string) string {
+	attr, ok := body.Attributes[name]
+	if !ok {
+		return ""
+	}
+
+	val, diags := attr.Expr.Value(nil)
+	if diags.HasErrors() || val.IsNull() || !val.Type().Equals(cty.String) {
+		return ""
+	}
+
+	return val.AsString()
+}
+
+// LoadRequiredVersion returns the required_version constraint of the terraform block, or "".
+func LoadRequiredVersion(dir string) (string, error) {
+	var constraint string
+
+	err := forEachConfigBlock(dir, "terraform", func(block *hclsyntax.Block) {
+		if v := staticStringAttribute(block.Body, "required_version"); v != "" {
+			constraint = v
+		}
+	})
+
+	return constraint, err
+}
+
+// LoadVariableNames returns the names of the variable blocks in the *.tf files.
+func LoadVariableNames(dir string) ([]string, error) {
+	var names []string
+
+	err := forEachConfigBlock(dir, "variable", func(block *hclsyntax.Block) {
+		if len(block.Labels) == 1 {
+			names = append(names, block.Labels[0])
+		}
+	})
+
+	return names, err
+}
+
+// LoadTFVarsNames parses a .tfvars file and returns the variables it sets.
+func LoadTFVarsNames(path string) ([]string, error) {
+	body, err := parseHCLFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(body.Attributes))
+	for name := range body.Attributes {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	return names, nil
+}
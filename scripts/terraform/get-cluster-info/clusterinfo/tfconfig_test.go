@@ -0,0 +1,214 @@
+package clusterinfo
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// =============================================================================
+// LoadOutputDescriptions tests
+// =============================================================================
+
+func TestLoadOutputDescriptions(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	must(t, os.WriteFile(filepath.Join(tmpDir, "outputs.tf"), []byte(`
+output "control_plane_public_ip" {
+  description = "IP publique du control-plane"
+  value       = scaleway_instance_ip.nodes_ips["control-plane"].address
+}
+
+output "no_description" {
+  value = "x"
+}
+
+output "dynamic_description" {
+  description = "prefix-${var.project_name}"
+  value       = "x"
+}
+
+resource "random_id" "ignored" {
+  byte_length = 4
+}
+`), 0o600))
+
+	descriptions, err := LoadOutputDescriptions(tmpDir)
+	if err != nil {
+		t.Fatalf("LoadOutputDescriptions() unexpected error = %v", err)
+	}
+
+	expected := map[string]string{"control_plane_public_ip": "IP publique du control-plane"}
+	if !reflect.DeepEqual(descriptions, expected) {
+		t.Errorf("LoadOutputDescriptions() = %v, want %v", descriptions, expected)
+	}
+}
+
+// =============================================================================
+// LoadVariableValue tests
+// =============================================================================
+
+func TestLoadVariableValue(t *testing.T) {
+	t.Parallel()
+
+	variables := `
+variable "private_network_cidr" {
+  type    = string
+  default = "10.0.0.0/24"
+}
+
+variable "no_default" {
+  type = string
+}
+`
+
+	tests := []struct {
+		name     string
+		tfvars   string
+		variable string
+		expected string
+	}{
+		{
+			name:     "default from variable block",
+			variable: "private_network_cidr",
+			expected: "10.0.0.0/24",
+		},
+		{
+			name:     "terraform.tfvars overrides default",
+			tfvars:   `private_network_cidr = "192.168.0.0/24"`,
+			variable: "private_network_cidr",
+			expected: "192.168.0.0/24",
+		},
+		{
+			name:     "tfvars without the variable - falls back to default",
+			tfvars:   `project_name = "lab"`,
+			variable: "private_network_cidr",
+			expected: "10.0.0.0/24",
+		},
+		{
+			name:     "variable without default - empty",
+			variable: "no_default",
+			expected: "",
+		},
+		{
+			name:     "unknown variable - empty",
+			variable: "unknown",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tmpDir := t.TempDir()
+			must(t, os.WriteFile(filepath.Join(tmpDir, "variables.tf"), []byte(variables), 0o600))
+
+			if tt.tfvars != "" {
+				must(t, os.WriteFile(filepath.Join(tmpDir, TFVarsFile), []byte(tt.tfvars), 0o600))
+			}
+
+			got, err := LoadVariableValue(tmpDir, tt.variable)
+			if err != nil {
+				t.Fatalf("LoadVariableValue() unexpected error = %v", err)
+			}
+
+			if got != tt.expected {
+				t.Errorf("LoadVariableValue() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+// =============================================================================
+// LoadRequiredVersion / LoadVariableNames / LoadTFVarsNames tests
+// =============================================================================
+
+func TestLoadRequiredVersion(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	must(t, os.WriteFile(filepath.Join(tmpDir, "main.tf"), []byte(`
+terraform {
+  required_version = ">= 1.6"
+}
+`), 0o600))
+
+	got, err := LoadRequiredVersion(tmpDir)
+	if err != nil {
+		t.Fatalf("LoadRequiredVersion() unexpected error = %v", err)
+	}
+
+	if got != ">= 1.6" {
+		t.Errorf("LoadRequiredVersion() = %q, want %q", got, ">= 1.6")
+	}
+}
+
+func TestLoadVariableNames(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	must(t, os.WriteFile(filepath.Join(tmpDir, "variables.tf"), []byte(`
+variable "project_name" {}
+variable "worker_flavor" {}
+`), 0o600))
+
+	got, err := LoadVariableNames(tmpDir)
+	if err != nil {
+		t.Fatalf("LoadVariableNames() unexpected error = %v", err)
+	}
+
+	if expected := []string{"project_name", "worker_flavor"}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("LoadVariableNames() = %v, want %v", got, expected)
+	}
+}
+
+func TestLoadTFVarsNames(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		content  string
+		expected []string
+		wantErr  bool
+	}{
+		{
+			name:     "valid file - sorted names",
+			content:  "worker_flavor = \"DEV1-M\"\nproject_name = \"lab\"\n",
+			expected: []string{"project_name", "worker_flavor"},
+		},
+		{
+			name:    "syntax error - returns error",
+			content: "project_name = \n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), TFVarsFile)
+			must(t, os.WriteFile(path, []byte(tt.content), 0o600))
+
+			got, err := LoadTFVarsNames(path)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("LoadTFVarsNames() expected error, got nil")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("LoadTFVarsNames() unexpected error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("LoadTFVarsNames() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
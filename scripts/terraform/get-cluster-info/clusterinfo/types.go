@@ -0,0 +1,99 @@
+package clusterinfo
+
+import "path/filepath"
+
+// =============================================================================
+// Types
+// =============================================================================
+
+// Node roles.
+const (
+	RoleControlPlane = "control-plane"
+	RoleWorker       = "worker"
+)
+
+// ClusterInfo contains cluster information.
+// JSON tags use snake_case for consistency with Terraform outputs.
+type ClusterInfo struct {
+	ControlPlane NodeInfo `json:"control_plane"` //nolint:tagliatelle
+	Worker       NodeInfo `json:"worker"`
+	Nodes        []Node   `json:"nodes,omitempty"`
+	SSHKeyPath   string   `json:"ssh_key_path"` //nolint:tagliatelle
+	// Lifetime is the TTL of the lab, if one was set (see LifetimeStore).
+	Lifetime *Lifetime `json:"lifetime,omitempty"`
+}
+
+// NodeInfo contains node IP addresses.
+// JSON tags use snake_case for consistency with Terraform outputs.
+type NodeInfo struct {
+	PublicIP  string `json:"public_ip"`  //nolint:tagliatelle
+	PrivateIP string `json:"private_ip"` //nolint:tagliatelle
+}
+
+// Node is a named cluster node. ControlPlane and Worker are the first node of each role.
+type Node struct {
+	Name string `json:"name"`
+	Role string `json:"role"`
+	NodeInfo
+}
+
+// AllNodes returns every node, falling back to ControlPlane and Worker when Nodes is not set.
+func (c *ClusterInfo) AllNodes() []Node {
+	if len(c.Nodes) > 0 {
+		return c.Nodes
+	}
+
+	return []Node{
+		{Name: RoleControlPlane, Role: RoleControlPlane, NodeInfo: c.ControlPlane},
+		{Name: RoleWorker, Role: RoleWorker, NodeInfo: c.Worker},
+	}
+}
+
+// Redacted replaces the values removed by ClusterInfo.Redacted.
+const Redacted = "<redacted>"
+
+// RedactedPublicIP replaces the public IP of a node in redacted output.
+func RedactedPublicIP(node string) string {
+	return "<public-ip:" + node + ">"
+}
+
+// Redacted returns a copy that can be shared: the public IPs are replaced by
+// RedactedPublicIP, the SSH key path is reduced to its file name and the
+// author of the lifetime is removed. Private IPs are kept.
+func (c *ClusterInfo) Redacted() ClusterInfo {
+	r := *c
+
+	r.ControlPlane.PublicIP = redactIP(c.ControlPlane.PublicIP, RoleControlPlane)
+	r.Worker.PublicIP = redactIP(c.Worker.PublicIP, RoleWorker)
+
+	if c.Nodes != nil {
+		r.Nodes = make([]Node, len(c.Nodes))
+		for i, n := range c.Nodes {
+			n.PublicIP = redactIP(n.PublicIP, n.Name)
+			r.Nodes[i] = n
+		}
+	}
+
+	if c.SSHKeyPath != "" {
+		r.SSHKeyPath = filepath.Base(c.SSHKeyPath)
+	}
+
+	if c.Lifetime != nil {
+		l := *c.Lifetime
+		if l.SetBy != "" {
+			l.SetBy = Redacted
+		}
+
+		r.Lifetime = &l
+	}
+
+	return r
+}
+
+func redactIP(ip, node string) string {
+	if ip == "" {
+		return ""
+	}
+
+	return RedactedPublicIP(node)
+}
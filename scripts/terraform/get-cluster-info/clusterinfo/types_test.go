@@ -0,0 +1,202 @@
+package clusterinfo
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// =============================================================================
+// ClusterInfo JSON marshaling tests
+// =============================================================================
+
+func TestClusterInfoJSON(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		input          ClusterInfo
+		wantFields     []string // Expected field names in JSON
+		wantPublicIP   string
+		wantPrivateIP  string
+		wantSSHKeyPath string
+	}{
+		{
+			name: "marshals with snake_case field names",
+			input: ClusterInfo{
+				ControlPlane: NodeInfo{
+					PublicIP:  "1.2.3.4",
+					PrivateIP: "10.0.0.1",
+				},
+				Worker: NodeInfo{
+					PublicIP:  "5.6.7.8",
+					PrivateIP: "10.0.0.2",
+				},
+				SSHKeyPath: "/home/user/.ssh/key.pem",
+			},
+			wantFields:     []string{"control_plane", "worker", "ssh_key_path"},
+			wantPublicIP:   "1.2.3.4",
+			wantPrivateIP:  "10.0.0.1",
+			wantSSHKeyPath: "/home/user/.ssh/key.pem",
+		},
+		{
+			name: "handles empty values",
+			input: ClusterInfo{
+				ControlPlane: NodeInfo{},
+				Worker:       NodeInfo{},
+				SSHKeyPath:   "",
+			},
+			wantFields:     []string{"control_plane", "worker", "ssh_key_path"},
+			wantPublicIP:   "",
+			wantPrivateIP:  "",
+			wantSSHKeyPath: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			data, err := json.Marshal(tt.input)
+			if err != nil {
+				t.Fatalf("json.Marshal() error = %v", err)
+			}
+
+			var result map[string]any
+			if err := json.Unmarshal(data, &result); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+
+			// Check expected field names
+			for _, field := range tt.wantFields {
+				if _, ok := result[field]; !ok {
+					t.Errorf("expected field %q in JSON output", field)
+				}
+			}
+
+			// Check nested fields
+			if cp, ok := result["control_plane"].(map[string]any); ok {
+				if _, ok := cp["public_ip"]; !ok {
+					t.Error("expected 'public_ip' field in control_plane")
+				}
+
+				if _, ok := cp["private_ip"]; !ok {
+					t.Error("expected 'private_ip' field in control_plane")
+				}
+			}
+		})
+	}
+}
+
+// =============================================================================
+// Credentials JSON tests
+// =============================================================================
+
+func TestCredentialsJSON(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		jsonInput     string
+		wantAccessKey string
+		wantSecretKey string
+		wantErr       bool
+	}{
+		{
+			name:          "unmarshals valid credentials",
+			jsonInput:     `{"access_key": "AKIA123", "secret_key": "secret456"}`,
+			wantAccessKey: "AKIA123",
+			wantSecretKey: "secret456",
+			wantErr:       false,
+		},
+		{
+			name:          "handles extra fields gracefully",
+			jsonInput:     `{"access_key": "AKIA123", "secret_key": "secret456", "extra": "ignored"}`,
+			wantAccessKey: "AKIA123",
+			wantSecretKey: "secret456",
+			wantErr:       false,
+		},
+		{
+			name:          "handles missing fields as empty strings",
+			jsonInput:     `{}`,
+			wantAccessKey: "",
+			wantSecretKey: "",
+			wantErr:       false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var creds Credentials
+
+			err := json.Unmarshal([]byte(tt.jsonInput), &creds)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Errorf("unexpected error = %v", err)
+
+				return
+			}
+
+			if creds.AccessKey != tt.wantAccessKey {
+				t.Errorf("AccessKey = %q, want %q", creds.AccessKey, tt.wantAccessKey)
+			}
+
+			if creds.SecretKey != tt.wantSecretKey {
+				t.Errorf("SecretKey = %q, want %q", creds.SecretKey, tt.wantSecretKey)
+			}
+		})
+	}
+}
+
+func TestClusterInfoRedacted(t *testing.T) {
+	t.Parallel()
+
+	l := Lifetime{SetBy: "alice"}
+	info := ClusterInfo{
+		ControlPlane: NodeInfo{PublicIP: "203.0.113.10", PrivateIP: "10.0.0.10"},
+		Nodes: []Node{
+			{Name: "control-plane", Role: RoleControlPlane, NodeInfo: NodeInfo{
+				PublicIP: "203.0.113.10", PrivateIP: "10.0.0.10",
+			}},
+			{Name: "worker-2", Role: RoleWorker, NodeInfo: NodeInfo{PublicIP: "203.0.113.12", PrivateIP: "10.0.0.12"}},
+		},
+		SSHKeyPath: "/home/alice/.ssh/k8s-lab.pem",
+		Lifetime:   &l,
+	}
+
+	r := info.Redacted()
+
+	data, err := json.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, secret := range []string{"203.0.113.", "alice"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("redacted JSON contains %q: %s", secret, data)
+		}
+	}
+
+	if r.Nodes[1].PublicIP != "<public-ip:worker-2>" || r.Nodes[1].PrivateIP != "10.0.0.12" {
+		t.Errorf("worker-2 = %+v, want the public IP redacted and the private IP kept", r.Nodes[1])
+	}
+
+	if r.Worker.PublicIP != "" || r.SSHKeyPath != "k8s-lab.pem" {
+		t.Errorf("redacted = %+v", r)
+	}
+
+	// The original is untouched.
+	if info.Nodes[1].PublicIP != "203.0.113.12" || info.Lifetime.SetBy != "alice" {
+		t.Errorf("Redacted() modified the original: %+v", info)
+	}
+}
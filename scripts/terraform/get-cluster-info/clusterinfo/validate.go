@@ -0,0 +1,319 @@
+package clusterinfo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/netip"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-exec/tfexec"
+)
+
+// =============================================================================
+// Output validation
+// =============================================================================
+//
+// Every expected output must be present, a string, and a valid IP address.
+// Private IPs must also belong to the private network (private_network_cidr).
+// All problems are collected so that a half-applied state is explained at once.
+
+const PrivateNetworkCIDRVar = "private_network_cidr"
+
+var (
+	ErrOutputMissing   = errors.New("output is missing")
+	ErrOutputNotString = errors.New("output is not a string")
+	ErrOutputEmpty     = errors.New("output is empty")
+	ErrInvalidIP       = errors.New("not a valid IP address")
+	ErrOutsideNetwork  = errors.New("outside the private network")
+	ErrOutputNotNodes  = errors.New("output is not a map of nodes")
+	ErrNoControlPlane  = errors.New("no control-plane node discovered (expected e.g. control_plane_public_ip)")
+)
+
+// ValidationError lists every problem found in the Terraform outputs.
+type ValidationError struct {
+	Problems []error
+}
+
+func (e *ValidationError) Error() string {
+	lines := make([]string, 0, len(e.Problems)+1)
+	lines = append(lines, fmt.Sprintf("invalid terraform outputs (%d problem(s)):", len(e.Problems)))
+
+	for _, p := range e.Problems {
+		lines = append(lines, "  - "+p.Error())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Unwrap allows errors.Is on individual problems.
+func (e *ValidationError) Unwrap() []error {
+	return e.Problems
+}
+
+// fillClusterInfo reads and validates the IP outputs named by mapping into info.
+// Valid values are always filled in, even when other outputs are invalid,
+// so that --lenient can still display what is available.
+// An invalid privateNetwork (zero value) disables the network membership check.
+func fillClusterInfo(
+	info *ClusterInfo,
+	outputs map[string]tfexec.OutputMeta,
+	mapping map[string]string,
+	privateNetwork netip.Prefix,
+) error {
+	var problems []error
+
+	info.ControlPlane, problems = readNodeIPs(outputs,
+		mapping[FieldControlPlanePublicIP], mapping[FieldControlPlanePrivateIP], privateNetwork, problems)
+	info.Worker, problems = readNodeIPs(outputs,
+		mapping[FieldWorkerPublicIP], mapping[FieldWorkerPrivateIP], privateNetwork, problems)
+
+	nodes, ok, problems := readNodesOutput(outputs, mapping[FieldNodes], privateNetwork, problems)
+	if !ok {
+		nodes = []Node{
+			{Name: RoleControlPlane, Role: RoleControlPlane, NodeInfo: info.ControlPlane},
+			{Name: RoleWorker, Role: RoleWorker, NodeInfo: info.Worker},
+		}
+	}
+
+	info.Nodes = nodes
+
+	return validationResult(problems)
+}
+
+// discoverClusterInfo infers the nodes from the nodes output, else from the
+// <name>_public_ip / <name>_private_ip pairs. The first control-plane and the
+// first worker (by name) fill ControlPlane and Worker.
+func discoverClusterInfo(
+	info *ClusterInfo,
+	outputs map[string]tfexec.OutputMeta,
+	nodesOutput string,
+	privateNetwork netip.Prefix,
+) error {
+	nodes, ok, problems := readNodesOutput(outputs, nodesOutput, privateNetwork, nil)
+	if !ok {
+		nodes = []Node{}
+
+		for _, name := range discoverNodeNames(outputs) {
+			var ips NodeInfo
+
+			ips, problems = readNodeIPs(outputs, name+publicIPSuffix, name+privateIPSuffix, privateNetwork, problems)
+			nodes = append(nodes, Node{Name: strings.ReplaceAll(name, "_", "-"), Role: roleFromName(name), NodeInfo: ips})
+		}
+	}
+
+	info.Nodes = nodes
+
+	for _, node := range nodes {
+		switch {
+		case node.Role == RoleControlPlane && info.ControlPlane == (NodeInfo{}):
+			info.ControlPlane = node.NodeInfo
+		case node.Role == RoleWorker && info.Worker == (NodeInfo{}):
+			info.Worker = node.NodeInfo
+		}
+	}
+
+	if len(info.Nodes) == 0 {
+		problems = append(problems, fmt.Errorf("no <name>%s output found: %w", publicIPSuffix, ErrOutputMissing))
+	} else if !hasRole(info.Nodes, RoleControlPlane) {
+		problems = append(problems, ErrNoControlPlane)
+	}
+
+	return validationResult(problems)
+}
+
+// nodesOutputEntry is an entry of the nodes output: a node of local.nodes and its IPs.
+type nodesOutputEntry struct {
+	Role      string `json:"role"`
+	PublicIP  string `json:"public_ip"`  //nolint:tagliatelle
+	PrivateIP string `json:"private_ip"` //nolint:tagliatelle
+}
+
+// readNodesOutput reads the nodes output, one entry per node of local.nodes,
+// sorted by name. ok is false when the state has no such output (older module).
+// Problems are appended to problems; the valid IPs are always filled in.
+func readNodesOutput(
+	outputs map[string]tfexec.OutputMeta,
+	key string,
+	privateNetwork netip.Prefix,
+	problems []error,
+) ([]Node, bool, []error) {
+	v, ok := outputs[key]
+	if !ok || key == "" {
+		return nil, false, problems
+	}
+
+	var entries map[string]nodesOutputEntry
+	if err := json.Unmarshal(v.Value, &entries); err != nil {
+		return nil, true, append(problems,
+			fmt.Errorf("%s: %w (got %s)", key, ErrOutputNotNodes, truncate(string(v.Value), 40)))
+	}
+
+	nodes := make([]Node, 0, len(entries))
+
+	for _, name := range slices.Sorted(maps.Keys(entries)) {
+		entry := entries[name]
+
+		role := entry.Role
+		if role == "" {
+			role = roleFromName(name)
+		}
+
+		node := Node{Name: name, Role: role}
+
+		if value, err := parseIP(entry.PublicIP); err != nil {
+			problems = append(problems, fmt.Errorf("%s[%q].public_ip: %w", key, name, err))
+		} else {
+			node.PublicIP = value
+		}
+
+		value, err := parseIP(entry.PrivateIP)
+		if err == nil && privateNetwork.IsValid() {
+			err = checkInNetwork(value, privateNetwork)
+		}
+
+		if err != nil {
+			problems = append(problems, fmt.Errorf("%s[%q].private_ip: %w", key, name, err))
+		} else {
+			node.PrivateIP = value
+		}
+
+		nodes = append(nodes, node)
+	}
+
+	return nodes, true, problems
+}
+
+// readNodeIPs reads a public/private IP pair, appending any problem to problems.
+func readNodeIPs(
+	outputs map[string]tfexec.OutputMeta,
+	publicName, privateName string,
+	privateNetwork netip.Prefix,
+	problems []error,
+) (NodeInfo, []error) {
+	var ips NodeInfo
+
+	if value, err := readIPOutput(outputs, publicName); err != nil {
+		problems = append(problems, fmt.Errorf("%s: %w", publicName, err))
+	} else {
+		ips.PublicIP = value
+	}
+
+	value, err := readIPOutput(outputs, privateName)
+	if err == nil && privateNetwork.IsValid() {
+		err = checkInNetwork(value, privateNetwork)
+	}
+
+	if err != nil {
+		problems = append(problems, fmt.Errorf("%s: %w", privateName, err))
+	} else {
+		ips.PrivateIP = value
+	}
+
+	return ips, problems
+}
+
+func validationResult(problems []error) error {
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+
+	return nil
+}
+
+func hasRole(nodes []Node, role string) bool {
+	for _, n := range nodes {
+		if n.Role == role {
+			return true
+		}
+	}
+
+	return false
+}
+
+// readStringOutput returns a string output, with an explicit error when it is missing or mistyped.
+func readStringOutput(outputs map[string]tfexec.OutputMeta, key string) (string, error) {
+	v, ok := outputs[key]
+	if !ok {
+		return "", ErrOutputMissing
+	}
+
+	var result string
+	if err := json.Unmarshal(v.Value, &result); err != nil {
+		return "", fmt.Errorf("%w (got %s)", ErrOutputNotString, truncate(string(v.Value), 40))
+	}
+
+	if result == "" {
+		return "", ErrOutputEmpty
+	}
+
+	return result, nil
+}
+
+// readIPOutput returns a string output holding a valid IPv4 or IPv6 address.
+func readIPOutput(outputs map[string]tfexec.OutputMeta, key string) (string, error) {
+	value, err := readStringOutput(outputs, key)
+	if err != nil {
+		return "", err
+	}
+
+	return parseIP(value)
+}
+
+// parseIP returns value if it is a valid IPv4 or IPv6 address.
+func parseIP(value string) (string, error) {
+	if value == "" {
+		return "", ErrOutputEmpty
+	}
+
+	if _, err := netip.ParseAddr(value); err != nil {
+		return "", fmt.Errorf("%q is %w", value, ErrInvalidIP)
+	}
+
+	return value, nil
+}
+
+func checkInNetwork(value string, network netip.Prefix) error {
+	addr, err := netip.ParseAddr(value)
+	if err != nil {
+		return fmt.Errorf("%q is %w", value, ErrInvalidIP)
+	}
+
+	if !network.Contains(addr.Unmap()) {
+		return fmt.Errorf("%s is %w %s", value, ErrOutsideNetwork, network)
+	}
+
+	return nil
+}
+
+// LoadPrivateNetwork resolves private_network_cidr from terraform.tfvars or its default value.
+// It returns an invalid (zero) prefix, and no error, when the variable cannot be resolved statically.
+func LoadPrivateNetwork(dir string) (netip.Prefix, error) {
+	value, err := LoadVariableValue(dir, PrivateNetworkCIDRVar)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("failed to read %s: %w", PrivateNetworkCIDRVar, err)
+	}
+
+	if value == "" {
+		return netip.Prefix{}, nil
+	}
+
+	prefix, err := netip.ParsePrefix(value)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("invalid %s %q: %w", PrivateNetworkCIDRVar, value, err)
+	}
+
+	return prefix.Masked(), nil
+}
+
+// truncate keeps the first n runes of s.
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+
+	return string(runes[:n]) + "…"
+}
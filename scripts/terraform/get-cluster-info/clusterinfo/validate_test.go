@@ -0,0 +1,324 @@
+package clusterinfo
+
+import (
+	"encoding/json"
+	"errors"
+	"maps"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/hashicorp/terraform-exec/tfexec"
+)
+
+// =============================================================================
+// fillClusterInfo tests
+// =============================================================================
+
+func TestFillClusterInfo(t *testing.T) {
+	t.Parallel()
+
+	validOutputs := func() map[string]tfexec.OutputMeta {
+		return map[string]tfexec.OutputMeta{
+			"control_plane_public_ip":  {Value: json.RawMessage(`"51.15.1.1"`)},
+			"control_plane_private_ip": {Value: json.RawMessage(`"10.0.0.10"`)},
+			"worker_public_ip":         {Value: json.RawMessage(`"2001:db8::1"`)},
+			"worker_private_ip":        {Value: json.RawMessage(`"10.0.0.11"`)},
+		}
+	}
+	network := netip.MustParsePrefix("10.0.0.0/24")
+
+	tests := []struct {
+		name         string
+		mutate       func(outputs map[string]tfexec.OutputMeta)
+		network      netip.Prefix
+		wantProblems []error
+		wantWorkerIP string
+	}{
+		{
+			name:         "all outputs valid - no error",
+			mutate:       func(map[string]tfexec.OutputMeta) {},
+			network:      network,
+			wantWorkerIP: "2001:db8::1",
+		},
+		{
+			name: "missing worker outputs - one problem per output",
+			mutate: func(o map[string]tfexec.OutputMeta) {
+				delete(o, "worker_public_ip")
+				delete(o, "worker_private_ip")
+			},
+			network:      network,
+			wantProblems: []error{ErrOutputMissing, ErrOutputMissing},
+		},
+		{
+			name: "number instead of string",
+			mutate: func(o map[string]tfexec.OutputMeta) {
+				o["worker_public_ip"] = tfexec.OutputMeta{Value: json.RawMessage(`42`)}
+			},
+			network:      network,
+			wantProblems: []error{ErrOutputNotString},
+		},
+		{
+			name: "empty string",
+			mutate: func(o map[string]tfexec.OutputMeta) {
+				o["worker_public_ip"] = tfexec.OutputMeta{Value: json.RawMessage(`""`)}
+			},
+			network:      network,
+			wantProblems: []error{ErrOutputEmpty},
+		},
+		{
+			name: "invalid IP address",
+			mutate: func(o map[string]tfexec.OutputMeta) {
+				o["control_plane_public_ip"] = tfexec.OutputMeta{Value: json.RawMessage(`"not-an-ip"`)}
+			},
+			network:      network,
+			wantProblems: []error{ErrInvalidIP},
+			wantWorkerIP: "2001:db8::1",
+		},
+		{
+			name: "private IP outside private_network_cidr",
+			mutate: func(o map[string]tfexec.OutputMeta) {
+				o["worker_private_ip"] = tfexec.OutputMeta{Value: json.RawMessage(`"192.168.1.5"`)}
+			},
+			network:      network,
+			wantProblems: []error{ErrOutsideNetwork},
+			wantWorkerIP: "2001:db8::1",
+		},
+		{
+			name: "unknown network - membership not checked",
+			mutate: func(o map[string]tfexec.OutputMeta) {
+				o["worker_private_ip"] = tfexec.OutputMeta{Value: json.RawMessage(`"192.168.1.5"`)}
+			},
+			network:      netip.Prefix{},
+			wantWorkerIP: "2001:db8::1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			outputs := validOutputs()
+			tt.mutate(outputs)
+
+			info := &ClusterInfo{}
+			err := fillClusterInfo(info, outputs, DefaultOutputMap(), tt.network)
+
+			if len(tt.wantProblems) == 0 {
+				if err != nil {
+					t.Fatalf("fillClusterInfo() unexpected error = %v", err)
+				}
+			} else {
+				var verr *ValidationError
+				if !errors.As(err, &verr) {
+					t.Fatalf("fillClusterInfo() error = %v, want *ValidationError", err)
+				}
+
+				if len(verr.Problems) != len(tt.wantProblems) {
+					t.Fatalf("got %d problem(s), want %d: %v", len(verr.Problems), len(tt.wantProblems), err)
+				}
+
+				for i, want := range tt.wantProblems {
+					if !errors.Is(verr.Problems[i], want) {
+						t.Errorf("problem[%d] = %v, want %v", i, verr.Problems[i], want)
+					}
+				}
+			}
+
+			if info.Worker.PublicIP != tt.wantWorkerIP {
+				t.Errorf("Worker.PublicIP = %q, want %q", info.Worker.PublicIP, tt.wantWorkerIP)
+			}
+		})
+	}
+}
+
+func TestFillClusterInfoNodesOutput(t *testing.T) {
+	t.Parallel()
+
+	outputs := map[string]tfexec.OutputMeta{
+		"control_plane_public_ip":  {Value: json.RawMessage(`"51.15.1.1"`)},
+		"control_plane_private_ip": {Value: json.RawMessage(`"10.0.0.10"`)},
+		"worker_public_ip":         {Value: json.RawMessage(`"51.15.1.2"`)},
+		"worker_private_ip":        {Value: json.RawMessage(`"10.0.0.11"`)},
+	}
+	network := netip.MustParsePrefix("10.0.0.0/24")
+
+	tests := []struct {
+		name         string
+		nodes        string
+		wantNodes    []Node
+		wantProblems []error
+	}{
+		{
+			name: "no nodes output - control-plane and worker",
+			wantNodes: []Node{
+				{Name: "control-plane", Role: RoleControlPlane, NodeInfo: NodeInfo{"51.15.1.1", "10.0.0.10"}},
+				{Name: "worker", Role: RoleWorker, NodeInfo: NodeInfo{"51.15.1.2", "10.0.0.11"}},
+			},
+		},
+		{
+			name: "extra worker",
+			nodes: `{"worker-2": {"role": "worker", "public_ip": "51.15.1.3", "private_ip": "10.0.0.12"},
+"control-plane": {"role": "control-plane", "public_ip": "51.15.1.1", "private_ip": "10.0.0.10"}}`,
+			wantNodes: []Node{
+				{Name: "control-plane", Role: RoleControlPlane, NodeInfo: NodeInfo{"51.15.1.1", "10.0.0.10"}},
+				{Name: "worker-2", Role: RoleWorker, NodeInfo: NodeInfo{"51.15.1.3", "10.0.0.12"}},
+			},
+		},
+		{
+			name:  "role guessed from the name",
+			nodes: `{"master": {"public_ip": "51.15.1.1", "private_ip": "10.0.0.10"}}`,
+			wantNodes: []Node{
+				{Name: "master", Role: RoleControlPlane, NodeInfo: NodeInfo{"51.15.1.1", "10.0.0.10"}},
+			},
+		},
+		{
+			name:  "invalid IPs",
+			nodes: `{"worker-2": {"role": "worker", "public_ip": "", "private_ip": "192.168.1.5"}}`,
+			wantNodes: []Node{
+				{Name: "worker-2", Role: RoleWorker},
+			},
+			wantProblems: []error{ErrOutputEmpty, ErrOutsideNetwork},
+		},
+		{
+			name:         "not a map",
+			nodes:        `"51.15.1.1"`,
+			wantNodes:    []Node{},
+			wantProblems: []error{ErrOutputNotNodes},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			withNodes := maps.Clone(outputs)
+			if tt.nodes != "" {
+				withNodes["nodes"] = tfexec.OutputMeta{Value: json.RawMessage(tt.nodes)}
+			}
+
+			info := &ClusterInfo{}
+			err := fillClusterInfo(info, withNodes, DefaultOutputMap(), network)
+
+			var problems []error
+
+			var verr *ValidationError
+			if errors.As(err, &verr) {
+				problems = verr.Problems
+			} else if err != nil {
+				t.Fatalf("fillClusterInfo() error = %v, want *ValidationError", err)
+			}
+
+			if len(problems) != len(tt.wantProblems) {
+				t.Fatalf("got %d problem(s), want %d: %v", len(problems), len(tt.wantProblems), err)
+			}
+
+			for i, want := range tt.wantProblems {
+				if !errors.Is(problems[i], want) {
+					t.Errorf("problem[%d] = %v, want %v", i, problems[i], want)
+				}
+			}
+
+			if !slices.Equal(info.Nodes, tt.wantNodes) {
+				t.Errorf("Nodes = %+v, want %+v", info.Nodes, tt.wantNodes)
+			}
+
+			if info.Worker.PublicIP != "51.15.1.2" {
+				t.Errorf("Worker.PublicIP = %q, want the worker_public_ip output", info.Worker.PublicIP)
+			}
+		})
+	}
+}
+
+func TestValidationErrorMessage(t *testing.T) {
+	t.Parallel()
+
+	err := &ValidationError{Problems: []error{
+		errors.New("worker_public_ip: output is missing"),
+		errors.New("worker_private_ip: output is missing"),
+	}}
+
+	msg := err.Error()
+	for _, want := range []string{"2 problem(s)", "  - worker_public_ip", "  - worker_private_ip"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("Error() = %q, want it to contain %q", msg, want)
+		}
+	}
+}
+
+// =============================================================================
+// LoadPrivateNetwork tests
+// =============================================================================
+
+func TestLoadPrivateNetwork(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		tfvars   string
+		expected netip.Prefix
+		wantErr  bool
+	}{
+		{name: "no tfvars - no network", expected: netip.Prefix{}},
+		{name: "valid CIDR - masked prefix", tfvars: `private_network_cidr = "10.0.0.7/24"`, expected: netip.MustParsePrefix("10.0.0.0/24")},
+		{name: "invalid CIDR - error", tfvars: `private_network_cidr = "10.0.0.0"`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tmpDir := t.TempDir()
+			if tt.tfvars != "" {
+				must(t, os.WriteFile(filepath.Join(tmpDir, TFVarsFile), []byte(tt.tfvars), 0o600))
+			}
+
+			got, err := LoadPrivateNetwork(tmpDir)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("LoadPrivateNetwork() expected error, got nil")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("LoadPrivateNetwork() unexpected error = %v", err)
+			}
+
+			if got != tt.expected {
+				t.Errorf("LoadPrivateNetwork() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in   string
+		n    int
+		want string
+	}{
+		{in: "short", n: 10, want: "short"},
+		{in: "abcdef", n: 3, want: "abc…"},
+		{in: "ééééé", n: 4, want: "éééé…"},
+		{in: "🔑🔑🔑", n: 1, want: "🔑…"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			t.Parallel()
+
+			got := truncate(tt.in, tt.n)
+			if got != tt.want || !utf8.ValidString(got) {
+				t.Errorf("truncate(%q, %d) = %q, want %q", tt.in, tt.n, got, tt.want)
+			}
+		})
+	}
+}
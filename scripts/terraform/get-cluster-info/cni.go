@@ -0,0 +1,193 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/k8s-lab/get-cluster-info/cni"
+	"github.com/k8s-lab/get-cluster-info/i18n"
+	"github.com/k8s-lab/get-cluster-info/render"
+	"github.com/spf13/cobra"
+)
+
+// =============================================================================
+// CNI subcommands
+// =============================================================================
+//
+// Installs Cilium with helm on the control plane (over SSH, like bootstrap),
+// then waits until the agent of every node is ready, and renders the result
+// like `cilium status`.
+
+var (
+	cniVersion string
+	cniValues  string
+	cniTimeout time.Duration
+	// cniPollInterval is the delay between two status checks while waiting.
+	cniPollInterval = 5 * time.Second
+)
+
+var cniCmd = &cobra.Command{
+	Use:   "cni",
+	Short: "Install and check the CNI (Cilium)",
+	Args:  cobra.NoArgs,
+}
+
+var cniInstallCmd = &cobra.Command{
+	Use:   "install cilium",
+	Short: "Install Cilium and wait for its agents",
+	Long: `Install Cilium and wait for its agents.
+
+Runs "helm upgrade --install" on the control plane with the pinned chart
+version and the bundled values (resources/installK8s.md, plus Hubble), then
+waits until the cilium agent of every node is ready. Run it after
+"get-cluster-info bootstrap"; running it again upgrades the release.
+
+Examples:
+  # Install the pinned version
+  get-cluster-info cni install cilium
+
+  # Another version, with extra Helm values
+  get-cluster-info cni install cilium --version 1.18.5 --values my-values.yaml`,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{"cilium"},
+	RunE:      runCNIInstall,
+}
+
+var cniStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the Cilium workloads and agents",
+	Args:  cobra.NoArgs,
+	RunE:  runCNIStatus,
+}
+
+func init() {
+	cniInstallCmd.Flags().StringVar(&cniVersion, "version", cni.CiliumVersion, "Cilium chart version")
+	cniInstallCmd.Flags().StringVar(&cniValues, "values", "",
+		"Helm values file applied over the bundled values (cni/cilium-values.yaml)")
+	cniInstallCmd.Flags().DurationVar(&cniTimeout, "timeout", 5*time.Minute, "How long to wait for the agents")
+
+	cniCmd.AddCommand(cniInstallCmd, cniStatusCmd)
+	rootCmd.AddCommand(cniCmd)
+}
+
+func runCNIInstall(_ *cobra.Command, args []string) error {
+	if args[0] != "cilium" {
+		return i18n.Errorf("%w: %q", cni.ErrUnknownCNI, args[0])
+	}
+
+	var values []byte
+
+	if cniValues != "" {
+		var err error
+		if values, err = os.ReadFile(cniValues); err != nil {
+			return i18n.Errorf("failed to read --values: %w", err)
+		}
+	}
+
+	ctx := context.Background()
+
+	info, dialer, closeSrc, err := openCluster(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer closeSrc()
+
+	client, err := dialer.Dial(ctx, info.ControlPlane.PublicIP)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = client.Close() }()
+
+	logInfo("Installing Cilium %s from the control plane...", cniVersion)
+
+	err = cni.Install(ctx, client, cni.InstallOptions{
+		Version:       cniVersion,
+		Values:        values,
+		APIServerHost: info.ControlPlane.PrivateIP,
+	})
+	if err != nil {
+		return err
+	}
+
+	logSuccess("Cilium %s installed", cniVersion)
+	logInfo("Waiting for the cilium agents (timeout %s)...", cniTimeout)
+
+	waitCtx, cancel := context.WithTimeout(ctx, cniTimeout)
+	defer cancel()
+
+	ready := -1
+
+	status, err := cni.Wait(waitCtx, client, cniPollInterval, func(s cni.Status) {
+		if n := readyAgents(s); n != ready {
+			ready = n
+			logInfo("%d/%d agent(s) ready", n, len(s.Agents))
+		}
+	})
+
+	if renderErr := printCiliumStatus(status); renderErr != nil {
+		return renderErr
+	}
+
+	return err
+}
+
+func runCNIStatus(_ *cobra.Command, _ []string) error {
+	ctx := context.Background()
+
+	info, dialer, closeSrc, err := openCluster(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer closeSrc()
+
+	client, err := dialer.Dial(ctx, info.ControlPlane.PublicIP)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = client.Close() }()
+
+	status, err := cni.GetStatus(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	if err := printCiliumStatus(status); err != nil {
+		return err
+	}
+
+	if !status.Ready() {
+		return cni.ErrNotReady
+	}
+
+	return nil
+}
+
+func printCiliumStatus(s cni.Status) error {
+	if config.JSONOutput {
+		data, _ := json.MarshalIndent(s, "", "  ")
+		fmt.Fprintln(stdout, string(data))
+
+		return nil
+	}
+
+	return render.CiliumStatus(stdout, s)
+}
+
+func readyAgents(s cni.Status) int {
+	n := 0
+
+	for _, a := range s.Agents {
+		if a.Ready {
+			n++
+		}
+	}
+
+	return n
+}
@@ -0,0 +1,347 @@
+// Package cni installs the Cilium CNI on a bootstrapped K8s-Lab cluster, from the
+// control plane over SSH, and reports its status like `cilium status`: the
+// Cilium workloads and the agent of every node.
+package cni
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/k8s-lab/get-cluster-info/remote"
+)
+
+// =============================================================================
+// Install
+// =============================================================================
+
+// Cilium chart of resources/installK8s.md.
+const (
+	CiliumChart   = "oci://quay.io/cilium/charts/cilium"
+	CiliumVersion = "1.19.0"
+	// Namespace is where Cilium is installed.
+	Namespace = "kube-system"
+	// apiServerPort is the port of the API server reached by the agents (no kube-proxy).
+	apiServerPort = "6443"
+	// valuesDelimiter ends the here-documents uploading the values files.
+	valuesDelimiter = "K8S_LAB_CILIUM_VALUES"
+)
+
+// DefaultValues are the bundled Helm values (cilium-values.yaml).
+//
+//go:embed cilium-values.yaml
+var DefaultValues []byte
+
+var (
+	// ErrUnknownCNI is returned for a CNI other than cilium.
+	ErrUnknownCNI = errors.New("unsupported CNI (supported: cilium)")
+	// ErrNotReady is returned when the agents are not ready before the deadline.
+	ErrNotReady = errors.New("cilium is not ready")
+	// ErrInvalidValues is returned for a values file that cannot be uploaded.
+	ErrInvalidValues = errors.New("invalid values file")
+)
+
+// InstallOptions configures Install.
+type InstallOptions struct {
+	// Version is the chart version (default CiliumVersion).
+	Version string
+	// Values are extra Helm values, applied over DefaultValues (optional).
+	Values []byte
+	// APIServerHost is the private IP of the control plane.
+	APIServerHost string
+}
+
+// Install installs or upgrades the Cilium chart with helm on the control plane.
+func Install(ctx context.Context, controlPlane remote.Client, opts InstallOptions) error {
+	version := opts.Version
+	if version == "" {
+		version = CiliumVersion
+	}
+
+	files := [][]byte{DefaultValues}
+	if len(opts.Values) > 0 {
+		files = append(files, opts.Values)
+	}
+
+	var script strings.Builder
+
+	script.WriteString("dir=$(mktemp -d)\ntrap 'rm -rf \"$dir\"' EXIT\n")
+
+	args := []string{
+		"helm upgrade --install cilium " + CiliumChart,
+		"--version " + remote.Quote(version),
+		"--namespace " + Namespace,
+	}
+
+	for i, values := range files {
+		if strings.Contains(string(values), valuesDelimiter) {
+			return fmt.Errorf("%w: contains %s", ErrInvalidValues, valuesDelimiter)
+		}
+
+		fmt.Fprintf(&script, "cat > \"$dir/values-%d.yaml\" <<'%s'\n%s\n%s\n",
+			i, valuesDelimiter, strings.TrimRight(string(values), "\n"), valuesDelimiter)
+
+		args = append(args, fmt.Sprintf("--values \"$dir/values-%d.yaml\"", i))
+	}
+
+	args = append(args,
+		"--set k8sServiceHost="+remote.Quote(opts.APIServerHost),
+		"--set k8sServicePort="+apiServerPort)
+
+	script.WriteString(strings.Join(args, " \\\n  ") + "\n")
+
+	if _, err := controlPlane.Run(ctx, "bash -euo pipefail -c "+remote.Quote(script.String())); err != nil {
+		return fmt.Errorf("helm install cilium failed: %w", err)
+	}
+
+	return nil
+}
+
+// =============================================================================
+// Status
+// =============================================================================
+
+// Workload is a Cilium DaemonSet or Deployment.
+type Workload struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Desired   int    `json:"desired"`
+	Ready     int    `json:"ready"`
+	Available int    `json:"available"`
+	Image     string `json:"image"`
+}
+
+// OK reports whether every desired replica is ready.
+func (w Workload) OK() bool {
+	return w.Desired > 0 && w.Ready == w.Desired
+}
+
+// Agent is the Cilium agent of a node.
+type Agent struct {
+	Node     string `json:"node"`
+	NodeIP   string `json:"node_ip"` //nolint:tagliatelle
+	Pod      string `json:"pod,omitempty"`
+	Ready    bool   `json:"ready"`
+	Restarts int    `json:"restarts"`
+	// Reason explains a missing or unready agent.
+	Reason string `json:"reason,omitempty"`
+}
+
+// Status is the state of the Cilium installation, like `cilium status`.
+type Status struct {
+	Workloads []Workload `json:"workloads"`
+	Agents    []Agent    `json:"agents"`
+}
+
+// Ready reports whether every workload and the agent of every node are ready.
+func (s Status) Ready() bool {
+	if len(s.Workloads) == 0 || len(s.Agents) == 0 {
+		return false
+	}
+
+	for _, w := range s.Workloads {
+		if !w.OK() {
+			return false
+		}
+	}
+
+	for _, a := range s.Agents {
+		if !a.Ready {
+			return false
+		}
+	}
+
+	return true
+}
+
+// agentLabel selects the agent pods; partOfLabel the Cilium workloads.
+const (
+	agentLabel  = "k8s-app=cilium"
+	partOfLabel = "app.kubernetes.io/part-of=cilium"
+)
+
+// GetStatus reads the Cilium workloads, agents and nodes with kubectl on the control plane.
+func GetStatus(ctx context.Context, controlPlane remote.Client) (Status, error) {
+	workloads, err := controlPlane.Run(ctx, "kubectl -n "+Namespace+" get daemonsets,deployments -l "+partOfLabel+
+		" -o json")
+	if err != nil {
+		return Status{}, fmt.Errorf("failed to list the cilium workloads: %w", err)
+	}
+
+	pods, err := controlPlane.Run(ctx, "kubectl -n "+Namespace+" get pods -l "+agentLabel+" -o json")
+	if err != nil {
+		return Status{}, fmt.Errorf("failed to list the cilium agents: %w", err)
+	}
+
+	nodes, err := controlPlane.Run(ctx, "kubectl get nodes -o json")
+	if err != nil {
+		return Status{}, fmt.Errorf("failed to list the nodes: %w", err)
+	}
+
+	return ParseStatus(workloads, pods, nodes)
+}
+
+// k8sList is the subset of a kubectl -o json list read by ParseStatus.
+type k8sList struct {
+	Items []struct {
+		Kind     string `json:"kind"`
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+		Spec struct {
+			Replicas *int   `json:"replicas"`
+			NodeName string `json:"nodeName"`
+			Template struct {
+				Spec struct {
+					Containers []struct {
+						Image string `json:"image"`
+					} `json:"containers"`
+				} `json:"spec"`
+			} `json:"template"`
+		} `json:"spec"`
+		Status struct {
+			// DaemonSet
+			DesiredNumberScheduled int `json:"desiredNumberScheduled"`
+			NumberReady            int `json:"numberReady"`
+			NumberAvailable        int `json:"numberAvailable"`
+			// Deployment
+			ReadyReplicas     int `json:"readyReplicas"`
+			AvailableReplicas int `json:"availableReplicas"`
+			// Pod and Node
+			Phase             string         `json:"phase"`
+			Conditions        []k8sCondition `json:"conditions"`
+			ContainerStatuses []struct {
+				RestartCount int `json:"restartCount"`
+			} `json:"containerStatuses"`
+			Addresses []struct {
+				Type    string `json:"type"`
+				Address string `json:"address"`
+			} `json:"addresses"`
+		} `json:"status"`
+	} `json:"items"`
+}
+
+// ParseStatus builds the status from the kubectl -o json lists of the Cilium
+// workloads, the agent pods and the nodes.
+func ParseStatus(workloadsJSON, podsJSON, nodesJSON []byte) (Status, error) {
+	var workloads, pods, nodes k8sList
+
+	for _, l := range []struct {
+		data []byte
+		list *k8sList
+	}{{workloadsJSON, &workloads}, {podsJSON, &pods}, {nodesJSON, &nodes}} {
+		if err := json.Unmarshal(l.data, l.list); err != nil {
+			return Status{}, fmt.Errorf("failed to parse kubectl output: %w", err)
+		}
+	}
+
+	s := Status{Workloads: []Workload{}, Agents: []Agent{}}
+
+	for _, it := range workloads.Items {
+		w := Workload{Kind: it.Kind, Name: it.Metadata.Name}
+		if containers := it.Spec.Template.Spec.Containers; len(containers) > 0 {
+			w.Image = containers[0].Image
+		}
+
+		if it.Kind == "DaemonSet" {
+			w.Desired, w.Ready, w.Available = it.Status.DesiredNumberScheduled, it.Status.NumberReady,
+				it.Status.NumberAvailable
+		} else {
+			w.Desired, w.Ready, w.Available = 1, it.Status.ReadyReplicas, it.Status.AvailableReplicas
+			if it.Spec.Replicas != nil {
+				w.Desired = *it.Spec.Replicas
+			}
+		}
+
+		s.Workloads = append(s.Workloads, w)
+	}
+
+	// DaemonSets first, then by name.
+	slices.SortStableFunc(s.Workloads, func(a, b Workload) int {
+		if a.Kind != b.Kind {
+			return strings.Compare(a.Kind, b.Kind)
+		}
+
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	for _, node := range nodes.Items {
+		a := Agent{Node: node.Metadata.Name, Reason: "no cilium agent scheduled"}
+
+		for _, addr := range node.Status.Addresses {
+			if addr.Type == "InternalIP" {
+				a.NodeIP = addr.Address
+			}
+		}
+
+		for _, pod := range pods.Items {
+			if pod.Spec.NodeName != a.Node {
+				continue
+			}
+
+			a.Pod, a.Ready, a.Reason = pod.Metadata.Name, condition(pod.Status.Conditions, "Ready"), ""
+			if !a.Ready {
+				a.Reason = "agent " + strings.ToLower(pod.Status.Phase) + ", not ready"
+			}
+
+			for _, cs := range pod.Status.ContainerStatuses {
+				a.Restarts += cs.RestartCount
+			}
+		}
+
+		s.Agents = append(s.Agents, a)
+	}
+
+	return s, nil
+}
+
+// k8sCondition is a pod or node condition.
+type k8sCondition struct {
+	Type   string `json:"type"`
+	Status string `json:"status"`
+}
+
+// condition reports whether the condition typ is True.
+func condition(conditions []k8sCondition, typ string) bool {
+	for _, c := range conditions {
+		if c.Type == typ {
+			return c.Status == "True"
+		}
+	}
+
+	return false
+}
+
+// Wait polls the status every interval until Cilium is ready, reporting each
+// status to progress (optional). When ctx is done, it returns the last status and ErrNotReady.
+func Wait(ctx context.Context, controlPlane remote.Client, interval time.Duration,
+	progress func(Status),
+) (Status, error) {
+	var last Status
+
+	for {
+		// kubectl may fail while the agents restart the API server connections: retry.
+		if s, err := GetStatus(ctx, controlPlane); err == nil {
+			last = s
+
+			if progress != nil {
+				progress(s)
+			}
+
+			if s.Ready() {
+				return s, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return last, fmt.Errorf("%w: %w", ErrNotReady, ctx.Err())
+		case <-time.After(interval):
+		}
+	}
+}
@@ -0,0 +1,272 @@
+package cni
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/k8s-lab/get-cluster-info/remote"
+	"github.com/k8s-lab/get-cluster-info/remote/remotetest"
+)
+
+// =============================================================================
+// CNI tests
+// =============================================================================
+
+func must(t *testing.T, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	must(t, err)
+
+	return data
+}
+
+// kubectl answers the GetStatus commands with the fixtures, pods being the agent pods fixture.
+func kubectl(t *testing.T, pods ...string) func(_, cmd string) ([]byte, error) {
+	t.Helper()
+
+	workloads, nodes := readFixture(t, "workloads.json"), readFixture(t, "nodes.json")
+	calls := 0
+
+	return func(_, cmd string) ([]byte, error) {
+		switch {
+		case strings.Contains(cmd, "get daemonsets,deployments"):
+			return workloads, nil
+		case strings.Contains(cmd, "get pods"):
+			// One fixture per poll, the last one repeated.
+			name := pods[min(calls, len(pods)-1)]
+			calls++
+
+			return readFixture(t, name), nil
+		case strings.Contains(cmd, "get nodes"):
+			return nodes, nil
+		}
+
+		return nil, nil
+	}
+}
+
+// script returns the script run by a "bash -c" command, unquoted.
+func script(cmd string) string {
+	cmd = strings.TrimPrefix(cmd, "bash -euo pipefail -c ")
+
+	return strings.ReplaceAll(strings.Trim(cmd, "'"), `'\''`, "'")
+}
+
+func dial(t *testing.T, d *remotetest.FakeDialer) remote.Client {
+	t.Helper()
+
+	c, err := d.Dial(context.Background(), "203.0.113.10")
+	must(t, err)
+
+	return c
+}
+
+// -----------------------------------------------------------------------------
+// Install
+// -----------------------------------------------------------------------------
+
+func TestInstall(t *testing.T) {
+	t.Parallel()
+
+	t.Run("default values", func(t *testing.T) {
+		t.Parallel()
+
+		d := &remotetest.FakeDialer{}
+		must(t, Install(context.Background(), dial(t, d), InstallOptions{APIServerHost: "10.0.0.10"}))
+
+		cmds := d.Commands("203.0.113.10")
+		if len(cmds) != 1 {
+			t.Fatalf("%d commands run, want 1: %q", len(cmds), cmds)
+		}
+
+		cmd := script(cmds[0])
+		for _, want := range []string{
+			"helm upgrade --install cilium " + CiliumChart,
+			"--version " + remote.Quote(CiliumVersion),
+			"--namespace kube-system",
+			"values-0.yaml",
+			"kubeProxyReplacement",
+			"k8sServiceHost=" + remote.Quote("10.0.0.10"),
+			"k8sServicePort=6443",
+		} {
+			if !strings.Contains(cmd, want) {
+				t.Errorf("script missing %q:\n%s", want, cmd)
+			}
+		}
+
+		if strings.Contains(cmd, "values-1.yaml") {
+			t.Errorf("unexpected extra values file:\n%s", cmd)
+		}
+	})
+
+	t.Run("version and extra values", func(t *testing.T) {
+		t.Parallel()
+
+		d := &remotetest.FakeDialer{}
+		must(t, Install(context.Background(), dial(t, d), InstallOptions{
+			Version: "1.18.5", Values: []byte("hubble:\n  ui:\n    enabled: false\n"), APIServerHost: "10.0.0.10",
+		}))
+
+		cmd := script(d.Commands("203.0.113.10")[0])
+		for _, want := range []string{"--version " + remote.Quote("1.18.5"), "values-1.yaml", "enabled: false"} {
+			if !strings.Contains(cmd, want) {
+				t.Errorf("script missing %q:\n%s", want, cmd)
+			}
+		}
+
+		// The user values come last, to override the bundled ones.
+		if strings.Index(cmd, "--values \"$dir/values-0.yaml\"") > strings.Index(cmd, "--values \"$dir/values-1.yaml\"") {
+			t.Errorf("values files in the wrong order:\n%s", cmd)
+		}
+	})
+
+	t.Run("invalid values", func(t *testing.T) {
+		t.Parallel()
+
+		d := &remotetest.FakeDialer{}
+
+		err := Install(context.Background(), dial(t, d), InstallOptions{Values: []byte(valuesDelimiter)})
+		if !errors.Is(err, ErrInvalidValues) {
+			t.Errorf("Install() error = %v, want %v", err, ErrInvalidValues)
+		}
+
+		if len(d.Calls()) != 0 {
+			t.Errorf("commands run despite invalid values: %v", d.Calls())
+		}
+	})
+
+	t.Run("helm failure", func(t *testing.T) {
+		t.Parallel()
+
+		d := &remotetest.FakeDialer{Handler: func(_, _ string) ([]byte, error) {
+			return nil, &remote.ExitError{Status: 1, Stderr: "Error: chart not found"}
+		}}
+
+		err := Install(context.Background(), dial(t, d), InstallOptions{})
+
+		var exitErr *remote.ExitError
+		if !errors.As(err, &exitErr) || !strings.Contains(err.Error(), "chart not found") {
+			t.Errorf("Install() error = %v, want the helm error", err)
+		}
+	})
+}
+
+// -----------------------------------------------------------------------------
+// Status
+// -----------------------------------------------------------------------------
+
+func TestParseStatus(t *testing.T) {
+	t.Parallel()
+
+	workloads, nodes := readFixture(t, "workloads.json"), readFixture(t, "nodes.json")
+
+	t.Run("ready", func(t *testing.T) {
+		t.Parallel()
+
+		s, err := ParseStatus(workloads, readFixture(t, "pods_ready.json"), nodes)
+		must(t, err)
+
+		if !s.Ready() {
+			t.Errorf("Ready() = false, want true: %+v", s)
+		}
+
+		var names []string
+		for _, w := range s.Workloads {
+			names = append(names, w.Kind+"/"+w.Name)
+		}
+
+		if got, want := strings.Join(names, " "),
+			"DaemonSet/cilium Deployment/cilium-operator Deployment/hubble-relay"; got != want {
+			t.Errorf("workloads = %s, want %s", got, want)
+		}
+
+		want := Agent{Node: "k8s-lab-worker-1", NodeIP: "10.0.0.11", Pod: "cilium-q9w4z", Ready: true, Restarts: 1}
+		if len(s.Agents) != 2 || s.Agents[1] != want {
+			t.Errorf("agents = %+v, want the worker %+v", s.Agents, want)
+		}
+	})
+
+	t.Run("agent starting", func(t *testing.T) {
+		t.Parallel()
+
+		s, err := ParseStatus(workloads, readFixture(t, "pods_starting.json"), nodes)
+		must(t, err)
+
+		if s.Ready() {
+			t.Error("Ready() = true with a pending agent")
+		}
+
+		if a := s.Agents[1]; a.Ready || a.Reason != "agent pending, not ready" {
+			t.Errorf("worker agent = %+v, want pending", a)
+		}
+	})
+
+	t.Run("no agent", func(t *testing.T) {
+		t.Parallel()
+
+		s, err := ParseStatus([]byte(`{"items":[]}`), []byte(`{"items":[]}`), nodes)
+		must(t, err)
+
+		if s.Ready() || s.Agents[0].Reason != "no cilium agent scheduled" {
+			t.Errorf("status = %+v, want not ready without agents", s)
+		}
+	})
+
+	t.Run("invalid json", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := ParseStatus([]byte("error"), nil, nil); err == nil {
+			t.Error("ParseStatus() error = nil, want a parse error")
+		}
+	})
+}
+
+func TestWait(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ready after polls", func(t *testing.T) {
+		t.Parallel()
+
+		d := &remotetest.FakeDialer{Handler: kubectl(t, "pods_starting.json", "pods_starting.json", "pods_ready.json")}
+		polls := 0
+
+		s, err := Wait(context.Background(), dial(t, d), time.Millisecond, func(Status) { polls++ })
+		must(t, err)
+
+		if !s.Ready() || polls != 3 {
+			t.Errorf("Wait() = ready %v after %d polls, want ready after 3", s.Ready(), polls)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		d := &remotetest.FakeDialer{Handler: kubectl(t, "pods_starting.json")}
+
+		s, err := Wait(ctx, dial(t, d), time.Millisecond, nil)
+		if !errors.Is(err, ErrNotReady) {
+			t.Fatalf("Wait() error = %v, want %v", err, ErrNotReady)
+		}
+
+		if len(s.Agents) != 2 {
+			t.Errorf("Wait() did not return the last status: %+v", s)
+		}
+	})
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/k8s-lab/get-cluster-info/cni"
+)
+
+// =============================================================================
+// cni subcommand tests
+// =============================================================================
+
+// setupCNI is setupBootstrap with kubectl answering the Cilium status from the
+// cni/testdata fixtures: one agent pods fixture per poll, the last one repeated.
+func setupCNI(t *testing.T, pods ...string) (func() string, func() []string) {
+	t.Helper()
+
+	fixture := func(name string) []byte {
+		data, err := os.ReadFile(filepath.Join("cni", "testdata", name))
+		must(t, err)
+
+		return data
+	}
+
+	polls := 0
+
+	dialer, out := setupBootstrap(t, func(_, cmd string) ([]byte, error) {
+		switch {
+		case strings.Contains(cmd, "get daemonsets,deployments"):
+			return fixture("workloads.json"), nil
+		case strings.Contains(cmd, "get pods"):
+			name := pods[min(polls, len(pods)-1)]
+			polls++
+
+			return fixture(name), nil
+		case strings.Contains(cmd, "get nodes"):
+			return fixture("nodes.json"), nil
+		}
+
+		return nil, nil
+	})
+
+	oldVersion, oldValues, oldTimeout, oldInterval := cniVersion, cniValues, cniTimeout, cniPollInterval
+	t.Cleanup(func() {
+		cniVersion, cniValues, cniTimeout, cniPollInterval = oldVersion, oldValues, oldTimeout, oldInterval
+	})
+
+	cniVersion, cniValues, cniTimeout, cniPollInterval = cni.CiliumVersion, "", time.Second, time.Millisecond
+
+	return out, func() []string { return dialer.Commands("203.0.113.10") }
+}
+
+func TestRunCNIInstallGolden(t *testing.T) {
+	// Not parallel - modifies global config
+	out, _ := setupCNI(t, "pods_starting.json", "pods_ready.json")
+	config.Quiet = true // the credentials path changes on every run
+
+	must(t, runCNIInstall(nil, []string{"cilium"}))
+
+	assertGolden(t, "cni_install_summary", []byte(out()))
+}
+
+func TestRunCNIInstall(t *testing.T) {
+	// Not parallel - modifies global config
+
+	t.Run("unknown CNI", func(t *testing.T) {
+		_, cmds := setupCNI(t, "pods_ready.json")
+
+		if err := runCNIInstall(nil, []string{"calico"}); !errors.Is(err, cni.ErrUnknownCNI) {
+			t.Errorf("runCNIInstall() error = %v, want %v", err, cni.ErrUnknownCNI)
+		}
+
+		if len(cmds()) != 0 {
+			t.Errorf("commands run for an unknown CNI: %q", cmds())
+		}
+	})
+
+	t.Run("values file", func(t *testing.T) {
+		_, cmds := setupCNI(t, "pods_ready.json")
+		cniValues = filepath.Join(t.TempDir(), "values.yaml")
+		must(t, os.WriteFile(cniValues, []byte("debug:\n  enabled: true\n"), 0o600))
+
+		must(t, runCNIInstall(nil, []string{"cilium"}))
+
+		if c := cmds(); len(c) == 0 || !strings.Contains(c[0], "debug:") || !strings.Contains(c[0], "values-1.yaml") {
+			t.Errorf("values file not uploaded: %q", c)
+		}
+	})
+
+	t.Run("timeout reports the status", func(t *testing.T) {
+		out, _ := setupCNI(t, "pods_starting.json")
+		cniTimeout = 20 * time.Millisecond
+
+		if err := runCNIInstall(nil, []string{"cilium"}); !errors.Is(err, cni.ErrNotReady) {
+			t.Fatalf("runCNIInstall() error = %v, want %v", err, cni.ErrNotReady)
+		}
+
+		if !strings.Contains(out(), "agent pending, not ready") {
+			t.Errorf("status of the pending agent not rendered:\n%s", out())
+		}
+	})
+}
+
+func TestRunCNIStatus(t *testing.T) {
+	// Not parallel - modifies global config
+
+	t.Run("JSON", func(t *testing.T) {
+		out, cmds := setupCNI(t, "pods_ready.json")
+		config.JSONOutput, config.Quiet = true, true
+
+		must(t, runCNIStatus(nil, nil))
+
+		var s cni.Status
+		if err := json.Unmarshal([]byte(out()), &s); err != nil {
+			t.Fatalf("invalid JSON status: %v\n%s", err, out())
+		}
+
+		if !s.Ready() || len(s.Agents) != 2 {
+			t.Errorf("status = %+v, want 2 ready agents", s)
+		}
+
+		for _, c := range cmds() {
+			if strings.Contains(c, "helm") {
+				t.Errorf("status ran helm: %s", c)
+			}
+		}
+	})
+
+	t.Run("not ready", func(t *testing.T) {
+		setupCNI(t, "pods_starting.json")
+
+		if err := runCNIStatus(nil, nil); !errors.Is(err, cni.ErrNotReady) {
+			t.Errorf("runCNIStatus() error = %v, want %v", err, cni.ErrNotReady)
+		}
+	})
+}
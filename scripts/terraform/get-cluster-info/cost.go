@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-exec/tfexec"
+	"github.com/k8s-lab/get-cluster-info/clusterinfo"
+	"github.com/k8s-lab/get-cluster-info/cost"
+	"github.com/k8s-lab/get-cluster-info/i18n"
+	"github.com/k8s-lab/get-cluster-info/render"
+	"github.com/spf13/cobra"
+)
+
+// =============================================================================
+// Cost subcommand
+// =============================================================================
+//
+// Reads the servers (flavor) and billed resources (flexible IPs) from the state,
+// or from a plan with --plan, and multiplies them by the bundled price table
+// (cost/prices.yaml), overridable with --prices. The Scaleway resources do not
+// record when they were created: the cost accumulated since creation needs the
+// creation time recorded with the lab TTL (see ttl.go).
+
+var (
+	costPrices string
+	costPlan   bool
+)
+
+var costCmd = &cobra.Command{
+	Use:   "cost",
+	Short: "Estimate what the lab costs",
+	Long: `Estimate what the lab costs.
+
+Multiplies the instance flavors and billed resources (flexible IPs) of the
+Terraform state by a price table, for hourly and monthly estimates.
+
+The cost accumulated since the lab was created needs a TTL: the Scaleway
+resources do not record their creation time, so it is only shown once
+"get-cluster-info ttl set" has recorded when the lab was created.
+
+With --plan, the estimate is for the infrastructure once the pending plan is
+applied (e.g. after changing control_plane_flavor or worker_flavor).
+
+The bundled prices are indicative Scaleway prices (EUR, excluding VAT).
+Override them with a YAML file of the same shape: entries missing from the
+file keep their bundled value.
+
+Examples:
+  # Cost of the deployed lab
+  get-cluster-info cost
+
+  # Cost after the pending changes
+  get-cluster-info cost --plan
+
+  # Custom prices
+  get-cluster-info cost --prices my-prices.yaml --json`,
+	Args: cobra.NoArgs,
+	RunE: runCost,
+}
+
+func init() {
+	costCmd.Flags().StringVar(&costPrices, "prices", "",
+		"YAML price table overriding the bundled prices (same shape as cost/prices.yaml)")
+	costCmd.Flags().BoolVar(&costPlan, "plan", false, "Estimate the infrastructure once the pending plan is applied")
+
+	rootCmd.AddCommand(costCmd)
+}
+
+func runCost(_ *cobra.Command, _ []string) error {
+	prices, err := cost.LoadPrices(costPrices)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	src, err := setupEnvironment(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = src.Close() }()
+
+	tf := src.Client()
+
+	var estimate cost.Estimate
+
+	if costPlan {
+		estimate, err = planCost(ctx, tf, prices)
+		if err != nil {
+			return err
+		}
+	} else {
+		logInfo("Reading the Terraform state...")
+
+		state, err := tf.Show(ctx)
+		if err != nil {
+			return withClass(errBackend, i18n.Errorf("failed to read terraform state: %w", err))
+		}
+
+		var created time.Time
+		if l := readLifetime(ctx); l != nil {
+			created = l.CreatedAt
+		}
+
+		estimate = cost.FromState(state, prices, now(), created)
+	}
+
+	if config.JSONOutput {
+		data, _ := json.MarshalIndent(estimate, "", "  ")
+		fmt.Fprintln(stdout, string(data))
+
+		return nil
+	}
+
+	return render.CostEstimate(stdout, estimate, now())
+}
+
+// planCost estimates the infrastructure once the pending plan is applied.
+func planCost(ctx context.Context, tf clusterinfo.TerraformClient, prices cost.Prices) (cost.Estimate, error) {
+	planPath, cleanup, err := planFilePath("")
+	if err != nil {
+		return cost.Estimate{}, err
+	}
+
+	defer cleanup()
+
+	logInfo("Running terraform plan...")
+
+	if _, err := tf.Plan(ctx, tfexec.Out(planPath)); err != nil {
+		return cost.Estimate{}, withClass(errBackend, i18n.Errorf("terraform plan failed: %w", err))
+	}
+
+	plan, err := tf.ShowPlanFile(ctx, planPath)
+	if err != nil {
+		return cost.Estimate{}, withClass(errBackend, i18n.Errorf("failed to read plan file: %w", err))
+	}
+
+	return cost.FromPlan(plan, prices), nil
+}
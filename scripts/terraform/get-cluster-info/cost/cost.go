@@ -0,0 +1,252 @@
+// Package cost estimates what a K8s-Lab cluster costs from its Terraform state
+// or plan: instance flavors and billed resources multiplied by a price table.
+//
+// The bundled price table (prices.yaml) holds indicative Scaleway prices; it can
+// be overridden entry by entry with a YAML file of the same shape.
+package cost
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	tfjson "github.com/hashicorp/terraform-json"
+	"gopkg.in/yaml.v3"
+)
+
+// =============================================================================
+// Price table
+// =============================================================================
+
+//go:embed prices.yaml
+var defaultPrices []byte
+
+// serverType is the resource whose "type" attribute is an instance flavor.
+const serverType = "scaleway_instance_server"
+
+// ErrInvalidPrices is returned when a price table cannot be read.
+var ErrInvalidPrices = errors.New("invalid price table")
+
+// Prices are hourly prices by instance flavor and by resource type.
+type Prices struct {
+	Currency      string             `yaml:"currency"`
+	HoursPerMonth float64            `yaml:"hours_per_month"` //nolint:tagliatelle
+	Instances     map[string]float64 `yaml:"instances"`
+	Resources     map[string]float64 `yaml:"resources"`
+}
+
+// DefaultPrices returns the bundled price table.
+func DefaultPrices() Prices {
+	var p Prices
+	if err := yaml.Unmarshal(defaultPrices, &p); err != nil {
+		panic(fmt.Sprintf("bundled prices.yaml: %v", err))
+	}
+
+	return p
+}
+
+// LoadPrices returns the bundled price table overridden by the entries of the
+// YAML file at path (the bundled table alone if path is empty).
+func LoadPrices(path string) (Prices, error) {
+	p := DefaultPrices()
+	if path == "" {
+		return p, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Prices{}, fmt.Errorf("%w: %w", ErrInvalidPrices, err)
+	}
+
+	var override Prices
+	if err := yaml.Unmarshal(data, &override); err != nil {
+		return Prices{}, fmt.Errorf("%w %s: %w", ErrInvalidPrices, path, err)
+	}
+
+	if override.Currency != "" {
+		p.Currency = override.Currency
+	}
+
+	if override.HoursPerMonth != 0 {
+		p.HoursPerMonth = override.HoursPerMonth
+	}
+
+	for flavor, price := range override.Instances {
+		p.Instances[flavor] = price
+	}
+
+	for typ, price := range override.Resources {
+		p.Resources[typ] = price
+	}
+
+	if p.HoursPerMonth <= 0 {
+		return Prices{}, fmt.Errorf("%w %s: hours_per_month must be positive", ErrInvalidPrices, path)
+	}
+
+	return p, nil
+}
+
+// =============================================================================
+// Estimate
+// =============================================================================
+
+// Source tells where the billed resources were read from.
+type Source string
+
+// Sources of an estimate.
+const (
+	SourceState Source = "state"
+	SourcePlan  Source = "plan"
+)
+
+// Item is a billed resource.
+// JSON tags use snake_case for consistency with Terraform outputs.
+type Item struct {
+	Address string  `json:"address"`
+	Type    string  `json:"type"`
+	Flavor  string  `json:"flavor,omitempty"`
+	Hourly  float64 `json:"hourly"`
+	Monthly float64 `json:"monthly"`
+}
+
+// FlavorCount is the number of servers of a flavor.
+type FlavorCount struct {
+	Flavor string `json:"flavor"`
+	Count  int    `json:"count"`
+}
+
+// Estimate is the cost of the billed resources of a state or plan.
+type Estimate struct {
+	Source   Source        `json:"source"`
+	Currency string        `json:"currency"`
+	Items    []Item        `json:"items"`
+	Flavors  []FlavorCount `json:"flavors"`
+	// Unpriced lists the servers whose flavor is not in the price table.
+	Unpriced []string `json:"unpriced"`
+	Hourly   float64  `json:"hourly"`
+	Monthly  float64  `json:"monthly"`
+	// Since is the creation time of the lab, and Accumulated the cost since then
+	// (state only, nil when the creation time is unknown).
+	Since       *time.Time `json:"since,omitempty"`
+	Accumulated *float64   `json:"accumulated,omitempty"`
+}
+
+// resource is a billed resource candidate, from a state or a plan.
+type resource struct {
+	address string
+	typ     string
+	values  map[string]any
+}
+
+// FromState estimates the cost of the deployed resources, and what they have
+// cost since created. The Scaleway resources do not record their creation
+// time in the state: created is the creation time of the lab TTL, and the
+// accumulated cost is unknown (nil) when it is zero.
+func FromState(state *tfjson.State, prices Prices, now, created time.Time) Estimate {
+	var resources []resource
+
+	if state != nil && state.Values != nil {
+		forEachResource(state.Values.RootModule, func(r *tfjson.StateResource) {
+			if r.Mode == tfjson.ManagedResourceMode {
+				resources = append(resources, resource{address: r.Address, typ: r.Type, values: r.AttributeValues})
+			}
+		})
+	}
+
+	e := estimate(SourceState, resources, prices)
+
+	if created.IsZero() || created.After(now) || len(e.Items) == 0 {
+		return e
+	}
+
+	since := created.UTC()
+	accumulated := e.Hourly * now.Sub(since).Hours()
+	e.Since, e.Accumulated = &since, &accumulated
+
+	return e
+}
+
+// FromPlan estimates the cost of the infrastructure once the plan is applied.
+func FromPlan(plan *tfjson.Plan, prices Prices) Estimate {
+	var resources []resource
+
+	if plan != nil {
+		for _, rc := range plan.ResourceChanges {
+			if rc.Mode != tfjson.ManagedResourceMode || rc.Change == nil || rc.Change.Actions.Delete() {
+				continue
+			}
+
+			after, _ := rc.Change.After.(map[string]any)
+			resources = append(resources, resource{address: rc.Address, typ: rc.Type, values: after})
+		}
+	}
+
+	return estimate(SourcePlan, resources, prices)
+}
+
+func estimate(source Source, resources []resource, prices Prices) Estimate {
+	e := Estimate{Source: source, Currency: prices.Currency, Items: []Item{}, Flavors: []FlavorCount{},
+		Unpriced: []string{}}
+
+	for _, r := range resources {
+		item := Item{Address: r.address, Type: r.typ}
+
+		switch {
+		case r.typ == serverType:
+			item.Flavor, _ = r.values["type"].(string)
+			e.countFlavor(item.Flavor)
+
+			price, ok := prices.Instances[item.Flavor]
+			if !ok {
+				e.Unpriced = append(e.Unpriced, r.address)
+
+				continue
+			}
+
+			item.Hourly = price
+		case prices.Resources[r.typ] > 0:
+			item.Hourly = prices.Resources[r.typ]
+		default:
+			continue
+		}
+
+		item.Monthly = item.Hourly * prices.HoursPerMonth
+		e.Hourly += item.Hourly
+		e.Monthly += item.Monthly
+		e.Items = append(e.Items, item)
+	}
+
+	slices.SortStableFunc(e.Flavors, func(a, b FlavorCount) int { return b.Count - a.Count })
+
+	return e
+}
+
+func (e *Estimate) countFlavor(flavor string) {
+	for i := range e.Flavors {
+		if e.Flavors[i].Flavor == flavor {
+			e.Flavors[i].Count++
+
+			return
+		}
+	}
+
+	e.Flavors = append(e.Flavors, FlavorCount{Flavor: flavor, Count: 1})
+}
+
+// forEachResource calls fn for the resources of m and its child modules.
+func forEachResource(m *tfjson.StateModule, fn func(*tfjson.StateResource)) {
+	if m == nil {
+		return
+	}
+
+	for _, r := range m.Resources {
+		fn(r)
+	}
+
+	for _, child := range m.ChildModules {
+		forEachResource(child, fn)
+	}
+}
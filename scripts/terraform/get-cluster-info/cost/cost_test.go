@@ -0,0 +1,196 @@
+package cost
+
+import (
+	"errors"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/k8s-lab/get-cluster-info/clusterinfo/clusterinfotest"
+)
+
+// =============================================================================
+// Cost tests
+// =============================================================================
+
+// testPrices is a small price table with round numbers.
+var testPrices = Prices{
+	Currency:      "EUR",
+	HoursPerMonth: 730,
+	Instances:     map[string]float64{"DEV1-M": 0.02, "DEV1-L": 0.04},
+	Resources:     map[string]float64{"scaleway_instance_ip": 0.005},
+}
+
+func approx(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func server(address, flavor string) *tfjson.StateResource {
+	return &tfjson.StateResource{
+		Address: address, Mode: tfjson.ManagedResourceMode, Type: serverType,
+		AttributeValues: map[string]any{"type": flavor},
+	}
+}
+
+func TestDefaultPrices(t *testing.T) {
+	t.Parallel()
+
+	p := DefaultPrices()
+
+	if p.Currency != "EUR" || p.HoursPerMonth != 730 {
+		t.Errorf("DefaultPrices() = %s, %v hours", p.Currency, p.HoursPerMonth)
+	}
+
+	// The default flavors of variables.tf must be priced.
+	for _, flavor := range []string{"DEV1-S", "DEV1-M", "DEV1-L"} {
+		if p.Instances[flavor] <= 0 {
+			t.Errorf("no price for %s", flavor)
+		}
+	}
+}
+
+func TestLoadPrices(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	override := filepath.Join(dir, "prices.yaml")
+	must(t, os.WriteFile(override, []byte("instances:\n  DEV1-M: 0.5\n  CUSTOM-1: 1\n"), 0o600))
+
+	invalid := filepath.Join(dir, "invalid.yaml")
+	must(t, os.WriteFile(invalid, []byte("instances: [oops\n"), 0o600))
+
+	p, err := LoadPrices(override)
+	if err != nil {
+		t.Fatalf("LoadPrices() error = %v", err)
+	}
+
+	defaults := DefaultPrices()
+	if p.Instances["DEV1-M"] != 0.5 || p.Instances["CUSTOM-1"] != 1 ||
+		p.Instances["DEV1-L"] != defaults.Instances["DEV1-L"] || p.Currency != defaults.Currency {
+		t.Errorf("LoadPrices() = %+v, want the overrides on top of the defaults", p)
+	}
+
+	if _, err := LoadPrices(invalid); !errors.Is(err, ErrInvalidPrices) {
+		t.Errorf("LoadPrices(invalid) error = %v, want %v", err, ErrInvalidPrices)
+	}
+
+	if _, err := LoadPrices(filepath.Join(dir, "missing.yaml")); !errors.Is(err, ErrInvalidPrices) ||
+		!errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("LoadPrices(missing) error = %v, want %v wrapping %v", err, ErrInvalidPrices, fs.ErrNotExist)
+	}
+}
+
+func TestFromState(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	created := now.Add(-10 * time.Hour)
+
+	state := &tfjson.State{Values: &tfjson.StateValues{RootModule: &tfjson.StateModule{
+		Resources: []*tfjson.StateResource{
+			server("scaleway_instance_server.nodes[\"control-plane\"]", "DEV1-L"),
+			server("scaleway_instance_server.nodes[\"worker\"]", "DEV1-M"),
+			server("scaleway_instance_server.nodes[\"gpu\"]", "H100-1-80G"),
+			{
+				Address: "scaleway_instance_ip.ip", Mode: tfjson.ManagedResourceMode,
+				Type: "scaleway_instance_ip", AttributeValues: map[string]any{},
+			},
+			{
+				Address: "data.scaleway_instance_ip.ip", Mode: tfjson.DataResourceMode,
+				Type: "scaleway_instance_ip", AttributeValues: map[string]any{},
+			},
+			{Address: "tls_private_key.ssh", Mode: tfjson.ManagedResourceMode, Type: "tls_private_key"},
+		},
+	}}}
+
+	e := FromState(state, testPrices, now, created)
+
+	if len(e.Items) != 3 || !approx(e.Hourly, 0.065) || !approx(e.Monthly, 0.065*730) {
+		t.Errorf("FromState() = %d items, %v/h, %v/month, want 3 items, 0.065/h", len(e.Items), e.Hourly, e.Monthly)
+	}
+
+	if want := []string{"scaleway_instance_server.nodes[\"gpu\"]"}; !slices.Equal(e.Unpriced, want) {
+		t.Errorf("Unpriced = %v, want %v", e.Unpriced, want)
+	}
+
+	if want := []FlavorCount{{"DEV1-L", 1}, {"DEV1-M", 1}, {"H100-1-80G", 1}}; !slices.Equal(e.Flavors, want) {
+		t.Errorf("Flavors = %v, want %v", e.Flavors, want)
+	}
+
+	// Every priced resource for the 10h since created.
+	if e.Accumulated == nil || !approx(*e.Accumulated, 10*0.065) {
+		t.Errorf("Accumulated = %v, want %v", e.Accumulated, 10*0.065)
+	}
+
+	if e.Since == nil || !e.Since.Equal(created) {
+		t.Errorf("Since = %v, want %v", e.Since, created)
+	}
+}
+
+func TestFromStateUnknownCreation(t *testing.T) {
+	t.Parallel()
+
+	state, err := clusterinfotest.LoadFixture(clusterinfotest.FixtureDeployed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := FromState(state, testPrices, time.Now(), time.Time{})
+
+	if want := []FlavorCount{{"DEV1-M", 2}}; !slices.Equal(e.Flavors, want) {
+		t.Errorf("Flavors = %v, want %v", e.Flavors, want)
+	}
+
+	if !approx(e.Hourly, 2*0.02+2*0.005) {
+		t.Errorf("Hourly = %v, want %v", e.Hourly, 2*0.02+2*0.005)
+	}
+
+	if e.Since != nil || e.Accumulated != nil {
+		t.Errorf("Since, Accumulated = %v, %v, want nil without creation time", e.Since, e.Accumulated)
+	}
+}
+
+func TestFromPlan(t *testing.T) {
+	t.Parallel()
+
+	plan, err := clusterinfotest.LoadPlanFixture(clusterinfotest.FixturePlanChanges)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := FromPlan(plan, testPrices)
+
+	// Deleted worker gone, control-plane replaced, worker-2 created: 2 servers, 2 IPs.
+	want := []string{
+		"scaleway_instance_ip.nodes_ips[\"control-plane\"]",
+		"scaleway_instance_ip.nodes_ips[\"worker-2\"]",
+		"scaleway_instance_server.nodes[\"control-plane\"]",
+		"scaleway_instance_server.nodes[\"worker-2\"]",
+	}
+
+	got := make([]string, 0, len(e.Items))
+	for _, it := range e.Items {
+		got = append(got, it.Address)
+	}
+
+	if !slices.Equal(got, want) {
+		t.Errorf("items = %v, want %v", got, want)
+	}
+
+	if e.Source != SourcePlan || !approx(e.Hourly, 2*0.02+2*0.005) || e.Accumulated != nil {
+		t.Errorf("FromPlan() = %+v", e)
+	}
+}
+
+func must(t *testing.T, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+}
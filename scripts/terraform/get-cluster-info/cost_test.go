@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/k8s-lab/get-cluster-info/clusterinfo"
+	"github.com/k8s-lab/get-cluster-info/clusterinfo/clusterinfotest"
+	"github.com/k8s-lab/get-cluster-info/cost"
+)
+
+// =============================================================================
+// cost subcommand tests
+// =============================================================================
+
+// setupCost is setupPlan with the cost flags reset.
+func setupCost(t *testing.T, planFixture string) (*clusterinfotest.FakeTerraform, func() []byte) {
+	t.Helper()
+
+	fake, out := setupPlan(t, planFixture)
+
+	oldPrices, oldPlan := costPrices, costPlan
+	t.Cleanup(func() { costPrices, costPlan = oldPrices, oldPlan })
+
+	costPrices, costPlan = "", false
+
+	return fake, out
+}
+
+func TestRunCostGolden(t *testing.T) {
+	// Not parallel - modifies global config
+
+	tests := []struct {
+		name      string
+		fixture   string
+		plan      bool
+		wantCalls []string
+	}{
+		{name: "cost_summary", wantCalls: []string{"init", "show"}},
+		{
+			name:      "cost_plan_summary",
+			fixture:   clusterinfotest.FixturePlanChanges,
+			plan:      true,
+			wantCalls: []string{"init", "plan", "show plan"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake, out := setupCost(t, tt.fixture)
+			costPlan = tt.plan
+
+			l := clusterinfo.NewLifetime(testNow.Add(-26*time.Hour), 30*time.Hour, "alice", nil)
+			lifetimeStore = &clusterinfotest.MemoryLifetimeStore{Value: &l}
+
+			must(t, runCost(nil, nil))
+
+			assertGolden(t, tt.name, out())
+
+			if !slices.Equal(fake.Calls(), tt.wantCalls) {
+				t.Errorf("calls = %v, want %v", fake.Calls(), tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRunCost(t *testing.T) {
+	// Not parallel - modifies global config
+
+	t.Run("JSON with custom prices", func(t *testing.T) {
+		_, out := setupCost(t, "")
+		config.JSONOutput = true
+
+		costPrices = filepath.Join(t.TempDir(), "prices.yaml")
+		must(t, os.WriteFile(costPrices, []byte("instances:\n  DEV1-M: 1\nresources:\n  scaleway_instance_ip: 0\n"), 0o600))
+
+		must(t, runCost(nil, nil))
+
+		var e cost.Estimate
+		if err := json.Unmarshal(out(), &e); err != nil {
+			t.Fatalf("invalid JSON estimate: %v\n%s", err, out())
+		}
+
+		if e.Hourly != 2 || e.Monthly != 2*730 || e.Accumulated != nil {
+			t.Errorf("estimate = %+v, want 2/h, no accumulated cost without TTL", e)
+		}
+	})
+
+	t.Run("accumulated cost needs a TTL", func(t *testing.T) {
+		_, out := setupCost(t, "")
+
+		must(t, runCost(nil, nil))
+
+		if !strings.Contains(string(out()), "Accumulated cost unknown") || strings.Contains(string(out()), "Since ") {
+			t.Errorf("output without TTL, want the ttl set hint and no accumulated cost:\n%s", out())
+		}
+	})
+
+	t.Run("invalid price table", func(t *testing.T) {
+		fake, _ := setupCost(t, "")
+		costPrices = filepath.Join(t.TempDir(), "missing.yaml")
+
+		if err := runCost(nil, nil); err == nil {
+			t.Fatal("runCost() want error")
+		}
+
+		if len(fake.Calls()) != 0 {
+			t.Errorf("calls = %v, want none before the price table is loaded", fake.Calls())
+		}
+	})
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/atotto/clipboard"
+	"github.com/k8s-lab/get-cluster-info/dashboard"
+	"github.com/k8s-lab/get-cluster-info/remote"
+	"github.com/spf13/cobra"
+)
+
+// =============================================================================
+// dashboard subcommand
+// =============================================================================
+//
+// A live view of the nodes (see the dashboard package), refreshed over SSH,
+// with keys to open an SSH session, copy the IPs or tail the kubelet logs.
+
+var dashboardInterval time.Duration
+
+var dashboardCmd = &cobra.Command{
+	Use:   "dashboard",
+	Short: "Live dashboard of the nodes (SSH, load, memory, kubelet)",
+	Long: `Live dashboard of the nodes.
+
+Shows every node with its reachability over SSH, load average, memory and
+kubelet state, refreshed periodically.
+
+Keys:
+  ↑/↓ or k/j   select a node
+  s            open an SSH session on the node
+  l            tail the kubelet logs of the node
+  c / p        copy the public / private IP
+  r            refresh now
+  q            quit
+
+Examples:
+  get-cluster-info dashboard
+  get-cluster-info dashboard --interval 2s`,
+	Args: cobra.NoArgs,
+	RunE: runDashboard,
+}
+
+func init() {
+	dashboardCmd.Flags().DurationVar(&dashboardInterval, "interval", dashboard.DefaultInterval, "Refresh interval")
+
+	rootCmd.AddCommand(dashboardCmd)
+}
+
+func runDashboard(_ *cobra.Command, _ []string) error {
+	ctx := context.Background()
+
+	info, dialer, closeSrc, err := openCluster(ctx)
+	if err != nil {
+		return err
+	}
+
+	closeSrc()
+
+	if _, err := os.Stat(config.SSHKeyPath); errors.Is(err, os.ErrNotExist) {
+		logWarning("SSH key not found at %s: run get-cluster-info first to save it for the SSH sessions",
+			config.SSHKeyPath)
+	}
+
+	return dashboard.Run(info, dashboard.Options{
+		Prober:   &dashboard.Prober{Dialer: dialer},
+		Interval: dashboardInterval,
+		SSH:      sshCommand,
+		Copy:     clipboard.WriteAll,
+	})
+}
+
+// sshCommand returns the interactive ssh command of a node, with the lab key
+// and known hosts; remoteCmd runs in a terminal (-t), to stop it with Ctrl-C.
+func sshCommand(host string, remoteCmd ...string) *exec.Cmd {
+	args := []string{"-i", config.SSHKeyPath, "-o", "UserKnownHostsFile=" + knownHostsPath()}
+	if len(remoteCmd) > 0 {
+		args = append(args, "-t")
+	}
+
+	args = append(args, remote.DefaultUser+"@"+host)
+
+	return exec.Command("ssh", append(args, remoteCmd...)...)
+}
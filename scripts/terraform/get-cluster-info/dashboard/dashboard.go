@@ -0,0 +1,296 @@
+// Package dashboard is a live terminal dashboard of a K8s-Lab cluster, built
+// with Bubble Tea: the nodes with their reachability, load, memory and kubelet
+// state, refreshed over SSH, and keys to open an SSH session, copy the IPs or
+// tail the kubelet logs of the selected node.
+package dashboard
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/k8s-lab/get-cluster-info/clusterinfo"
+	"github.com/k8s-lab/get-cluster-info/render"
+)
+
+// =============================================================================
+// Model
+// =============================================================================
+
+// DefaultInterval is the default refresh interval.
+const DefaultInterval = 5 * time.Second
+
+// Options configures the dashboard.
+type Options struct {
+	Prober *Prober
+	// Interval between two refreshes (default DefaultInterval).
+	Interval time.Duration
+	// SSH returns the command opening an interactive SSH session on host,
+	// running remoteCmd when given.
+	SSH func(host string, remoteCmd ...string) *exec.Cmd
+	// Copy writes text to the clipboard.
+	Copy func(text string) error
+	// Now returns the current time (default time.Now).
+	Now func() time.Time
+}
+
+// Model is the Bubble Tea model of the dashboard.
+type Model struct {
+	info  *clusterinfo.ClusterInfo
+	nodes []clusterinfo.Node
+	opts  Options
+
+	stats     map[string]Stats
+	updatedAt time.Time
+	selected  int
+	message   string
+}
+
+// New returns the dashboard of the cluster.
+func New(info *clusterinfo.ClusterInfo, opts Options) Model {
+	if opts.Interval == 0 {
+		opts.Interval = DefaultInterval
+	}
+
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+
+	return Model{info: info, nodes: info.AllNodes(), opts: opts, stats: map[string]Stats{}}
+}
+
+// statsMsg carries the statistics of a node.
+type statsMsg struct {
+	node  string
+	stats Stats
+}
+
+// tickMsg triggers a refresh.
+type tickMsg struct{}
+
+// execDoneMsg reports the end of an SSH session.
+type execDoneMsg struct {
+	action string
+	err    error
+}
+
+// Init implements tea.Model: the first refresh.
+func (m Model) Init() tea.Cmd {
+	return tea.Batch(m.refresh(), m.tick())
+}
+
+// refresh probes every node concurrently.
+func (m Model) refresh() tea.Cmd {
+	cmds := make([]tea.Cmd, 0, len(m.nodes))
+
+	for _, n := range m.nodes {
+		cmds = append(cmds, func() tea.Msg {
+			return statsMsg{node: n.Name, stats: m.opts.Prober.Probe(context.Background(), n)}
+		})
+	}
+
+	return tea.Batch(cmds...)
+}
+
+// tick schedules the next periodic refresh.
+func (m Model) tick() tea.Cmd {
+	return tea.Tick(m.opts.Interval, func(time.Time) tea.Msg { return tickMsg{} })
+}
+
+// Update implements tea.Model.
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case statsMsg:
+		m.stats[msg.node] = msg.stats
+		m.updatedAt = m.opts.Now()
+	case tickMsg:
+		return m, tea.Batch(m.refresh(), m.tick())
+	case execDoneMsg:
+		m.message = msg.action + " ended"
+		if msg.err != nil {
+			m.message = fmt.Sprintf("%s failed: %v", msg.action, msg.err)
+		}
+	case tea.KeyMsg:
+		return m.key(msg.String())
+	}
+
+	return m, nil
+}
+
+// Keys of the dashboard.
+const help = "↑/↓ select • s ssh • l kubelet logs • c copy public IP • p copy private IP • r refresh • q quit"
+
+// key handles a key press.
+func (m Model) key(key string) (tea.Model, tea.Cmd) {
+	n := m.nodes[m.selected]
+
+	switch key {
+	case "q", "ctrl+c", "esc":
+		return m, tea.Quit
+	case "up", "k":
+		m.selected = (m.selected + len(m.nodes) - 1) % len(m.nodes)
+	case "down", "j":
+		m.selected = (m.selected + 1) % len(m.nodes)
+	case "r":
+		m.message = "refreshing..."
+
+		return m, m.refresh()
+	case "s":
+		return m, m.exec("SSH session on "+n.Name, m.opts.SSH(n.PublicIP))
+	case "l":
+		return m, m.exec("kubelet logs of "+n.Name,
+			m.opts.SSH(n.PublicIP, "sudo journalctl -u kubelet -f -n 100"))
+	case "c", "p":
+		ip, kind := n.PublicIP, "public"
+		if key == "p" {
+			ip, kind = n.PrivateIP, "private"
+		}
+
+		m.message = fmt.Sprintf("copied the %s IP of %s: %s", kind, n.Name, ip)
+		if err := m.opts.Copy(ip); err != nil {
+			m.message = fmt.Sprintf("copy failed: %v", err)
+		}
+	}
+
+	return m, nil
+}
+
+// exec suspends the dashboard while cmd runs in the terminal.
+func (m Model) exec(action string, cmd *exec.Cmd) tea.Cmd {
+	return tea.ExecProcess(cmd, func(err error) tea.Msg { return execDoneMsg{action: action, err: err} })
+}
+
+// =============================================================================
+// View
+// =============================================================================
+
+// memoryWarning and loadWarning are the thresholds shown in yellow.
+const (
+	memoryWarning = 0.85
+	loadWarning   = 1.0 // per CPU
+	kib           = 1024
+)
+
+var (
+	headerStyle   = lipgloss.NewStyle().Bold(true).Foreground(render.Cyan)
+	selectedStyle = lipgloss.NewStyle().Bold(true).Background(lipgloss.Color("236"))
+	okStyle       = lipgloss.NewStyle().Foreground(render.Green)
+	warnStyle     = lipgloss.NewStyle().Foreground(render.Yellow)
+	errStyle      = lipgloss.NewStyle().Foreground(render.Red)
+	dimStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
+)
+
+// columns of the node table: header and width.
+var columns = []struct {
+	title string
+	width int
+}{
+	{"", 2}, {"NODE", 16}, {"ROLE", 14}, {"PUBLIC IP", 16}, {"PRIVATE IP", 14},
+	{"SSH", 10}, {"LOAD 1/5/15", 18}, {"MEMORY", 20}, {"KUBELET", 10},
+}
+
+// View implements tea.Model.
+func (m Model) View() string {
+	var b strings.Builder
+
+	b.WriteString(render.TitleStyle.Render("🚀 K8S-LAB DASHBOARD") + "\n")
+
+	cells := make([]string, len(columns))
+	for i, c := range columns {
+		cells[i] = c.title
+	}
+
+	b.WriteString(headerStyle.Render(row(cells)) + "\n")
+
+	for i, n := range m.nodes {
+		line := row(m.cells(n, i == m.selected))
+		if i == m.selected {
+			line = selectedStyle.Render(line)
+		}
+
+		b.WriteString(line + "\n")
+	}
+
+	updated := "refreshing..."
+	if !m.updatedAt.IsZero() {
+		updated = "updated " + m.updatedAt.Format("15:04:05") + ", every " + m.opts.Interval.String()
+	}
+
+	b.WriteString("\n" + dimStyle.Render(updated) + "\n")
+
+	if s, ok := m.stats[m.nodes[m.selected].Name]; ok && !s.Reachable {
+		b.WriteString(errStyle.Render(m.nodes[m.selected].Name+": "+s.Err) + "\n")
+	}
+
+	if m.message != "" {
+		b.WriteString(m.message + "\n")
+	}
+
+	b.WriteString(dimStyle.Render(help) + "\n")
+
+	return b.String()
+}
+
+// cells returns the cells of the row of n.
+func (m Model) cells(n clusterinfo.Node, selected bool) []string {
+	cursor := ""
+	if selected {
+		cursor = "▸"
+	}
+
+	s, probed := m.stats[n.Name]
+
+	reach, load, mem, kubelet := dimStyle.Render("…"), "", "", ""
+
+	switch {
+	case !probed:
+	case !s.Reachable:
+		reach = errStyle.Render("✗ down")
+	default:
+		reach = okStyle.Render(fmt.Sprintf("✓ %dms", s.Latency.Milliseconds()))
+
+		load = fmt.Sprintf("%.2f %.2f %.2f", s.Load[0], s.Load[1], s.Load[2])
+		if s.CPUs > 0 && s.Load[0]/float64(s.CPUs) >= loadWarning {
+			load = warnStyle.Render(load)
+		}
+
+		mem = fmt.Sprintf("%3.0f%% of %.1f GiB", s.MemUsed()*100, float64(s.MemTotal)/kib/kib) //nolint:mnd // percent
+		if s.MemUsed() >= memoryWarning {
+			mem = warnStyle.Render(mem)
+		}
+
+		kubelet = okStyle.Render(s.Kubelet)
+		if s.Kubelet != "active" {
+			kubelet = warnStyle.Render(s.Kubelet)
+		}
+	}
+
+	return []string{cursor, n.Name, n.Role, n.PublicIP, n.PrivateIP, reach, load, mem, kubelet}
+}
+
+// row pads the cells to the column widths.
+func row(cells []string) string {
+	var b strings.Builder
+
+	for i, c := range cells {
+		b.WriteString(lipgloss.NewStyle().Width(columns[i].width).MaxWidth(columns[i].width).Render(c))
+	}
+
+	return strings.TrimRight(b.String(), " ")
+}
+
+// Run runs the dashboard in the terminal until the user quits.
+func Run(info *clusterinfo.ClusterInfo, opts Options) error {
+	defer opts.Prober.Close()
+
+	_, err := tea.NewProgram(New(info, opts), tea.WithAltScreen()).Run()
+	if err != nil {
+		return fmt.Errorf("dashboard failed: %w", err)
+	}
+
+	return nil
+}
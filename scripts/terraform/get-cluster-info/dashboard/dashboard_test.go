@@ -0,0 +1,239 @@
+package dashboard
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/k8s-lab/get-cluster-info/clusterinfo"
+	"github.com/k8s-lab/get-cluster-info/remote/remotetest"
+)
+
+// =============================================================================
+// Dashboard tests
+// =============================================================================
+
+const testStats = `0.42 0.30 0.15 1/123 4567
+2
+MemTotal:        4000000 kB
+MemAvailable:    1000000 kB
+active
+`
+
+var testInfo = &clusterinfo.ClusterInfo{Nodes: []clusterinfo.Node{
+	{Name: "control-plane", Role: clusterinfo.RoleControlPlane, NodeInfo: clusterinfo.NodeInfo{
+		PublicIP: "203.0.113.10", PrivateIP: "10.0.0.10",
+	}},
+	{Name: "worker", Role: clusterinfo.RoleWorker, NodeInfo: clusterinfo.NodeInfo{
+		PublicIP: "203.0.113.11", PrivateIP: "10.0.0.11",
+	}},
+}}
+
+func TestParseStats(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		out     string
+		want    Stats
+		wantErr error
+	}{
+		{
+			name: "valid",
+			out:  testStats,
+			want: Stats{
+				Reachable: true, Load: [3]float64{0.42, 0.30, 0.15}, CPUs: 2,
+				MemTotal: 4000000, MemAvailable: 1000000, Kubelet: "active",
+			},
+		},
+		{
+			name: "kubelet not installed",
+			out:  strings.Replace(testStats, "active", "inactive", 1),
+			want: Stats{
+				Reachable: true, Load: [3]float64{0.42, 0.30, 0.15}, CPUs: 2,
+				MemTotal: 4000000, MemAvailable: 1000000, Kubelet: "inactive",
+			},
+		},
+		{name: "truncated", out: "0.42 0.30 0.15 1/123 4567\n2\n", wantErr: ErrInvalidStats},
+		{name: "invalid load", out: strings.Replace(testStats, "0.42", "high", 1), wantErr: ErrInvalidStats},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseStats([]byte(tt.out))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseStats() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("ParseStats() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	if s := (Stats{MemTotal: 4000000, MemAvailable: 1000000}); s.MemUsed() != 0.75 {
+		t.Errorf("MemUsed() = %v, want 0.75", s.MemUsed())
+	}
+}
+
+func TestProber(t *testing.T) {
+	t.Parallel()
+
+	fail := false
+	dialer := &remotetest.FakeDialer{
+		Handler: func(_, _ string) ([]byte, error) {
+			if fail {
+				return nil, errors.New("connection lost")
+			}
+
+			return []byte(testStats), nil
+		},
+		DialErr: map[string]error{"203.0.113.11": errors.New("no route to host")},
+	}
+	p := &Prober{Dialer: dialer}
+
+	defer p.Close()
+
+	for range 2 {
+		if s := p.Probe(context.Background(), testInfo.Nodes[0]); !s.Reachable || s.Kubelet != "active" {
+			t.Errorf("Probe() = %+v, want reachable", s)
+		}
+	}
+
+	// One connection, reused.
+	if dialer.Closed() != 0 {
+		t.Errorf("%d connections closed, want 0", dialer.Closed())
+	}
+
+	fail = true
+	if s := p.Probe(context.Background(), testInfo.Nodes[0]); s.Reachable || s.Err != "connection lost" {
+		t.Errorf("Probe() = %+v, want unreachable", s)
+	}
+
+	if dialer.Closed() != 1 {
+		t.Error("failed connection not closed")
+	}
+
+	if s := p.Probe(context.Background(), testInfo.Nodes[1]); s.Reachable || !strings.Contains(s.Err, "no route") {
+		t.Errorf("Probe() = %+v, want unreachable", s)
+	}
+}
+
+// newTestModel returns a dashboard whose SSH commands and copies are recorded.
+func newTestModel(copied *[]string, ssh *[]string) Model {
+	return New(testInfo, Options{
+		Prober: &Prober{Dialer: &remotetest.FakeDialer{Handler: func(_, _ string) ([]byte, error) {
+			return []byte(testStats), nil
+		}}},
+		SSH: func(host string, remoteCmd ...string) *exec.Cmd {
+			*ssh = append(*ssh, strings.Join(append([]string{host}, remoteCmd...), " "))
+
+			return exec.Command("true")
+		},
+		Copy: func(text string) error {
+			*copied = append(*copied, text)
+
+			return nil
+		},
+		Now: func() time.Time { return time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC) },
+	})
+}
+
+func press(m Model, key string) (Model, tea.Cmd) {
+	msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+	if key == "down" {
+		msg = tea.KeyMsg{Type: tea.KeyDown}
+	}
+
+	next, cmd := m.Update(msg)
+
+	return next.(Model), cmd //nolint:forcetypeassert // Update returns a Model
+}
+
+func TestModel(t *testing.T) {
+	t.Parallel()
+
+	var copied, ssh []string
+
+	m := newTestModel(&copied, &ssh)
+
+	// Statistics of the control plane; the worker is down.
+	next, _ := m.Update(statsMsg{node: "control-plane", stats: Stats{
+		Reachable: true, Latency: 12 * time.Millisecond, Load: [3]float64{0.42, 0.30, 0.15}, CPUs: 2,
+		MemTotal: 4 * 1024 * 1024, MemAvailable: 1024 * 1024, Kubelet: "active",
+	}})
+	next, _ = next.Update(statsMsg{node: "worker", stats: Stats{Err: "connection refused"}})
+	m = next.(Model) //nolint:forcetypeassert // Update returns a Model
+
+	view := m.View()
+	for _, want := range []string{"✓ 12ms", "0.42 0.30 0.15", "75% of 4.0 GiB", "active", "✗ down", "updated 12:00:00"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q:\n%s", want, view)
+		}
+	}
+
+	// Copy the IPs of the worker.
+	m, _ = press(m, "down")
+	m, _ = press(m, "c")
+	m, _ = press(m, "p")
+
+	if strings.Join(copied, " ") != "203.0.113.11 10.0.0.11" {
+		t.Errorf("copied = %v, want the worker IPs", copied)
+	}
+
+	if view := m.View(); !strings.Contains(view, "copied the private IP of worker: 10.0.0.11") ||
+		!strings.Contains(view, "worker: connection refused") {
+		t.Errorf("view missing the copy message or the worker error:\n%s", view)
+	}
+
+	// SSH session and logs on the selected node.
+	if _, cmd := press(m, "s"); cmd == nil {
+		t.Error("s: no SSH session")
+	}
+
+	if _, cmd := press(m, "l"); cmd == nil {
+		t.Error("l: no logs session")
+	}
+
+	if want := []string{"203.0.113.11", "203.0.113.11 sudo journalctl -u kubelet -f -n 100"}; strings.Join(ssh, ",") !=
+		strings.Join(want, ",") {
+		t.Errorf("ssh = %q, want %q", ssh, want)
+	}
+
+	// Selection wraps around.
+	m, _ = press(m, "down")
+	if m.selected != 0 {
+		t.Errorf("selected = %d after wrapping, want 0", m.selected)
+	}
+
+	if _, cmd := press(m, "q"); cmd == nil {
+		t.Error("q: no quit command")
+	}
+}
+
+func TestModelRefresh(t *testing.T) {
+	t.Parallel()
+
+	var copied, ssh []string
+
+	m := newTestModel(&copied, &ssh)
+
+	// The probes of the first refresh report every node.
+	batch, ok := m.refresh()().(tea.BatchMsg)
+	if !ok || len(batch) != len(testInfo.Nodes) {
+		t.Fatalf("refresh() = %v, want a probe per node", batch)
+	}
+
+	for _, probe := range batch {
+		msg, ok := probe().(statsMsg)
+		if !ok || !msg.stats.Reachable {
+			t.Errorf("probe = %+v, want reachable statistics", msg)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package dashboard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/k8s-lab/get-cluster-info/clusterinfo"
+	"github.com/k8s-lab/get-cluster-info/remote"
+)
+
+// =============================================================================
+// Node statistics
+// =============================================================================
+
+// statsCmd prints the load average, the CPU count, the memory and the kubelet
+// state, one per line (see ParseStats).
+const statsCmd = "cat /proc/loadavg; nproc; grep -E '^(MemTotal|MemAvailable):' /proc/meminfo; " +
+	"systemctl is-active kubelet || true"
+
+// ErrInvalidStats is returned for an unexpected output of the statistics command.
+var ErrInvalidStats = errors.New("unexpected statistics output")
+
+// Stats is the state of a node at the last refresh.
+type Stats struct {
+	// Reachable is false when the SSH connection or the command failed (see Err).
+	Reachable bool
+	Err       string
+	Latency   time.Duration
+	// Load is the 1, 5 and 15 minutes load average.
+	Load [3]float64
+	CPUs int
+	// MemTotal and MemAvailable are in KiB.
+	MemTotal     uint64
+	MemAvailable uint64
+	// Kubelet is the systemd state of the kubelet (active, inactive, failed...).
+	Kubelet string
+}
+
+// MemUsed returns the fraction of the memory in use.
+func (s Stats) MemUsed() float64 {
+	if s.MemTotal == 0 {
+		return 0
+	}
+
+	return float64(s.MemTotal-s.MemAvailable) / float64(s.MemTotal)
+}
+
+// ParseStats parses the output of statsCmd.
+func ParseStats(out []byte) (Stats, error) {
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	if len(lines) != 5 { //nolint:mnd // loadavg, nproc, 2 meminfo lines, kubelet
+		return Stats{}, fmt.Errorf("%w: %d lines", ErrInvalidStats, len(lines))
+	}
+
+	s := Stats{Reachable: true, Kubelet: strings.TrimSpace(lines[4])}
+
+	load := strings.Fields(lines[0])
+	if len(load) < len(s.Load) {
+		return Stats{}, fmt.Errorf("%w: loadavg %q", ErrInvalidStats, lines[0])
+	}
+
+	for i := range s.Load {
+		v, err := strconv.ParseFloat(load[i], 64)
+		if err != nil {
+			return Stats{}, fmt.Errorf("%w: loadavg %q", ErrInvalidStats, lines[0])
+		}
+
+		s.Load[i] = v
+	}
+
+	cpus, err := strconv.Atoi(strings.TrimSpace(lines[1]))
+	if err != nil {
+		return Stats{}, fmt.Errorf("%w: nproc %q", ErrInvalidStats, lines[1])
+	}
+
+	s.CPUs = cpus
+
+	for _, line := range lines[2:4] {
+		fields := strings.Fields(line)
+		if len(fields) < 2 { //nolint:mnd // "MemTotal: 4021000 kB"
+			return Stats{}, fmt.Errorf("%w: meminfo %q", ErrInvalidStats, line)
+		}
+
+		v, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return Stats{}, fmt.Errorf("%w: meminfo %q", ErrInvalidStats, line)
+		}
+
+		if fields[0] == "MemTotal:" {
+			s.MemTotal = v
+		} else {
+			s.MemAvailable = v
+		}
+	}
+
+	return s, nil
+}
+
+// Prober reads the statistics of the nodes, keeping one SSH connection per node.
+type Prober struct {
+	Dialer remote.Dialer
+	// Timeout bounds a probe, connection included (default remote.DefaultTimeout).
+	Timeout time.Duration
+
+	mu      sync.Mutex
+	clients map[string]remote.Client
+}
+
+// Probe returns the statistics of n. Failures make the node unreachable.
+func (p *Prober) Probe(ctx context.Context, n clusterinfo.Node) Stats {
+	timeout := p.Timeout
+	if timeout == 0 {
+		timeout = remote.DefaultTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+
+	client, err := p.client(ctx, n.PublicIP)
+	if err != nil {
+		return Stats{Err: err.Error()}
+	}
+
+	out, err := client.Run(ctx, statsCmd)
+	if err != nil {
+		// Reconnect at the next probe.
+		p.drop(n.PublicIP)
+
+		return Stats{Err: err.Error()}
+	}
+
+	s, err := ParseStats(out)
+	if err != nil {
+		return Stats{Err: err.Error()}
+	}
+
+	s.Latency = time.Since(start)
+
+	return s
+}
+
+// Close closes the SSH connections.
+func (p *Prober) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for host, c := range p.clients {
+		_ = c.Close()
+
+		delete(p.clients, host)
+	}
+}
+
+func (p *Prober) client(ctx context.Context, host string) (remote.Client, error) {
+	p.mu.Lock()
+	c, ok := p.clients[host]
+	p.mu.Unlock()
+
+	if ok {
+		return c, nil
+	}
+
+	c, err := p.Dialer.Dial(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.clients == nil {
+		p.clients = map[string]remote.Client{}
+	}
+
+	// Another probe connected meanwhile.
+	if existing, ok := p.clients[host]; ok {
+		_ = c.Close()
+
+		return existing, nil
+	}
+
+	p.clients[host] = c
+
+	return c, nil
+}
+
+func (p *Prober) drop(host string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if c, ok := p.clients[host]; ok {
+		_ = c.Close()
+
+		delete(p.clients, host)
+	}
+}
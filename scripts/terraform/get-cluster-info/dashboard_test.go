@@ -0,0 +1,48 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+// =============================================================================
+// dashboard subcommand tests
+// =============================================================================
+
+func TestSSHCommand(t *testing.T) {
+	// Not parallel - modifies global config
+	old := config.SSHKeyPath
+	t.Cleanup(func() { config.SSHKeyPath = old })
+
+	config.SSHKeyPath = "/home/lab/.ssh/k8s-lab.pem"
+
+	tests := []struct {
+		name      string
+		remoteCmd []string
+		want      []string
+	}{
+		{
+			name: "session",
+			want: []string{
+				"ssh", "-i", "/home/lab/.ssh/k8s-lab.pem", "-o", "UserKnownHostsFile=/home/lab/.ssh/k8s-lab_known_hosts",
+				"ubuntu@203.0.113.10",
+			},
+		},
+		{
+			name:      "remote command in a terminal",
+			remoteCmd: []string{"sudo journalctl -u kubelet -f"},
+			want: []string{
+				"ssh", "-i", "/home/lab/.ssh/k8s-lab.pem", "-o", "UserKnownHostsFile=/home/lab/.ssh/k8s-lab_known_hosts",
+				"-t", "ubuntu@203.0.113.10", "sudo journalctl -u kubelet -f",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sshCommand("203.0.113.10", tt.remoteCmd...).Args; !slices.Equal(got, tt.want) {
+				t.Errorf("sshCommand() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/k8s-lab/get-cluster-info/doctor"
+	"github.com/k8s-lab/get-cluster-info/i18n"
+	"github.com/k8s-lab/get-cluster-info/render"
+	"github.com/spf13/cobra"
+)
+
+// =============================================================================
+// Doctor subcommand
+// =============================================================================
+//
+// Checks the workstation before a deployment (replaces `make check`): terraform
+// version, backend file and credentials, Scaleway variables, terraform.tfvars,
+// SSH key and agent, kubectl and helm. Warnings do not change the exit code;
+// any failed check makes the command exit with an error.
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the prerequisites and suggest fixes",
+	Long: `Check the prerequisites to deploy and use the lab, and suggest fixes.
+
+Checks: terraform version, backend file permissions and credentials,
+Scaleway variables, terraform.tfvars, SSH key permissions, ssh-agent,
+kubectl and helm. Nothing is modified.
+
+Examples:
+  # Human readable report
+  get-cluster-info doctor
+
+  # JSON report for scripting
+  get-cluster-info doctor --json`,
+	Args: cobra.NoArgs,
+	RunE: runDoctor,
+}
+
+func init() {
+	doctorCmd.Flags().StringVarP(&config.SSHKeyPath, "ssh-key", "k", "",
+		"Path of the SSH key to check (default: ~/.ssh/k8s-lab.pem)")
+
+	rootCmd.AddCommand(doctorCmd)
+}
+
+func runDoctor(_ *cobra.Command, _ []string) error {
+	if err := resolveDefaults(); err != nil {
+		return err
+	}
+
+	report := doctor.Doctor{
+		TerraformDir:    config.TerraformDir,
+		CredentialsFile: config.CredentialsFile,
+		SSHKeyPath:      config.SSHKeyPath,
+		LookPath:        doctorLookPath,
+		Exec:            doctorExec,
+	}.Run(context.Background())
+
+	if config.JSONOutput {
+		data, _ := json.MarshalIndent(report, "", "  ")
+		fmt.Fprintln(stdout, string(data))
+	} else if err := render.DoctorReport(stdout, report); err != nil {
+		return err
+	}
+
+	if report.Failed() {
+		return i18n.Errorf("%w: %d failed check(s)", doctor.ErrChecksFailed, report.Count(doctor.StatusFail))
+	}
+
+	return nil
+}
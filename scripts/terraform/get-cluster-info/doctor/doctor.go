@@ -0,0 +1,392 @@
+// Package doctor checks that the workstation is ready to deploy and use a K8s-Lab
+// cluster: tools, Terraform configuration, credentials and file permissions.
+//
+// Every check returns a Result with an actionable hint. Nothing is modified.
+package doctor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/netip"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+	"github.com/k8s-lab/get-cluster-info/clusterinfo"
+)
+
+// =============================================================================
+// Types
+// =============================================================================
+
+// Status is the outcome of a check.
+type Status string
+
+// Check outcomes. Only StatusFail makes the report fail.
+const (
+	StatusOK   Status = "ok"
+	StatusWarn Status = "warn"
+	StatusFail Status = "fail"
+	StatusSkip Status = "skip"
+)
+
+// Result is the outcome of a single check.
+type Result struct {
+	Name    string `json:"name"`
+	Status  Status `json:"status"`
+	Message string `json:"message"`
+	Hint    string `json:"hint,omitempty"`
+}
+
+// ErrChecksFailed is returned when at least one check failed.
+var ErrChecksFailed = errors.New("doctor found problems")
+
+// Report is the outcome of every check, in order.
+type Report struct {
+	Checks []Result `json:"checks"`
+}
+
+// Failed reports whether at least one check failed.
+func (r Report) Failed() bool {
+	return r.Count(StatusFail) > 0
+}
+
+// Count returns the number of checks with the given status.
+func (r Report) Count(status Status) int {
+	n := 0
+
+	for _, c := range r.Checks {
+		if c.Status == status {
+			n++
+		}
+	}
+
+	return n
+}
+
+// =============================================================================
+// Doctor
+// =============================================================================
+
+// MinTerraformVersion is required by the backend block (endpoints = { s3 = ... } syntax).
+const MinTerraformVersion = "1.6.0"
+
+// Permission bits that must not be set on secret files (group and others).
+const secretPermMask fs.FileMode = 0o077
+
+// Scaleway provider credentials (environment or CLI config file).
+var scalewayEnvVars = []string{"SCW_ACCESS_KEY", "SCW_SECRET_KEY", "SCW_DEFAULT_PROJECT_ID"}
+
+// Doctor runs the checks. The function fields default to the real system and are
+// replaced in tests.
+type Doctor struct {
+	// TerraformDir is the directory containing the Terraform files.
+	TerraformDir string
+	// CredentialsFile is the S3 backend file (backend.yaml or backend.json).
+	CredentialsFile string
+	// SSHKeyPath is where get-cluster-info saves the SSH private key.
+	SSHKeyPath string
+
+	// LookPath finds a binary (default: exec.LookPath).
+	LookPath func(file string) (string, error)
+	// Exec runs a command and returns its stdout (default: os/exec).
+	Exec func(ctx context.Context, name string, args ...string) ([]byte, error)
+	// Getenv reads an environment variable (default: os.Getenv).
+	Getenv func(key string) string
+}
+
+// Run runs every check, in order.
+func (d Doctor) Run(ctx context.Context) Report {
+	d = d.withDefaults()
+
+	checks := []func() Result{
+		func() Result { return d.checkTerraform(ctx) },
+		d.checkBackendFile,
+		d.checkCredentials,
+		d.checkScalewayEnv,
+		d.checkTFVars,
+		d.checkSSHKey,
+		d.checkSSHAgent,
+		func() Result { return d.checkTool("kubectl", "https://kubernetes.io/docs/tasks/tools/") },
+		func() Result { return d.checkTool("helm", "https://helm.sh/docs/intro/install/") },
+	}
+
+	report := Report{}
+	for _, check := range checks {
+		report.Checks = append(report.Checks, check())
+	}
+
+	return report
+}
+
+func (d Doctor) withDefaults() Doctor {
+	if d.LookPath == nil {
+		d.LookPath = exec.LookPath
+	}
+
+	if d.Exec == nil {
+		d.Exec = func(ctx context.Context, name string, args ...string) ([]byte, error) {
+			return exec.CommandContext(ctx, name, args...).Output() //nolint:gosec // fixed commands
+		}
+	}
+
+	if d.Getenv == nil {
+		d.Getenv = os.Getenv
+	}
+
+	return d
+}
+
+// =============================================================================
+// Checks
+// =============================================================================
+
+// checkTerraform checks that terraform is installed and satisfies required_version.
+func (d Doctor) checkTerraform(ctx context.Context) Result {
+	const name = "terraform"
+
+	hint := "Install Terraform >= " + MinTerraformVersion + ": https://developer.hashicorp.com/terraform/install"
+
+	path, err := d.LookPath("terraform")
+	if err != nil {
+		return Result{Name: name, Status: StatusFail, Message: "terraform not found in PATH", Hint: hint}
+	}
+
+	out, err := d.Exec(ctx, path, "version", "-json")
+	if err != nil {
+		return Result{Name: name, Status: StatusFail, Message: "`terraform version` failed: " + err.Error(), Hint: hint}
+	}
+
+	var v struct {
+		Version string `json:"terraform_version"` //nolint:tagliatelle // Terraform format
+	}
+	if err := json.Unmarshal(out, &v); err != nil {
+		return Result{Name: name, Status: StatusFail, Message: "unexpected `terraform version` output", Hint: hint}
+	}
+
+	installed, err := version.NewVersion(v.Version)
+	if err != nil {
+		return Result{Name: name, Status: StatusFail, Message: "unexpected terraform version " + v.Version, Hint: hint}
+	}
+
+	constraints := ">= " + MinTerraformVersion
+	if required, err := clusterinfo.LoadRequiredVersion(d.TerraformDir); err == nil && required != "" {
+		constraints += ", " + required
+	}
+
+	c, err := version.NewConstraint(constraints)
+	if err != nil {
+		return Result{
+			Name:    name,
+			Status:  StatusWarn,
+			Message: "invalid required_version: " + err.Error(),
+			Hint:    "Fix required_version in the terraform block",
+		}
+	}
+
+	if !c.Check(installed) {
+		return Result{
+			Name:    name,
+			Status:  StatusFail,
+			Message: fmt.Sprintf("terraform %s does not satisfy %s", installed, constraints),
+			Hint:    "Upgrade Terraform: https://developer.hashicorp.com/terraform/install",
+		}
+	}
+
+	return Result{Name: name, Status: StatusOK, Message: fmt.Sprintf("terraform %s (%s)", installed, constraints)}
+}
+
+// checkBackendFile checks that the backend file exists and is not readable by others.
+func (d Doctor) checkBackendFile() Result {
+	const name = "backend file"
+
+	stat, err := os.Stat(d.CredentialsFile)
+	if err != nil {
+		return Result{
+			Name:    name,
+			Status:  StatusFail,
+			Message: d.CredentialsFile + " not found",
+			Hint: fmt.Sprintf("cp %s %s && chmod 600 %s",
+				filepath.Join(d.TerraformDir, "backend.yaml.example"), d.CredentialsFile, d.CredentialsFile),
+		}
+	}
+
+	return checkSecretPerm(name, d.CredentialsFile, stat)
+}
+
+// checkCredentials checks that the backend file holds both S3 keys.
+func (d Doctor) checkCredentials() Result {
+	const name = "credentials"
+
+	if _, err := os.Stat(d.CredentialsFile); err != nil {
+		return Result{Name: name, Status: StatusSkip, Message: "no backend file"}
+	}
+
+	if _, err := (clusterinfo.FileCredentials{Path: d.CredentialsFile}).Credentials(context.Background()); err != nil {
+		return Result{
+			Name:    name,
+			Status:  StatusFail,
+			Message: err.Error(),
+			Hint: "Fill access_key and secret_key in " + d.CredentialsFile +
+				" (Scaleway Console > Object Storage > Credentials)",
+		}
+	}
+
+	return Result{Name: name, Status: StatusOK, Message: "access_key and secret_key set"}
+}
+
+// checkScalewayEnv checks the Scaleway provider credentials, needed by plan and apply.
+func (d Doctor) checkScalewayEnv() Result {
+	const name = "scaleway credentials"
+
+	var missing []string
+
+	for _, key := range scalewayEnvVars {
+		if d.Getenv(key) == "" {
+			missing = append(missing, key)
+		}
+	}
+
+	if len(missing) == 0 {
+		return Result{Name: name, Status: StatusOK, Message: strings.Join(scalewayEnvVars, ", ") + " set"}
+	}
+
+	if home := d.Getenv("HOME"); home != "" {
+		cliConfig := filepath.Join(home, ".config", "scw", "config.yaml")
+		if _, err := os.Stat(cliConfig); err == nil {
+			return Result{Name: name, Status: StatusOK, Message: "read from " + cliConfig}
+		}
+	}
+
+	return Result{
+		Name:    name,
+		Status:  StatusWarn,
+		Message: strings.Join(missing, ", ") + " not set (needed by terraform plan/apply)",
+		Hint:    "export " + missing[0] + "=... (Scaleway Console > IAM > API keys), or run `scw init`",
+	}
+}
+
+// checkTFVars checks that terraform.tfvars parses, only sets declared variables, and holds valid CIDRs.
+func (d Doctor) checkTFVars() Result {
+	const name = "terraform.tfvars"
+
+	path := filepath.Join(d.TerraformDir, clusterinfo.TFVarsFile)
+	if _, err := os.Stat(path); err != nil {
+		return Result{
+			Name:    name,
+			Status:  StatusWarn,
+			Message: path + " not found - variable defaults are used",
+			Hint:    "get-cluster-info init-config   # or make setup",
+		}
+	}
+
+	set, err := clusterinfo.LoadTFVarsNames(path)
+	if err != nil {
+		return Result{Name: name, Status: StatusFail, Message: err.Error(), Hint: "Fix the syntax error in " + path}
+	}
+
+	declared, err := clusterinfo.LoadVariableNames(d.TerraformDir)
+	if err != nil {
+		return Result{Name: name, Status: StatusFail, Message: err.Error()}
+	}
+
+	for _, v := range set {
+		if !slices.Contains(declared, v) {
+			return Result{
+				Name:    name,
+				Status:  StatusFail,
+				Message: fmt.Sprintf("unknown variable %q", v),
+				Hint:    "Remove it or check its spelling in variables.tf",
+			}
+		}
+	}
+
+	for _, v := range []string{"allowed_ssh_cidr", clusterinfo.PrivateNetworkCIDRVar} {
+		value, err := clusterinfo.LoadVariableValue(d.TerraformDir, v)
+		if err != nil || value == "" {
+			continue
+		}
+
+		if _, err := netip.ParsePrefix(value); err != nil {
+			return Result{
+				Name:    name,
+				Status:  StatusFail,
+				Message: fmt.Sprintf("%s = %q is not a valid CIDR", v, value),
+				Hint:    `Use the a.b.c.d/n notation, e.g. "1.2.3.4/32"`,
+			}
+		}
+	}
+
+	return Result{Name: name, Status: StatusOK, Message: fmt.Sprintf("%d variable(s) set", len(set))}
+}
+
+// checkSSHKey checks the permissions of the saved SSH private key.
+func (d Doctor) checkSSHKey() Result {
+	const name = "ssh key"
+
+	stat, err := os.Stat(d.SSHKeyPath)
+	if err != nil {
+		return Result{
+			Name:    name,
+			Status:  StatusSkip,
+			Message: d.SSHKeyPath + " not saved yet",
+			Hint:    "Run get-cluster-info once the cluster is deployed",
+		}
+	}
+
+	return checkSecretPerm(name, d.SSHKeyPath, stat)
+}
+
+// checkSSHAgent checks that an ssh-agent socket is available.
+func (d Doctor) checkSSHAgent() Result {
+	const name = "ssh-agent"
+
+	hint := `eval "$(ssh-agent -s)" && ssh-add ` + d.SSHKeyPath
+
+	sock := d.Getenv("SSH_AUTH_SOCK")
+	if sock == "" {
+		return Result{Name: name, Status: StatusWarn, Message: "SSH_AUTH_SOCK not set", Hint: hint}
+	}
+
+	stat, err := os.Stat(sock)
+	if err != nil || stat.Mode()&fs.ModeSocket == 0 {
+		return Result{Name: name, Status: StatusWarn, Message: "no agent listening on " + sock, Hint: hint}
+	}
+
+	return Result{Name: name, Status: StatusOK, Message: "agent available"}
+}
+
+// checkTool checks that an optional client tool is installed.
+func (d Doctor) checkTool(tool, installURL string) Result {
+	path, err := d.LookPath(tool)
+	if err != nil {
+		return Result{
+			Name:    tool,
+			Status:  StatusWarn,
+			Message: tool + " not found in PATH",
+			Hint:    "Install " + tool + ": " + installURL,
+		}
+	}
+
+	return Result{Name: tool, Status: StatusOK, Message: path}
+}
+
+// checkSecretPerm warns if a secret file is readable by group or others.
+func checkSecretPerm(name, path string, stat fs.FileInfo) Result {
+	if perm := stat.Mode().Perm(); perm&secretPermMask != 0 {
+		return Result{
+			Name:    name,
+			Status:  StatusWarn,
+			Message: fmt.Sprintf("%s has permissions %04o, should be 0600", path, perm),
+			Hint:    "chmod 600 " + path,
+		}
+	}
+
+	return Result{Name: name, Status: StatusOK, Message: path}
+}
@@ -0,0 +1,378 @@
+package doctor
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// =============================================================================
+// Check tests
+// =============================================================================
+
+const testVariables = `
+variable "project_name" {
+  default = "k8s-lab"
+}
+
+variable "allowed_ssh_cidr" {
+  default = "0.0.0.0/0"
+}
+`
+
+// newTestDoctor returns a Doctor over a temporary terraform dir, with no tools installed
+// and an empty environment.
+func newTestDoctor(t *testing.T) Doctor {
+	t.Helper()
+
+	dir := t.TempDir()
+	must(t, os.WriteFile(filepath.Join(dir, "main.tf"),
+		[]byte("terraform {\n  required_version = \">= 1.0\"\n}\n"), 0o600))
+	must(t, os.WriteFile(filepath.Join(dir, "variables.tf"), []byte(testVariables), 0o600))
+
+	return Doctor{
+		TerraformDir:    dir,
+		CredentialsFile: filepath.Join(dir, "backend.yaml"),
+		SSHKeyPath:      filepath.Join(dir, "k8s-lab.pem"),
+		LookPath:        func(file string) (string, error) { return "", errors.New(file + " not found") },
+		Exec: func(context.Context, string, ...string) ([]byte, error) {
+			return nil, errors.New("unexpected command")
+		},
+		Getenv: func(string) string { return "" },
+	}
+}
+
+// withTerraform makes LookPath find terraform, reporting the given version.
+func withTerraform(d Doctor, version string) Doctor {
+	d.LookPath = func(file string) (string, error) { return "/usr/bin/" + file, nil }
+	d.Exec = func(_ context.Context, _ string, _ ...string) ([]byte, error) {
+		return []byte(`{"terraform_version":"` + version + `","platform":"linux_amd64"}`), nil
+	}
+
+	return d
+}
+
+func TestCheckTerraform(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		version     string // "" = not installed
+		wantStatus  Status
+		msgContains string
+	}{
+		{name: "not installed - fail", wantStatus: StatusFail, msgContains: "not found"},
+		{name: "too old for the backend syntax - fail", version: "1.5.7", wantStatus: StatusFail, msgContains: "1.5.7"},
+		{name: "recent - ok", version: "1.9.8", wantStatus: StatusOK, msgContains: "1.9.8"},
+		{name: "garbage version - fail", version: "dev", wantStatus: StatusFail, msgContains: "unexpected"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			d := newTestDoctor(t)
+			if tt.version != "" {
+				d = withTerraform(d, tt.version)
+			}
+
+			assertResult(t, d.checkTerraform(context.Background()), tt.wantStatus, tt.msgContains)
+		})
+	}
+}
+
+func TestCheckSecretFiles(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		check       func(d Doctor) Result
+		file        func(d Doctor) string
+		content     string
+		perm        os.FileMode // 0 = no file
+		wantStatus  Status
+		msgContains string
+	}{
+		{
+			name:       "backend file missing - fail",
+			check:      Doctor.checkBackendFile,
+			file:       func(d Doctor) string { return d.CredentialsFile },
+			wantStatus: StatusFail,
+		},
+		{
+			name:        "backend file world readable - warn",
+			check:       Doctor.checkBackendFile,
+			file:        func(d Doctor) string { return d.CredentialsFile },
+			perm:        0o644,
+			wantStatus:  StatusWarn,
+			msgContains: "0644",
+		},
+		{
+			name:       "backend file 0600 - ok",
+			check:      Doctor.checkBackendFile,
+			file:       func(d Doctor) string { return d.CredentialsFile },
+			perm:       0o600,
+			wantStatus: StatusOK,
+		},
+		{
+			name:        "credentials incomplete - fail",
+			check:       Doctor.checkCredentials,
+			file:        func(d Doctor) string { return d.CredentialsFile },
+			content:     "access_key: AKIA\nsecret_key: \"\"\n",
+			perm:        0o600,
+			wantStatus:  StatusFail,
+			msgContains: "missing",
+		},
+		{
+			name:       "credentials set - ok",
+			check:      Doctor.checkCredentials,
+			file:       func(d Doctor) string { return d.CredentialsFile },
+			content:    "access_key: AKIA\nsecret_key: secret\n",
+			perm:       0o600,
+			wantStatus: StatusOK,
+		},
+		{
+			name:       "ssh key not saved - skip",
+			check:      Doctor.checkSSHKey,
+			file:       func(d Doctor) string { return d.SSHKeyPath },
+			wantStatus: StatusSkip,
+		},
+		{
+			name:        "ssh key group readable - warn",
+			check:       Doctor.checkSSHKey,
+			file:        func(d Doctor) string { return d.SSHKeyPath },
+			perm:        0o640,
+			wantStatus:  StatusWarn,
+			msgContains: "0640",
+		},
+		{
+			name:       "ssh key read-only for owner - ok",
+			check:      Doctor.checkSSHKey,
+			file:       func(d Doctor) string { return d.SSHKeyPath },
+			perm:       0o400,
+			wantStatus: StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			d := newTestDoctor(t)
+
+			if tt.perm != 0 {
+				path := tt.file(d)
+				must(t, os.WriteFile(path, []byte(tt.content), 0o600))
+				must(t, os.Chmod(path, tt.perm))
+			}
+
+			assertResult(t, tt.check(d), tt.wantStatus, tt.msgContains)
+		})
+	}
+}
+
+func TestCheckScalewayEnv(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		env         map[string]string
+		cliConfig   bool
+		wantStatus  Status
+		msgContains string
+	}{
+		{
+			name: "all variables set - ok",
+			env: map[string]string{
+				"SCW_ACCESS_KEY": "a", "SCW_SECRET_KEY": "s", "SCW_DEFAULT_PROJECT_ID": "p",
+			},
+			wantStatus: StatusOK,
+		},
+		{
+			name:        "variables missing - warn",
+			env:         map[string]string{"SCW_ACCESS_KEY": "a"},
+			wantStatus:  StatusWarn,
+			msgContains: "SCW_SECRET_KEY, SCW_DEFAULT_PROJECT_ID",
+		},
+		{
+			name:        "scw CLI config - ok",
+			cliConfig:   true,
+			wantStatus:  StatusOK,
+			msgContains: "config.yaml",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			d := newTestDoctor(t)
+			home := t.TempDir()
+
+			if tt.cliConfig {
+				must(t, os.MkdirAll(filepath.Join(home, ".config", "scw"), 0o700))
+				must(t, os.WriteFile(filepath.Join(home, ".config", "scw", "config.yaml"), nil, 0o600))
+			}
+
+			d.Getenv = func(key string) string {
+				if key == "HOME" {
+					return home
+				}
+
+				return tt.env[key]
+			}
+
+			assertResult(t, d.checkScalewayEnv(), tt.wantStatus, tt.msgContains)
+		})
+	}
+}
+
+func TestCheckTFVars(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		tfvars      string // "" = no file
+		wantStatus  Status
+		msgContains string
+	}{
+		{name: "missing - warn", wantStatus: StatusWarn, msgContains: "not found"},
+		{name: "valid - ok", tfvars: "project_name = \"lab\"\n", wantStatus: StatusOK, msgContains: "1 variable"},
+		{name: "syntax error - fail", tfvars: "project_name = \n", wantStatus: StatusFail},
+		{
+			name:        "unknown variable - fail",
+			tfvars:      "projet_name = \"lab\"\n",
+			wantStatus:  StatusFail,
+			msgContains: `"projet_name"`,
+		},
+		{
+			name:        "invalid CIDR - fail",
+			tfvars:      "allowed_ssh_cidr = \"1.2.3.4\"\n",
+			wantStatus:  StatusFail,
+			msgContains: "not a valid CIDR",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			d := newTestDoctor(t)
+			if tt.tfvars != "" {
+				must(t, os.WriteFile(filepath.Join(d.TerraformDir, "terraform.tfvars"), []byte(tt.tfvars), 0o600))
+			}
+
+			assertResult(t, d.checkTFVars(), tt.wantStatus, tt.msgContains)
+		})
+	}
+}
+
+func TestCheckTFVarsHint(t *testing.T) {
+	t.Parallel()
+
+	// The file is created by the init-config wizard.
+	if r := newTestDoctor(t).checkTFVars(); !strings.Contains(r.Hint, "get-cluster-info init-config") {
+		t.Errorf("Hint = %q, want the init-config wizard", r.Hint)
+	}
+}
+
+func TestCheckSSHAgent(t *testing.T) {
+	t.Parallel()
+
+	sockDir := t.TempDir()
+	sock := filepath.Join(sockDir, "agent.sock")
+
+	listener, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+
+	t.Cleanup(func() { _ = listener.Close() })
+
+	tests := []struct {
+		name       string
+		authSock   string
+		wantStatus Status
+	}{
+		{name: "SSH_AUTH_SOCK unset - warn", wantStatus: StatusWarn},
+		{name: "stale socket path - warn", authSock: filepath.Join(sockDir, "gone.sock"), wantStatus: StatusWarn},
+		{name: "agent listening - ok", authSock: sock, wantStatus: StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			d := newTestDoctor(t)
+			d.Getenv = func(string) string { return tt.authSock }
+
+			assertResult(t, d.checkSSHAgent(), tt.wantStatus, "")
+		})
+	}
+}
+
+// =============================================================================
+// Run tests
+// =============================================================================
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	d := withTerraform(newTestDoctor(t), "1.9.8")
+	must(t, os.WriteFile(d.CredentialsFile, []byte("access_key: a\nsecret_key: s\n"), 0o600))
+
+	report := d.Run(context.Background())
+
+	names := make([]string, 0, len(report.Checks))
+	for _, c := range report.Checks {
+		names = append(names, c.Name)
+	}
+
+	want := "terraform,backend file,credentials,scaleway credentials,terraform.tfvars,ssh key,ssh-agent,kubectl,helm"
+	if got := strings.Join(names, ","); got != want {
+		t.Errorf("checks = %s, want %s", got, want)
+	}
+
+	if report.Failed() {
+		t.Errorf("report failed: %+v", report.Checks)
+	}
+
+	// Without a backend file, the report fails.
+	must(t, os.Remove(d.CredentialsFile))
+
+	if !d.Run(context.Background()).Failed() {
+		t.Error("report without backend file should fail")
+	}
+}
+
+// =============================================================================
+// Test helpers
+// =============================================================================
+
+func assertResult(t *testing.T, got Result, wantStatus Status, msgContains string) {
+	t.Helper()
+
+	if got.Status != wantStatus {
+		t.Errorf("Status = %s, want %s (message %q)", got.Status, wantStatus, got.Message)
+	}
+
+	if !strings.Contains(got.Message, msgContains) {
+		t.Errorf("Message = %q, want containing %q", got.Message, msgContains)
+	}
+
+	if (got.Status == StatusWarn || got.Status == StatusFail) && got.Hint == "" {
+		t.Errorf("%s: no fix hint for a %s result", got.Name, got.Status)
+	}
+}
+
+func must(t *testing.T, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/k8s-lab/get-cluster-info/clusterinfo/clusterinfotest"
+	"github.com/k8s-lab/get-cluster-info/doctor"
+)
+
+// =============================================================================
+// doctor subcommand tests
+// =============================================================================
+
+func TestRunDoctor(t *testing.T) {
+	// Not parallel - modifies global config
+
+	tests := []struct {
+		name       string
+		terraform  bool
+		wantErr    error
+		wantStatus doctor.Status
+	}{
+		{name: "terraform installed - passes", terraform: true, wantStatus: doctor.StatusOK},
+		{name: "terraform missing - fails", wantErr: doctor.ErrChecksFailed, wantStatus: doctor.StatusFail},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, _ := setupRun(t, clusterinfotest.FixtureDeployed)
+			config.JSONOutput = true
+
+			oldLookPath, oldExec := doctorLookPath, doctorExec
+			t.Cleanup(func() { doctorLookPath, doctorExec = oldLookPath, oldExec })
+
+			doctorLookPath = func(file string) (string, error) {
+				if file == "terraform" && tt.terraform {
+					return "/usr/bin/terraform", nil
+				}
+
+				return "", errors.New("not found")
+			}
+			doctorExec = func(context.Context, string, ...string) ([]byte, error) {
+				return []byte(`{"terraform_version":"1.9.8"}`), nil
+			}
+
+			err := runDoctor(nil, nil)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("runDoctor() error = %v, want %v", err, tt.wantErr)
+			}
+
+			var report doctor.Report
+			if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+				t.Fatalf("invalid JSON report: %v\n%s", err, out.String())
+			}
+
+			if report.Checks[0].Name != "terraform" || report.Checks[0].Status != tt.wantStatus {
+				t.Errorf("terraform check = %+v, want status %s", report.Checks[0], tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-exec/tfexec"
+	"github.com/k8s-lab/get-cluster-info/i18n"
+	"github.com/k8s-lab/get-cluster-info/render"
+	"github.com/k8s-lab/get-cluster-info/tfplan"
+	"github.com/spf13/cobra"
+)
+
+// =============================================================================
+// Drift subcommand
+// =============================================================================
+//
+// Runs a refresh-only plan (`terraform plan -refresh-only`), which compares the
+// real infrastructure with the recorded state without looking at the code, and
+// lists what was changed or deleted outside Terraform (e.g. in the Scaleway
+// console). Exits with code 2 on drift, for scheduled CI jobs.
+
+var driftCmd = &cobra.Command{
+	Use:   "drift",
+	Short: "List the resources changed outside Terraform",
+	Long: `List the resources changed outside Terraform.
+
+Runs a refresh-only plan and shows, for every resource whose real state
+differs from the Terraform state, the changed attributes (sensitive values
+masked). Neither the state nor the infrastructure is modified.
+
+Exit codes: 0 no drift, 2 drift detected, 3 prerequisites missing,
+4 invalid credentials, 5 S3 backend or terraform failure, 9 invalid flag
+or config file, 1 other errors.
+
+Examples:
+  # Human readable report
+  get-cluster-info drift
+
+  # Scheduled CI job (exit code 2 on drift)
+  get-cluster-info drift --json > drift.json`,
+	Args: cobra.NoArgs,
+	RunE: runDrift,
+}
+
+func init() {
+	rootCmd.AddCommand(driftCmd)
+}
+
+func runDrift(_ *cobra.Command, _ []string) error {
+	ctx := context.Background()
+
+	planPath, cleanup, err := planFilePath("")
+	if err != nil {
+		return err
+	}
+
+	defer cleanup()
+
+	src, err := setupEnvironment(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = src.Close() }()
+
+	tf := src.Client()
+
+	logInfo("Refreshing the state against the deployed infrastructure...")
+
+	if _, err := tf.Plan(ctx, tfexec.RefreshOnly(true), tfexec.Out(planPath)); err != nil {
+		return withClass(errBackend, i18n.Errorf("terraform plan -refresh-only failed: %w", err))
+	}
+
+	plan, err := tf.ShowPlanFile(ctx, planPath)
+	if err != nil {
+		return withClass(errBackend, i18n.Errorf("failed to read plan file: %w", err))
+	}
+
+	report := tfplan.Drift(plan)
+
+	if config.JSONOutput {
+		data, _ := json.MarshalIndent(report, "", "  ")
+		fmt.Fprintln(stdout, string(data))
+	} else if err := render.DriftReport(stdout, report); err != nil {
+		return err
+	}
+
+	if n := len(report.Resources); n > 0 {
+		return i18n.Errorf("%w: %d resource(s) changed outside Terraform", tfplan.ErrDrift, n)
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/k8s-lab/get-cluster-info/clusterinfo/clusterinfotest"
+	"github.com/k8s-lab/get-cluster-info/tfplan"
+)
+
+// =============================================================================
+// drift subcommand tests
+// =============================================================================
+
+func TestRunDriftGolden(t *testing.T) {
+	// Not parallel - modifies global config
+
+	tests := []struct {
+		name    string
+		fixture string
+		wantErr error
+	}{
+		{name: "drift_summary", fixture: clusterinfotest.FixturePlanDrift, wantErr: tfplan.ErrDrift},
+		{name: "no_drift_summary"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake, out := setupPlan(t, tt.fixture)
+
+			if err := runDrift(nil, nil); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("runDrift() error = %v, want %v", err, tt.wantErr)
+			}
+
+			assertGolden(t, tt.name, out())
+
+			if want := []string{"init", "plan -refresh-only", "show plan"}; !slices.Equal(fake.Calls(), want) {
+				t.Errorf("calls = %v, want %v", fake.Calls(), want)
+			}
+		})
+	}
+}
+
+func TestRunDriftJSON(t *testing.T) {
+	// Not parallel - modifies global config
+
+	_, out := setupPlan(t, clusterinfotest.FixturePlanDrift)
+	config.JSONOutput = true
+
+	err := runDrift(nil, nil)
+	if code := exitCode(err); code != exitDrift {
+		t.Errorf("exit code = %d, want %d (error %v)", code, exitDrift, err)
+	}
+
+	var report tfplan.DriftReport
+	if err := json.Unmarshal(out(), &report); err != nil {
+		t.Fatalf("invalid JSON report: %v\n%s", err, out())
+	}
+
+	if len(report.Resources) != 2 {
+		t.Errorf("resources = %d, want 2", len(report.Resources))
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "generic error", err: errors.New("boom"), want: exitError},
+		{name: "wrapped drift", err: errors.Join(errors.New("ctx"), tfplan.ErrDrift), want: exitDrift},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := exitCode(tt.err); got != tt.want {
+				t.Errorf("exitCode() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
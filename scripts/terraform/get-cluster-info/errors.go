@@ -0,0 +1,188 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/k8s-lab/get-cluster-info/clusterinfo"
+	"github.com/k8s-lab/get-cluster-info/cost"
+	"github.com/k8s-lab/get-cluster-info/doctor"
+	"github.com/k8s-lab/get-cluster-info/i18n"
+	"github.com/k8s-lab/get-cluster-info/labconfig"
+	"github.com/k8s-lab/get-cluster-info/remote"
+	"github.com/k8s-lab/get-cluster-info/tfplan"
+)
+
+// =============================================================================
+// Error classes
+// =============================================================================
+//
+// Every error returned by a command belongs to a class, which sets the exit
+// code (see EXIT CODES in main.go) and the hint printed under the message.
+// The errors of the packages are classified by their sentinel (classOf); the
+// command tags its own errors with withClass.
+
+// errorClass is a class of errors. Its name is the "class" of the --json error envelope.
+type errorClass struct {
+	name string
+	code int
+	hint string // printed when the message has no hint of its own
+}
+
+func (c *errorClass) Error() string { return c.name }
+
+// Error classes, by exit code.
+var (
+	errGeneric       = &errorClass{name: "error", code: exitError}
+	errDrift         = &errorClass{name: "drift", code: exitDrift}
+	errPrerequisites = &errorClass{
+		name: "prerequisites", code: exitPrerequisites,
+		hint: "Run get-cluster-info doctor to check the prerequisites",
+	}
+	errCredentials = &errorClass{
+		name: "credentials", code: exitCredentials,
+		hint: "Check access_key and secret_key in the credentials file (--credentials)",
+	}
+	errBackend = &errorClass{
+		name: "backend", code: exitBackend,
+		hint: "Check the S3 backend (bucket, endpoint, credentials), or retry with --force-init",
+	}
+	errNotDeployed = &errorClass{
+		name: "not_deployed", code: exitNotDeployed,
+		hint: "Deploy the cluster with terraform apply",
+	}
+	errValidation = &errorClass{
+		name: "validation", code: exitValidation,
+		hint: "Use --lenient to display the available information anyway",
+	}
+	errSSH = &errorClass{
+		name: "ssh", code: exitSSH,
+		hint: "Check that the nodes are running and that the security group allows SSH from this host",
+	}
+	errUsage = &errorClass{
+		name: "usage", code: exitUsage,
+		hint: "Check the flags (get-cluster-info --help) and the config file (--config)",
+	}
+)
+
+// errorClasses lists every class, for the tests and the documentation.
+var errorClasses = []*errorClass{
+	errGeneric, errDrift, errPrerequisites, errCredentials, errBackend, errNotDeployed, errValidation, errSSH,
+	errUsage,
+}
+
+// classifiedErrors maps the sentinel errors of the packages to their class.
+var classifiedErrors = []struct {
+	err   error
+	class *errorClass
+}{
+	{tfplan.ErrDrift, errDrift},
+	{clusterinfo.ErrTerraformNotFound, errPrerequisites},
+	{doctor.ErrChecksFailed, errPrerequisites},
+	{clusterinfo.ErrCredentialsIncomplete, errCredentials},
+	{clusterinfo.ErrNoS3Backend, errBackend},
+	{clusterinfo.ErrNotDeployed, errNotDeployed},
+	{clusterinfo.ErrNoControlPlane, errValidation},
+	{remote.ErrHostKeyChanged, errSSH},
+	{clusterinfo.ErrUnknownMappingKey, errUsage},
+	{clusterinfo.ErrInvalidTTL, errUsage},
+	{i18n.ErrUnknownLanguage, errUsage},
+	{cost.ErrInvalidPrices, errUsage},
+	{labconfig.ErrFileExists, errUsage},
+}
+
+// classified tags an error with its class, keeping its message.
+type classified struct {
+	err   error
+	class *errorClass
+}
+
+func (e *classified) Error() string { return e.err.Error() }
+
+func (e *classified) Unwrap() []error { return []error{e.err, e.class} }
+
+// withClass tags err with class, nil stays nil.
+func withClass(class *errorClass, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &classified{err: err, class: class}
+}
+
+// classOf returns the class of err: the class of the sentinel it wraps, else
+// the class it was tagged with, else errGeneric.
+func classOf(err error) *errorClass {
+	for _, c := range classifiedErrors {
+		if errors.Is(err, c.err) {
+			return c.class
+		}
+	}
+
+	var verr *clusterinfo.ValidationError
+	if errors.As(err, &verr) {
+		return errValidation
+	}
+
+	var dialErr *remote.DialError
+	if errors.As(err, &dialErr) {
+		return errSSH
+	}
+
+	var class *errorClass
+	if errors.As(err, &class) {
+		return class
+	}
+
+	return errGeneric
+}
+
+// exitCode maps an error to the process exit code.
+func exitCode(err error) int {
+	return classOf(err).code
+}
+
+// errorEnvelope is the error printed on stderr with --json.
+type errorEnvelope struct {
+	Code    int    `json:"code"`
+	Class   string `json:"class"`
+	Message string `json:"message"`
+	Hint    string `json:"hint,omitempty"`
+}
+
+// newErrorEnvelope splits the localized message of err from its hint: the
+// text after the first blank line, else the hint of its class.
+func newErrorEnvelope(err error) errorEnvelope {
+	class := classOf(err)
+
+	msg, hint, ok := strings.Cut(localizeError(err), "\n\n")
+	if !ok && class.hint != "" {
+		hint = i18n.T(class.hint)
+	}
+
+	return errorEnvelope{Code: class.code, Class: class.name, Message: msg, Hint: hint}
+}
+
+// printError prints err on stderr with its hint, as a JSON envelope with --json.
+func printError(err error) {
+	e := newErrorEnvelope(err)
+
+	if config.JSONOutput {
+		data, jsonErr := json.Marshal(e)
+		if jsonErr == nil {
+			fmt.Fprintln(stderr, string(data))
+
+			return
+		}
+	}
+
+	if e.Hint == "" {
+		logError("%s", e.Message)
+
+		return
+	}
+
+	logError("%s\n\n%s", e.Message, e.Hint)
+}
@@ -0,0 +1,339 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/k8s-lab/get-cluster-info/clusterinfo"
+	"github.com/k8s-lab/get-cluster-info/clusterinfo/clusterinfotest"
+	"github.com/k8s-lab/get-cluster-info/cost"
+	"github.com/k8s-lab/get-cluster-info/doctor"
+	"github.com/k8s-lab/get-cluster-info/i18n"
+	"github.com/k8s-lab/get-cluster-info/remote"
+	"github.com/k8s-lab/get-cluster-info/tfplan"
+)
+
+// =============================================================================
+// Error class tests
+// =============================================================================
+
+func TestClassOf(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		err  error
+		want *errorClass
+	}{
+		{name: "generic error", err: errors.New("boom"), want: errGeneric},
+		{name: "drift", err: fmt.Errorf("plan: %w", tfplan.ErrDrift), want: errDrift},
+		{name: "terraform missing", err: clusterinfo.ErrTerraformNotFound, want: errPrerequisites},
+		{name: "doctor", err: doctor.ErrChecksFailed, want: errPrerequisites},
+		{name: "incomplete credentials", err: clusterinfo.ErrCredentialsIncomplete, want: errCredentials},
+		{name: "not deployed", err: clusterinfo.ErrNotDeployed, want: errNotDeployed},
+		{
+			name: "validation",
+			err:  fmt.Errorf("%w\n\nhint", &clusterinfo.ValidationError{Problems: []error{errors.New("bad")}}),
+			want: errValidation,
+		},
+		{name: "host key changed", err: remote.ErrHostKeyChanged, want: errSSH},
+		{name: "unreachable node", err: &remote.DialError{Addr: "x:22", Err: errors.New("refused")}, want: errSSH},
+		{name: "no S3 backend", err: clusterinfo.ErrNoS3Backend, want: errBackend},
+		{name: "invalid TTL", err: fmt.Errorf("%w: %q", clusterinfo.ErrInvalidTTL, "8x"), want: errUsage},
+		{name: "price table not found", err: missingPrices(), want: errUsage},
+		{name: "tagged", err: withClass(errBackend, errors.New("init failed")), want: errBackend},
+		{
+			name: "sentinel wins over the tag",
+			err:  withClass(errBackend, clusterinfo.ErrTerraformNotFound),
+			want: errPrerequisites,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := classOf(tt.err); got != tt.want {
+				t.Errorf("classOf() = %s, want %s", got, tt.want)
+			}
+
+			if got := exitCode(tt.err); got != tt.want.code {
+				t.Errorf("exitCode() = %d, want %d", got, tt.want.code)
+			}
+		})
+	}
+}
+
+// missingPrices returns the error of a --prices path that does not exist.
+func missingPrices() error {
+	_, err := cost.LoadPrices(filepath.Join(os.TempDir(), "no-such-prices.yaml"))
+
+	return err
+}
+
+func TestErrorClasses(t *testing.T) {
+	t.Parallel()
+
+	codes := map[int]string{}
+
+	for _, c := range errorClasses {
+		if other, ok := codes[c.code]; ok {
+			t.Errorf("classes %s and %s share the exit code %d", c, other, c.code)
+		}
+
+		codes[c.code] = c.name
+	}
+
+	if err := withClass(errBackend, nil); err != nil {
+		t.Errorf("withClass(nil) = %v, want nil", err)
+	}
+}
+
+func TestErrorClassHints(t *testing.T) {
+	t.Parallel()
+
+	for _, l := range i18n.Languages() {
+		for _, c := range errorClasses {
+			if c.hint != "" && !i18n.Has(l, c.hint) {
+				t.Errorf("%q catalog: missing the hint of %s: %q", l, c, c.hint)
+			}
+		}
+	}
+}
+
+// runError runs the root command on fixture and returns its error.
+// setup adjusts the config and the fake (may be nil).
+func runError(t *testing.T, fixture string, setup func(*clusterinfotest.FakeTerraform)) error {
+	t.Helper()
+
+	_, fake := setupRun(t, fixture)
+	config.NoSaveKey = true
+
+	if setup != nil {
+		setup(fake)
+	}
+
+	err := run(nil, nil)
+	if err == nil {
+		t.Fatal("run() succeeded, want an error")
+	}
+
+	return err
+}
+
+func TestRunExitCodes(t *testing.T) {
+	// Not parallel - modifies global config
+
+	tests := []struct {
+		name    string
+		fixture string
+		setup   func(*clusterinfotest.FakeTerraform)
+		want    int
+	}{
+		{name: "not deployed", fixture: clusterinfotest.FixtureNotDeployed, want: exitNotDeployed},
+		{name: "half applied", fixture: clusterinfotest.FixtureHalfApplied, want: exitValidation},
+		{
+			name:    "credentials missing",
+			fixture: clusterinfotest.FixtureDeployed,
+			setup:   func(*clusterinfotest.FakeTerraform) { config.CredentialsFile += ".missing" },
+			want:    exitCredentials,
+		},
+		{
+			name:    "credentials incomplete",
+			fixture: clusterinfotest.FixtureDeployed,
+			setup: func(*clusterinfotest.FakeTerraform) {
+				must(t, os.WriteFile(config.CredentialsFile, []byte("access_key: AKIATEST\n"), 0o600))
+			},
+			want: exitCredentials,
+		},
+		{
+			name:    "init failed",
+			fixture: clusterinfotest.FixtureDeployed,
+			setup:   func(f *clusterinfotest.FakeTerraform) { f.InitErr = errors.New("backend unreachable") },
+			want:    exitBackend,
+		},
+		{
+			name:    "output failed",
+			fixture: clusterinfotest.FixtureDeployed,
+			setup:   func(f *clusterinfotest.FakeTerraform) { f.OutputErr = errors.New("access denied") },
+			want:    exitBackend,
+		},
+		{
+			name:    "terraform directory missing",
+			fixture: clusterinfotest.FixtureDeployed,
+			setup:   func(*clusterinfotest.FakeTerraform) { config.TerraformDir += ".missing" },
+			want:    exitPrerequisites,
+		},
+		{
+			name:    "malformed config file",
+			fixture: clusterinfotest.FixtureDeployed,
+			setup: func(*clusterinfotest.FakeTerraform) {
+				config.ConfigFile = filepath.Join(config.TerraformDir, clusterinfo.ConfigFileName)
+				must(t, os.WriteFile(config.ConfigFile, []byte("output_map: [\n"), 0o600))
+			},
+			want: exitUsage,
+		},
+		{
+			name:    "unknown --output-map key",
+			fixture: clusterinfotest.FixtureDeployed,
+			setup:   func(*clusterinfotest.FakeTerraform) { config.OutputMap = map[string]string{"nope": "x"} },
+			want:    exitUsage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := runError(t, tt.fixture, tt.setup)
+			if got := exitCode(err); got != tt.want {
+				t.Errorf("exitCode(%v) = %d, want %d", err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSubcommandBackendErrors(t *testing.T) {
+	// Not parallel - modifies global config
+
+	errBoom := errors.New("backend unreachable")
+	expired := clusterinfo.NewLifetime(testNow.Add(-2*time.Hour), time.Hour, "alice", nil)
+
+	tests := []struct {
+		name  string
+		setup func(*clusterinfotest.FakeTerraform, *clusterinfotest.MemoryLifetimeStore)
+		run   func() error
+	}{
+		{
+			name:  "outputs",
+			setup: func(f *clusterinfotest.FakeTerraform, _ *clusterinfotest.MemoryLifetimeStore) { f.OutputErr = errBoom },
+			run:   func() error { return runOutputs(nil, nil) },
+		},
+		{
+			name:  "audit",
+			setup: func(f *clusterinfotest.FakeTerraform, _ *clusterinfotest.MemoryLifetimeStore) { f.ShowErr = errBoom },
+			run:   func() error { return runAudit(nil, nil) },
+		},
+		{
+			name:  "cost",
+			setup: func(f *clusterinfotest.FakeTerraform, _ *clusterinfotest.MemoryLifetimeStore) { f.ShowErr = errBoom },
+			run:   func() error { return runCost(nil, nil) },
+		},
+		{
+			name:  "plan",
+			setup: func(f *clusterinfotest.FakeTerraform, _ *clusterinfotest.MemoryLifetimeStore) { f.PlanErr = errBoom },
+			run:   func() error { return runPlan(nil, nil) },
+		},
+		{
+			name:  "drift",
+			setup: func(f *clusterinfotest.FakeTerraform, _ *clusterinfotest.MemoryLifetimeStore) { f.PlanErr = errBoom },
+			run:   func() error { return runDrift(nil, nil) },
+		},
+		{
+			name:  "ttl set",
+			setup: func(_ *clusterinfotest.FakeTerraform, s *clusterinfotest.MemoryLifetimeStore) { s.Err = errBoom },
+			run:   func() error { return runTTLSet(nil, []string{"8h"}) },
+		},
+		{
+			name: "reap",
+			setup: func(f *clusterinfotest.FakeTerraform, s *clusterinfotest.MemoryLifetimeStore) {
+				s.Value = &expired
+				f.DestroyErr = errBoom
+			},
+			run: func() error { return runReap(nil, nil) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, fake := setupRun(t, clusterinfotest.FixtureDeployed)
+
+			store := &clusterinfotest.MemoryLifetimeStore{}
+			lifetimeStore = store
+
+			tt.setup(fake, store)
+
+			err := tt.run()
+			if !errors.Is(err, errBoom) || exitCode(err) != exitBackend {
+				t.Errorf("error = %v (exit %d), want %v with exit %d", err, exitCode(err), errBoom, exitBackend)
+			}
+		})
+	}
+}
+
+func TestFlagErrorExitCode(t *testing.T) {
+	// Not parallel - runs the root command
+
+	rootCmd.SetArgs([]string{"--no-such-flag"})
+	t.Cleanup(func() { rootCmd.SetArgs(nil) })
+
+	if err := rootCmd.Execute(); exitCode(err) != exitUsage {
+		t.Errorf("Execute() error = %v (exit %d), want exit %d", err, exitCode(err), exitUsage)
+	}
+}
+
+func TestPrintErrorJSON(t *testing.T) {
+	// Not parallel - modifies global config
+
+	err := runError(t, clusterinfotest.FixtureNotDeployed, nil)
+
+	errOut := &bytes.Buffer{}
+	stderr = errOut
+	config.JSONOutput = true
+
+	printError(err)
+
+	var got errorEnvelope
+	if err := json.Unmarshal(errOut.Bytes(), &got); err != nil {
+		t.Fatalf("stderr is not a JSON envelope: %v\n%s", err, errOut)
+	}
+
+	want := errorEnvelope{
+		Code:    exitNotDeployed,
+		Class:   "not_deployed",
+		Message: clusterinfo.ErrNotDeployed.Error(),
+		Hint:    errNotDeployed.hint,
+	}
+	if got != want {
+		t.Errorf("envelope = %+v, want %+v", got, want)
+	}
+}
+
+func TestPrintErrorHint(t *testing.T) {
+	// Not parallel - modifies global config
+
+	tests := []struct {
+		name     string
+		err      error
+		wantHint string
+	}{
+		{name: "class hint", err: clusterinfo.ErrNotDeployed, wantHint: errNotDeployed.hint},
+		{name: "own hint", err: errors.New("broken\n\nDo this"), wantHint: "Do this"},
+		{name: "no hint", err: errors.New("broken")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupRun(t, clusterinfotest.FixtureDeployed)
+
+			errOut := &bytes.Buffer{}
+			stderr = errOut
+
+			printError(tt.err)
+
+			_, hint, _ := strings.Cut(strings.TrimSpace(errOut.String()), "\n\n")
+			if hint != tt.wantHint {
+				t.Errorf("hint = %q, want %q\n%s", hint, tt.wantHint, errOut)
+			}
+
+			if e := newErrorEnvelope(tt.err); e.Hint != tt.wantHint {
+				t.Errorf("envelope hint = %q, want %q", e.Hint, tt.wantHint)
+			}
+		})
+	}
+}
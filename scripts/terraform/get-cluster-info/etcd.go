@@ -0,0 +1,239 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/k8s-lab/get-cluster-info/bootstrap"
+	"github.com/k8s-lab/get-cluster-info/clusterinfo"
+	"github.com/k8s-lab/get-cluster-info/etcd"
+	"github.com/k8s-lab/get-cluster-info/i18n"
+	"github.com/k8s-lab/get-cluster-info/render"
+	"github.com/spf13/cobra"
+)
+
+// =============================================================================
+// etcd subcommands
+// =============================================================================
+//
+// Snapshots the etcd of the control plane (see the etcd package) into a dated
+// directory, locally or in the bucket of the Terraform state, and restores
+// them. Every transfer is checked against the snapshot sha256.
+
+var (
+	etcdBackupDir string
+	etcdBackupS3  bool
+	etcdTimeout   time.Duration
+	// etcdPollInterval is the delay between two API server checks after a restore.
+	etcdPollInterval = 5 * time.Second
+)
+
+const mebibyte = 1 << 20
+
+var etcdCmd = &cobra.Command{
+	Use:   "etcd",
+	Short: "Back up and restore the etcd of the cluster",
+	Args:  cobra.NoArgs,
+}
+
+var etcdBackupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Snapshot etcd into a dated directory",
+	Long: `Snapshot etcd into a dated directory.
+
+Runs "etcdctl snapshot save" in the etcd container of the control plane, with
+the kubeadm etcd certificates, downloads the snapshot over SFTP and checks its
+sha256. The snapshot and its manifest (snapshot.json: revision, keys, sha256)
+are written to <dir>/<date>/, or with --s3 to the bucket of the Terraform
+state, under <key>.etcd/<date>/.
+
+Examples:
+  # Local backup in ./etcd-backups/<date>/
+  get-cluster-info etcd backup
+
+  # Backup in the state bucket, with the backend credentials
+  get-cluster-info etcd backup --s3`,
+	Args: cobra.NoArgs,
+	RunE: runEtcdBackup,
+}
+
+var etcdRestoreCmd = &cobra.Command{
+	Use:   "restore <path>",
+	Short: "Restore etcd from a snapshot",
+	Long: `Restore etcd from a snapshot.
+
+<path> is a backup directory, a snapshot file, or s3://<bucket>/<key>/<date>.
+The snapshot is checked (manifest sha256 and etcd trailer), uploaded over SFTP
+and checked again, restored with etcdutl, then swapped in while the etcd static
+pod is stopped. The previous data is kept in /var/lib/etcd/member.bak-<date>.
+
+Examples:
+  get-cluster-info etcd restore etcd-backups/2025-03-01T120000Z
+  get-cluster-info etcd restore s3://k8s-lab-state/k8s-lab.tfstate.etcd/2025-03-01T120000Z`,
+	Args: cobra.ExactArgs(1),
+	RunE: runEtcdRestore,
+}
+
+func init() {
+	etcdBackupCmd.Flags().StringVar(&etcdBackupDir, "dir", "etcd-backups", "Directory of the local backups")
+	etcdBackupCmd.Flags().BoolVar(&etcdBackupS3, "s3", false, "Store the backup in the S3 bucket of the state")
+	etcdRestoreCmd.Flags().DurationVar(&etcdTimeout, "timeout", 5*time.Minute,
+		"How long to wait for the API server after the restore")
+
+	etcdCmd.AddCommand(etcdBackupCmd, etcdRestoreCmd)
+	rootCmd.AddCommand(etcdCmd)
+}
+
+func runEtcdBackup(_ *cobra.Command, _ []string) error {
+	ctx := context.Background()
+
+	info, dialer, closeSrc, err := openCluster(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer closeSrc()
+
+	var store etcd.Store = etcd.DirStore{Dir: etcdBackupDir}
+	if etcdBackupS3 {
+		if store, err = openEtcdS3Store(ctx); err != nil {
+			return err
+		}
+	}
+
+	cp, err := bootstrap.ControlPlane(info)
+	if err != nil {
+		return err
+	}
+
+	client, err := dialer.Dial(ctx, cp.PublicIP)
+	if err != nil {
+		return i18n.Errorf("%s: %w", cp.Name, err)
+	}
+
+	defer func() { _ = client.Close() }()
+
+	logInfo("Saving an etcd snapshot on %s...", cp.Name)
+
+	snapshot, err := etcd.Backup(ctx, client, cp, now())
+	if err != nil {
+		return err
+	}
+
+	location, err := store.Save(ctx, snapshot)
+	if err != nil && etcdBackupS3 {
+		return withClass(errBackend, err)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if config.JSONOutput {
+		data, _ := json.MarshalIndent(struct {
+			Location string        `json:"location"`
+			Manifest etcd.Manifest `json:"manifest"`
+		}{location, snapshot.Manifest}, "", "  ")
+		fmt.Fprintln(stdout, string(data))
+
+		return nil
+	}
+
+	m := snapshot.Manifest
+	logSuccess("Snapshot saved to %s", location)
+	fmt.Fprintf(stdout, "  revision %d, %d keys, %.1f MiB, sha256 %s\n",
+		m.Revision, m.TotalKeys, float64(m.Size)/mebibyte, m.SHA256)
+
+	return nil
+}
+
+func runEtcdRestore(_ *cobra.Command, args []string) error {
+	location := args[0]
+	ctx := context.Background()
+
+	var store etcd.Store = etcd.DirStore{}
+
+	if strings.HasPrefix(location, "s3://") {
+		var err error
+		if store, err = openEtcdS3Store(ctx); err != nil {
+			return err
+		}
+	}
+
+	// Checked before touching the cluster.
+	snapshot, err := store.Load(ctx, location)
+	if err != nil {
+		return err
+	}
+
+	if snapshot.Manifest.SHA256 == "" {
+		logWarning("No %s next to the snapshot: only its etcd checksum is verified", etcd.ManifestFile)
+	}
+
+	info, dialer, closeSrc, err := openCluster(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer closeSrc()
+
+	cp, err := bootstrap.ControlPlane(info)
+	if err != nil {
+		return err
+	}
+
+	client, err := dialer.Dial(ctx, cp.PublicIP)
+	if err != nil {
+		return i18n.Errorf("%s: %w", cp.Name, err)
+	}
+
+	defer func() { _ = client.Close() }()
+
+	logInfo("Restoring etcd on %s from %s...", cp.Name, location)
+
+	err = etcd.Restore(ctx, client, cp, snapshot, now(), func(step string) { logInfo("%s...", step) })
+	if err != nil {
+		return err
+	}
+
+	logInfo("Waiting for the API server (timeout %s)...", etcdTimeout)
+
+	waitCtx, cancel := context.WithTimeout(ctx, etcdTimeout)
+	defer cancel()
+
+	if err := etcd.WaitAPIServer(waitCtx, client, etcdPollInterval); err != nil {
+		return i18n.Errorf("%w\n\nCheck the etcd container on the control plane: %s", err,
+			render.CmdStyle.Render("sudo crictl ps -a --name etcd"))
+	}
+
+	logSuccess("etcd restored - the previous data is kept in /var/lib/etcd/member.bak-*")
+
+	return nil
+}
+
+// openEtcdS3Store returns the snapshot store of the state bucket.
+func openEtcdS3Store(ctx context.Context) (etcd.Store, error) {
+	if err := resolveDefaults(); err != nil {
+		return nil, err
+	}
+
+	backend, err := clusterinfo.LoadS3Backend(config.TerraformDir)
+	if err != nil {
+		return nil, err
+	}
+
+	creds, err := clusterinfo.FileCredentials{Path: config.CredentialsFile}.Credentials(ctx)
+	if err != nil {
+		return nil, withClass(errCredentials, err)
+	}
+
+	client, err := clusterinfo.NewS3Client(backend, *creds)
+	if err != nil {
+		return nil, withClass(errBackend, err)
+	}
+
+	return etcd.S3Store{Client: client, Bucket: backend.Bucket, Prefix: backend.Key + etcd.S3KeySuffix}, nil
+}
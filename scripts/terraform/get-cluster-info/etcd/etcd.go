@@ -0,0 +1,311 @@
+// Package etcd backs up and restores the etcd of a K8s-Lab cluster from the
+// control plane over SSH.
+//
+// etcdctl and etcdutl are not installed on the nodes: they run in the etcd
+// container of the kubeadm static pod (crictl exec), with the kubeadm etcd
+// certificates, on files of the /var/lib/etcd host path. Snapshots are
+// transferred with SFTP and stored in a dated directory, locally or in the
+// bucket of the Terraform state.
+//
+// A snapshot is checked at every transfer: the sha256 of the file is compared
+// with the one computed on the node and recorded in its manifest, and the
+// sha256 trailer etcd appends to the snapshot database is verified.
+package etcd
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/k8s-lab/get-cluster-info/clusterinfo"
+	"github.com/k8s-lab/get-cluster-info/remote"
+)
+
+// =============================================================================
+// Types
+// =============================================================================
+
+// Files of a snapshot directory.
+const (
+	SnapshotFile = "snapshot.db"
+	ManifestFile = "snapshot.json"
+)
+
+const (
+	// certDir holds the kubeadm etcd certificates.
+	certDir = "/etc/kubernetes/pki/etcd"
+	// dataDir is the data directory of etcd, mounted in its container.
+	dataDir = "/var/lib/etcd"
+	// manifest is the etcd static pod; parkedManifest stops etcd while restoring.
+	manifest       = "/etc/kubernetes/manifests/etcd.yaml"
+	parkedManifest = "/etc/kubernetes/etcd.yaml.k8s-lab-restore"
+	// snapshotPath and restoreDir are the work files, in dataDir (visible to the container).
+	snapshotPath = dataDir + "/k8s-lab-snapshot.db"
+	restoreDir   = dataDir + "/k8s-lab-restore"
+	// uploadPath receives the snapshot to restore (writable by the SSH user).
+	uploadPath = "/tmp/k8s-lab-etcd-restore.db"
+
+	// trailerAlign is the alignment of the database in a snapshot: a database
+	// followed by its sha256 has a size of trailerAlign*n + sha256.Size.
+	trailerAlign = 512
+	// stopAttempts bounds the wait for etcd to stop (2s apart).
+	stopAttempts = 60
+	// backupTimeFormat names the backup directories and the old data directories.
+	backupTimeFormat = "2006-01-02T150405Z"
+)
+
+var (
+	// ErrNoEtcd is returned when the etcd container is not running on the control plane.
+	ErrNoEtcd = errors.New("etcd container not running on the control plane")
+	// ErrCorruptSnapshot is returned when the sha256 trailer of a snapshot does not match.
+	ErrCorruptSnapshot = errors.New("corrupt etcd snapshot")
+	// ErrChecksumMismatch is returned when a snapshot differs from its recorded sha256.
+	ErrChecksumMismatch = errors.New("snapshot checksum mismatch")
+	// ErrAPIServer is returned when the API server is not ready after a restore.
+	ErrAPIServer = errors.New("API server not ready")
+)
+
+// Manifest describes a snapshot (snapshot.json).
+type Manifest struct {
+	CreatedAt    time.Time `json:"created_at"`    //nolint:tagliatelle
+	ControlPlane string    `json:"control_plane"` //nolint:tagliatelle
+	Revision     int64     `json:"revision"`
+	TotalKeys    int64     `json:"total_keys"` //nolint:tagliatelle
+	Size         int64     `json:"size"`
+	SHA256       string    `json:"sha256"`
+}
+
+// Snapshot is an etcd snapshot and its manifest.
+type Snapshot struct {
+	Manifest Manifest
+	Data     []byte
+}
+
+// Verify checks the snapshot against its manifest (when it records a sha256)
+// and the sha256 trailer of the database.
+func (s Snapshot) Verify() error {
+	if s.Manifest.SHA256 != "" {
+		if sum := checksum(s.Data); sum != s.Manifest.SHA256 {
+			return fmt.Errorf("%w: sha256 %s, manifest %s", ErrChecksumMismatch, sum, s.Manifest.SHA256)
+		}
+	}
+
+	return VerifyTrailer(s.Data)
+}
+
+// VerifyTrailer checks the sha256 etcd appends to the database of a snapshot
+// (what etcdutl snapshot restore checks).
+func VerifyTrailer(data []byte) error {
+	if len(data) < sha256.Size || len(data)%trailerAlign != sha256.Size {
+		return fmt.Errorf("%w: no sha256 trailer (%d bytes)", ErrCorruptSnapshot, len(data))
+	}
+
+	db, trailer := data[:len(data)-sha256.Size], data[len(data)-sha256.Size:]
+	if sum := sha256.Sum256(db); !bytes.Equal(sum[:], trailer) {
+		return fmt.Errorf("%w: sha256 trailer does not match the database", ErrCorruptSnapshot)
+	}
+
+	return nil
+}
+
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:])
+}
+
+// =============================================================================
+// Backup
+// =============================================================================
+
+// etcdctl runs etcdctl in the etcd container with the kubeadm certificates.
+func etcdctl(container, args string) string {
+	return "sudo crictl exec " + container + " etcdctl --endpoints=https://127.0.0.1:2379" +
+		" --cacert=" + certDir + "/ca.crt" +
+		" --cert=" + certDir + "/healthcheck-client.crt" +
+		" --key=" + certDir + "/healthcheck-client.key " + args
+}
+
+// etcdutl runs etcdutl in the etcd container.
+func etcdutl(container, args string) string {
+	return "sudo crictl exec " + container + " etcdutl " + args
+}
+
+// container returns the ID of the running etcd container.
+func container(ctx context.Context, controlPlane remote.Client) (string, error) {
+	out, err := controlPlane.Run(ctx, "sudo crictl ps --name '^etcd$' --state running -q")
+	if err != nil {
+		return "", fmt.Errorf("failed to find the etcd container: %w", err)
+	}
+
+	id, _, _ := strings.Cut(strings.TrimSpace(string(out)), "\n")
+	if id == "" {
+		return "", ErrNoEtcd
+	}
+
+	return id, nil
+}
+
+// Backup saves a snapshot on the control plane, downloads it and checks it.
+func Backup(ctx context.Context, controlPlane remote.Client, cp clusterinfo.Node, now time.Time) (Snapshot, error) {
+	id, err := container(ctx, controlPlane)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	if _, err := controlPlane.Run(ctx, etcdctl(id, "snapshot save "+snapshotPath)); err != nil {
+		return Snapshot{}, fmt.Errorf("etcdctl snapshot save failed: %w", err)
+	}
+
+	out, err := controlPlane.Run(ctx, etcdutl(id, "snapshot status "+snapshotPath+" -w json"))
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("etcdutl snapshot status failed: %w", err)
+	}
+
+	var status struct {
+		Revision  int64 `json:"revision"`
+		TotalKeys int64 `json:"totalKey"`
+	}
+
+	if err := json.Unmarshal(out, &status); err != nil {
+		return Snapshot{}, fmt.Errorf("failed to parse etcdutl snapshot status: %w", err)
+	}
+
+	// Hand the snapshot over to the SSH user, for SFTP.
+	out, err = controlPlane.Run(ctx, "bash -euo pipefail -c "+remote.Quote(`tmp=$(mktemp)
+sudo mv `+snapshotPath+` "$tmp"
+sudo chown "$(id -u):$(id -g)" "$tmp"
+sha256sum "$tmp"`))
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("failed to prepare the snapshot download: %w", err)
+	}
+
+	fields := strings.Fields(string(out))
+	if len(fields) != 2 { //nolint:mnd // sha256sum prints "<sum>  <path>"
+		return Snapshot{}, fmt.Errorf("unexpected sha256sum output %q", out)
+	}
+
+	remoteSum, path := fields[0], fields[1]
+
+	defer func() { _, _ = controlPlane.Run(context.WithoutCancel(ctx), "rm -f "+remote.Quote(path)) }()
+
+	var buf bytes.Buffer
+	if err := controlPlane.Download(ctx, path, &buf); err != nil {
+		return Snapshot{}, err
+	}
+
+	s := Snapshot{
+		Manifest: Manifest{
+			CreatedAt:    now.UTC(),
+			ControlPlane: cp.Name,
+			Revision:     status.Revision,
+			TotalKeys:    status.TotalKeys,
+			Size:         int64(buf.Len()),
+			SHA256:       remoteSum,
+		},
+		Data: buf.Bytes(),
+	}
+
+	if err := s.Verify(); err != nil {
+		return Snapshot{}, fmt.Errorf("downloaded snapshot: %w", err)
+	}
+
+	return s, nil
+}
+
+// =============================================================================
+// Restore
+// =============================================================================
+
+// Restore replaces the etcd data of the control plane with the snapshot:
+// the snapshot is uploaded and checked, restored next to the data directory,
+// then swapped in while the etcd static pod is stopped. The previous data is
+// kept as member.bak-<date> in /var/lib/etcd. progress (optional) receives the
+// steps.
+func Restore(ctx context.Context, controlPlane remote.Client, cp clusterinfo.Node, s Snapshot, now time.Time,
+	progress func(step string),
+) error {
+	if progress == nil {
+		progress = func(string) {}
+	}
+
+	if err := s.Verify(); err != nil {
+		return err
+	}
+
+	id, err := container(ctx, controlPlane)
+	if err != nil {
+		return err
+	}
+
+	progress("uploading the snapshot")
+
+	if err := controlPlane.Upload(ctx, bytes.NewReader(s.Data), uploadPath); err != nil {
+		return err
+	}
+
+	out, err := controlPlane.Run(ctx, "sha256sum "+uploadPath)
+	if err != nil {
+		return fmt.Errorf("failed to check the uploaded snapshot: %w", err)
+	}
+
+	if sum, _, _ := strings.Cut(string(out), " "); sum != checksum(s.Data) {
+		return fmt.Errorf("%w: uploaded snapshot sha256 %s", ErrChecksumMismatch, sum)
+	}
+
+	progress("restoring the snapshot with etcdutl")
+
+	peerURL := "https://" + cp.PrivateIP + ":2380"
+
+	_, err = controlPlane.Run(ctx, "bash -euo pipefail -c "+remote.Quote(`name=$(hostname)
+sudo rm -rf `+restoreDir+`
+sudo mv `+uploadPath+` `+snapshotPath+`
+`+etcdutl(id, "snapshot restore "+snapshotPath+" --data-dir "+restoreDir+
+		` --name "$name" --initial-cluster "$name=`+peerURL+`" --initial-advertise-peer-urls `+peerURL)+`
+sudo rm -f `+snapshotPath))
+	if err != nil {
+		return fmt.Errorf("etcdutl snapshot restore failed: %w", err)
+	}
+
+	progress("swapping the etcd data (etcd stopped)")
+
+	// The static pod manifest is put back even if the swap fails.
+	_, err = controlPlane.Run(ctx, "bash -euo pipefail -c "+remote.Quote(fmt.Sprintf(`
+trap 'if [ -f %[2]s ]; then sudo mv %[2]s %[1]s; fi' EXIT
+sudo mv %[1]s %[2]s
+for _ in $(seq %[3]d); do
+  [ -z "$(sudo crictl ps --name '^etcd$' -q)" ] && break
+  sleep 2
+done
+[ -z "$(sudo crictl ps --name '^etcd$' -q)" ]
+sudo mv %[4]s/member %[4]s/member.bak-%[5]s
+sudo mv %[6]s/member %[4]s/member
+sudo rm -rf %[6]s`, manifest, parkedManifest, stopAttempts, dataDir, now.UTC().Format(backupTimeFormat), restoreDir)))
+	if err != nil {
+		return fmt.Errorf("failed to swap the etcd data: %w", err)
+	}
+
+	return nil
+}
+
+// WaitAPIServer polls the readiness of the API server every interval, until
+// it is ready or ctx is done.
+func WaitAPIServer(ctx context.Context, controlPlane remote.Client, interval time.Duration) error {
+	for {
+		if _, err := controlPlane.Run(ctx, "kubectl get --raw=/readyz"); err == nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %w", ErrAPIServer, ctx.Err())
+		case <-time.After(interval):
+		}
+	}
+}
@@ -0,0 +1,267 @@
+package etcd
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/k8s-lab/get-cluster-info/clusterinfo"
+	"github.com/k8s-lab/get-cluster-info/remote"
+	"github.com/k8s-lab/get-cluster-info/remote/remotetest"
+)
+
+// =============================================================================
+// etcd tests
+// =============================================================================
+
+const testHost = "203.0.113.10"
+
+var (
+	testNow = time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	testCP  = clusterinfo.Node{Name: "control-plane", Role: clusterinfo.RoleControlPlane, NodeInfo: clusterinfo.NodeInfo{
+		PublicIP: testHost, PrivateIP: "10.0.0.10",
+	}}
+)
+
+func must(t *testing.T, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// testSnapshot returns a snapshot database of 4 KiB followed by its sha256, like etcdctl writes.
+func testSnapshot() []byte {
+	db := []byte(strings.Repeat("etcd", 1024))
+	sum := sha256.Sum256(db)
+
+	return append(db, sum[:]...)
+}
+
+// controlPlane simulates the commands run by Backup and Restore on the control plane.
+type controlPlane struct {
+	dialer   *remotetest.FakeDialer
+	snapshot []byte
+	noEtcd   bool
+}
+
+func newControlPlane() *controlPlane {
+	cp := &controlPlane{snapshot: testSnapshot()}
+	cp.dialer = &remotetest.FakeDialer{Handler: cp.handler}
+
+	return cp
+}
+
+func (cp *controlPlane) handler(host, cmd string) ([]byte, error) {
+	switch {
+	case strings.HasPrefix(cmd, "sudo crictl ps"):
+		if cp.noEtcd {
+			return nil, nil
+		}
+
+		return []byte("3f2a1b\n"), nil
+	case strings.Contains(cmd, "snapshot status"):
+		return []byte(`{"hash":3043207826,"revision":4242,"totalKey":1234,"totalSize":4128}`), nil
+	case strings.Contains(cmd, "mktemp"):
+		cp.dialer.WriteFile(host, "/tmp/tmp.Xq3", cp.snapshot)
+
+		return []byte(checksum(testSnapshot()) + "  /tmp/tmp.Xq3\n"), nil
+	case strings.HasPrefix(cmd, "sha256sum "):
+		data, _ := cp.dialer.ReadFile(host, strings.TrimPrefix(cmd, "sha256sum "))
+
+		return []byte(checksum(data) + "  " + uploadPath + "\n"), nil
+	}
+
+	return nil, nil
+}
+
+func (cp *controlPlane) client(t *testing.T) remote.Client {
+	t.Helper()
+
+	c, err := cp.dialer.Dial(context.Background(), testHost)
+	must(t, err)
+
+	return c
+}
+
+func TestVerifyTrailer(t *testing.T) {
+	t.Parallel()
+
+	corrupted := testSnapshot()
+	corrupted[100] ^= 0xff
+
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr error
+	}{
+		{name: "valid", data: testSnapshot()},
+		{name: "corrupted", data: corrupted, wantErr: ErrCorruptSnapshot},
+		{name: "truncated", data: testSnapshot()[:4000], wantErr: ErrCorruptSnapshot},
+		{name: "empty", data: nil, wantErr: ErrCorruptSnapshot},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if err := VerifyTrailer(tt.data); !errors.Is(err, tt.wantErr) {
+				t.Errorf("VerifyTrailer() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSnapshotVerify(t *testing.T) {
+	t.Parallel()
+
+	s := Snapshot{Manifest: Manifest{SHA256: checksum(testSnapshot())}, Data: testSnapshot()}
+	must(t, s.Verify())
+
+	s.Manifest.SHA256 = checksum([]byte("other"))
+	if err := s.Verify(); !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("Verify() error = %v, want %v", err, ErrChecksumMismatch)
+	}
+}
+
+func TestBackup(t *testing.T) {
+	t.Parallel()
+
+	t.Run("snapshot", func(t *testing.T) {
+		t.Parallel()
+
+		cp := newControlPlane()
+
+		s, err := Backup(context.Background(), cp.client(t), testCP, testNow)
+		must(t, err)
+
+		want := Manifest{
+			CreatedAt: testNow, ControlPlane: "control-plane", Revision: 4242, TotalKeys: 1234,
+			Size: int64(len(testSnapshot())), SHA256: checksum(testSnapshot()),
+		}
+		if s.Manifest != want {
+			t.Errorf("manifest = %+v, want %+v", s.Manifest, want)
+		}
+
+		cmds := cp.dialer.Commands(testHost)
+		if !slices.ContainsFunc(cmds, func(c string) bool {
+			return strings.HasPrefix(c, "sudo crictl exec 3f2a1b etcdctl ") &&
+				strings.Contains(c, "--cert="+certDir+"/healthcheck-client.crt") &&
+				strings.HasSuffix(c, "snapshot save "+snapshotPath)
+		}) {
+			t.Errorf("no etcdctl snapshot save with the kubeadm certs: %q", cmds)
+		}
+
+		if last := cmds[len(cmds)-1]; last != "rm -f '/tmp/tmp.Xq3'" {
+			t.Errorf("downloaded snapshot not removed from the node, last command %q", last)
+		}
+	})
+
+	t.Run("corrupted download", func(t *testing.T) {
+		t.Parallel()
+
+		cp := newControlPlane()
+		cp.snapshot = append(testSnapshot()[:100], testSnapshot()[101:]...)
+
+		if _, err := Backup(context.Background(), cp.client(t), testCP, testNow); !errors.Is(err, ErrChecksumMismatch) {
+			t.Errorf("Backup() error = %v, want %v", err, ErrChecksumMismatch)
+		}
+	})
+
+	t.Run("etcd not running", func(t *testing.T) {
+		t.Parallel()
+
+		cp := newControlPlane()
+		cp.noEtcd = true
+
+		if _, err := Backup(context.Background(), cp.client(t), testCP, testNow); !errors.Is(err, ErrNoEtcd) {
+			t.Errorf("Backup() error = %v, want %v", err, ErrNoEtcd)
+		}
+	})
+}
+
+func TestRestore(t *testing.T) {
+	t.Parallel()
+
+	t.Run("restore", func(t *testing.T) {
+		t.Parallel()
+
+		cp := newControlPlane()
+		s := Snapshot{Manifest: Manifest{SHA256: checksum(testSnapshot())}, Data: testSnapshot()}
+
+		var steps []string
+
+		must(t, Restore(context.Background(), cp.client(t), testCP, s, testNow, func(step string) {
+			steps = append(steps, step)
+		}))
+
+		if uploaded, _ := cp.dialer.ReadFile(testHost, uploadPath); string(uploaded) != string(s.Data) {
+			t.Error("snapshot not uploaded")
+		}
+
+		script := strings.Join(cp.dialer.Commands(testHost), "\n")
+		for _, want := range []string{
+			"etcdutl snapshot restore " + snapshotPath + " --data-dir " + restoreDir,
+			"=https://10.0.0.10:2380",
+			"sudo mv " + manifest + " " + parkedManifest,
+			"member.bak-2025-03-01T120000Z",
+		} {
+			if !strings.Contains(script, want) {
+				t.Errorf("commands missing %q:\n%s", want, script)
+			}
+		}
+
+		if len(steps) != 3 {
+			t.Errorf("steps = %q, want 3", steps)
+		}
+	})
+
+	t.Run("corrupted snapshot", func(t *testing.T) {
+		t.Parallel()
+
+		cp := newControlPlane()
+		data := testSnapshot()
+		data[0] = 'x'
+
+		err := Restore(context.Background(), cp.client(t), testCP, Snapshot{Data: data}, testNow, nil)
+		if !errors.Is(err, ErrCorruptSnapshot) {
+			t.Errorf("Restore() error = %v, want %v", err, ErrCorruptSnapshot)
+		}
+
+		if len(cp.dialer.Calls()) != 0 {
+			t.Errorf("commands run with a corrupted snapshot: %v", cp.dialer.Calls())
+		}
+	})
+}
+
+func TestWaitAPIServer(t *testing.T) {
+	t.Parallel()
+
+	polls := 0
+	dialer := &remotetest.FakeDialer{Handler: func(_, _ string) ([]byte, error) {
+		polls++
+		if polls < 3 {
+			return nil, &remote.ExitError{Status: 1, Stderr: "connection refused"}
+		}
+
+		return []byte("ok"), nil
+	}}
+	c, _ := dialer.Dial(context.Background(), testHost)
+
+	must(t, WaitAPIServer(context.Background(), c, time.Millisecond))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	down := &remotetest.FakeDialer{Handler: func(_, _ string) ([]byte, error) { return nil, remotetest.Exit(1) }}
+	c, _ = down.Dial(context.Background(), testHost)
+
+	if err := WaitAPIServer(ctx, c, time.Millisecond); !errors.Is(err, ErrAPIServer) {
+		t.Errorf("WaitAPIServer() error = %v, want %v", err, ErrAPIServer)
+	}
+}
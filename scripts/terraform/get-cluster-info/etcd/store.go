@@ -0,0 +1,202 @@
+package etcd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// =============================================================================
+// Storage
+// =============================================================================
+
+// S3KeySuffix is appended to the state key to form the prefix of the
+// snapshots in the backend bucket.
+const S3KeySuffix = ".etcd"
+
+const (
+	dirPermissions  = 0o700
+	filePermissions = 0o600
+)
+
+// ErrNoSnapshot is returned when a location holds no snapshot.
+var ErrNoSnapshot = errors.New("no etcd snapshot")
+
+// Store saves snapshots in dated directories and loads them back by location.
+type Store interface {
+	// Save stores the snapshot and its manifest, and returns their location.
+	Save(ctx context.Context, s Snapshot) (string, error)
+	// Load reads and verifies the snapshot at location (as returned by Save,
+	// or the snapshot file itself).
+	Load(ctx context.Context, location string) (Snapshot, error)
+}
+
+// encodeManifest returns the JSON manifest of a snapshot.
+func encodeManifest(m Manifest) []byte {
+	data, _ := json.MarshalIndent(m, "", "  ")
+
+	return append(data, '\n')
+}
+
+// decode builds a snapshot from its files and verifies it. manifest is
+// optional: a bare snapshot is only checked with its sha256 trailer.
+func decode(data, manifest []byte) (Snapshot, error) {
+	s := Snapshot{Data: data}
+
+	if manifest != nil {
+		if err := json.Unmarshal(manifest, &s.Manifest); err != nil {
+			return Snapshot{}, fmt.Errorf("failed to parse %s: %w", ManifestFile, err)
+		}
+	}
+
+	if err := s.Verify(); err != nil {
+		return Snapshot{}, err
+	}
+
+	return s, nil
+}
+
+// -----------------------------------------------------------------------------
+// Local directory
+// -----------------------------------------------------------------------------
+
+// DirStore stores the snapshots in <Dir>/<date>/.
+type DirStore struct {
+	Dir string
+}
+
+var _ Store = DirStore{}
+
+// Save implements Store.
+func (d DirStore) Save(_ context.Context, s Snapshot) (string, error) {
+	dir := filepath.Join(d.Dir, s.Manifest.CreatedAt.UTC().Format(backupTimeFormat))
+
+	if err := os.MkdirAll(dir, dirPermissions); err != nil {
+		return "", fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, SnapshotFile), s.Data, filePermissions); err != nil {
+		return "", fmt.Errorf("failed to write snapshot: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, ManifestFile), encodeManifest(s.Manifest), filePermissions); err != nil {
+		return "", fmt.Errorf("failed to write manifest: %w", err)
+	}
+
+	return dir, nil
+}
+
+// Load implements Store. location is a backup directory or a snapshot file.
+func (d DirStore) Load(_ context.Context, location string) (Snapshot, error) {
+	file := location
+	if fi, err := os.Stat(location); err == nil && fi.IsDir() {
+		file = filepath.Join(location, SnapshotFile)
+	}
+
+	data, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return Snapshot{}, fmt.Errorf("%w at %s", ErrNoSnapshot, location)
+	}
+
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+
+	manifest, err := os.ReadFile(filepath.Join(filepath.Dir(file), ManifestFile))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return Snapshot{}, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	return decode(data, manifest)
+}
+
+// -----------------------------------------------------------------------------
+// S3 bucket
+// -----------------------------------------------------------------------------
+
+// S3Store stores the snapshots in s3://<Bucket>/<Prefix>/<date>/.
+type S3Store struct {
+	Client *minio.Client
+	Bucket string
+	Prefix string
+}
+
+var _ Store = S3Store{}
+
+// Save implements Store.
+func (s3 S3Store) Save(ctx context.Context, s Snapshot) (string, error) {
+	dir := path.Join(s3.Prefix, s.Manifest.CreatedAt.UTC().Format(backupTimeFormat))
+
+	for name, data := range map[string][]byte{SnapshotFile: s.Data, ManifestFile: encodeManifest(s.Manifest)} {
+		key := path.Join(dir, name)
+
+		_, err := s3.Client.PutObject(ctx, s3.Bucket, key, bytes.NewReader(data), int64(len(data)),
+			minio.PutObjectOptions{ContentType: "application/octet-stream"})
+		if err != nil {
+			return "", fmt.Errorf("failed to write s3://%s/%s: %w", s3.Bucket, key, err)
+		}
+	}
+
+	return "s3://" + s3.Bucket + "/" + dir, nil
+}
+
+// Load implements Store. location is s3://<bucket>/<backup directory or snapshot key>.
+func (s3 S3Store) Load(ctx context.Context, location string) (Snapshot, error) {
+	bucket, key, ok := strings.Cut(strings.TrimPrefix(location, "s3://"), "/")
+	if !ok || !strings.HasPrefix(location, "s3://") {
+		return Snapshot{}, fmt.Errorf("%w at %s: want s3://<bucket>/<key>", ErrNoSnapshot, location)
+	}
+
+	if path.Base(key) != SnapshotFile {
+		key = path.Join(key, SnapshotFile)
+	}
+
+	data, err := s3.get(ctx, bucket, key)
+	if errors.Is(err, errNoSuchKey) {
+		return Snapshot{}, fmt.Errorf("%w at %s", ErrNoSnapshot, location)
+	}
+
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	manifest, err := s3.get(ctx, bucket, path.Join(path.Dir(key), ManifestFile))
+	if err != nil && !errors.Is(err, errNoSuchKey) {
+		return Snapshot{}, err
+	}
+
+	return decode(data, manifest)
+}
+
+// errNoSuchKey is returned by get for a missing object.
+var errNoSuchKey = errors.New("no such key")
+
+// get reads an object.
+func (s3 S3Store) get(ctx context.Context, bucket, key string) ([]byte, error) {
+	obj, err := s3.Client.GetObject(ctx, bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read s3://%s/%s: %w", bucket, key, err)
+	}
+
+	defer func() { _ = obj.Close() }()
+
+	data, err := io.ReadAll(obj)
+	if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+		return nil, errNoSuchKey
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read s3://%s/%s: %w", bucket, key, err)
+	}
+
+	return data, nil
+}
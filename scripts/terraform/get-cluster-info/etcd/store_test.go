@@ -0,0 +1,61 @@
+package etcd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDirStore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := DirStore{Dir: t.TempDir()}
+	s := Snapshot{Manifest: Manifest{CreatedAt: testNow, SHA256: checksum(testSnapshot())}, Data: testSnapshot()}
+
+	dir, err := store.Save(ctx, s)
+	must(t, err)
+
+	if want := filepath.Join(store.Dir, "2025-03-01T120000Z"); dir != want {
+		t.Errorf("Save() = %s, want %s", dir, want)
+	}
+
+	t.Run("by directory or file", func(t *testing.T) {
+		t.Parallel()
+
+		for _, location := range []string{dir, filepath.Join(dir, SnapshotFile)} {
+			got, err := store.Load(ctx, location)
+			must(t, err)
+
+			if got.Manifest != s.Manifest {
+				t.Errorf("Load(%s) manifest = %+v, want %+v", location, got.Manifest, s.Manifest)
+			}
+		}
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := store.Load(ctx, filepath.Join(store.Dir, "nope")); !errors.Is(err, ErrNoSnapshot) {
+			t.Errorf("Load() error = %v, want %v", err, ErrNoSnapshot)
+		}
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		t.Parallel()
+
+		tampered := filepath.Join(t.TempDir(), "copy")
+		must(t, os.MkdirAll(tampered, 0o700))
+		must(t, os.WriteFile(filepath.Join(tampered, SnapshotFile), testSnapshot()[:4096], 0o600))
+
+		manifest, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+		must(t, err)
+		must(t, os.WriteFile(filepath.Join(tampered, ManifestFile), manifest, 0o600))
+
+		if _, err := store.Load(ctx, tampered); !errors.Is(err, ErrChecksumMismatch) {
+			t.Errorf("Load() error = %v, want %v", err, ErrChecksumMismatch)
+		}
+	})
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/k8s-lab/get-cluster-info/etcd"
+	"github.com/k8s-lab/get-cluster-info/remote/remotetest"
+)
+
+// =============================================================================
+// etcd subcommand tests
+// =============================================================================
+
+// testEtcdSnapshot is a snapshot database followed by its sha256, like etcdctl writes.
+func testEtcdSnapshot() []byte {
+	db := []byte(strings.Repeat("etcd", 1024))
+	sum := sha256.Sum256(db)
+
+	return append(db, sum[:]...)
+}
+
+// setupEtcd is setupBootstrap with a control plane answering the etcd commands,
+// and the backups in a temporary directory.
+func setupEtcd(t *testing.T) (*remotetest.FakeDialer, func() string) {
+	t.Helper()
+
+	var dialer *remotetest.FakeDialer
+
+	sha := func(data []byte) string {
+		sum := sha256.Sum256(data)
+
+		return hex.EncodeToString(sum[:])
+	}
+
+	dialer, out := setupBootstrap(t, func(host, cmd string) ([]byte, error) {
+		switch {
+		case strings.HasPrefix(cmd, "sudo crictl ps"):
+			return []byte("3f2a1b\n"), nil
+		case strings.Contains(cmd, "snapshot status"):
+			return []byte(`{"revision":4242,"totalKey":1234}`), nil
+		case strings.Contains(cmd, "mktemp"):
+			dialer.WriteFile(host, "/tmp/tmp.Xq3", testEtcdSnapshot())
+
+			return []byte(sha(testEtcdSnapshot()) + "  /tmp/tmp.Xq3\n"), nil
+		case strings.HasPrefix(cmd, "sha256sum "):
+			data, _ := dialer.ReadFile(host, strings.TrimPrefix(cmd, "sha256sum "))
+
+			return []byte(sha(data) + "  -\n"), nil
+		}
+
+		return nil, nil
+	})
+
+	oldDir, oldS3, oldTimeout, oldInterval := etcdBackupDir, etcdBackupS3, etcdTimeout, etcdPollInterval
+	t.Cleanup(func() {
+		etcdBackupDir, etcdBackupS3, etcdTimeout, etcdPollInterval = oldDir, oldS3, oldTimeout, oldInterval
+	})
+
+	etcdBackupDir, etcdBackupS3, etcdTimeout, etcdPollInterval = t.TempDir(), false, time.Second, time.Millisecond
+
+	return dialer, out
+}
+
+func TestRunEtcdBackup(t *testing.T) {
+	// Not parallel - modifies global config
+	_, out := setupEtcd(t)
+	config.JSONOutput, config.Quiet = true, true
+
+	must(t, runEtcdBackup(nil, nil))
+
+	var got struct {
+		Location string        `json:"location"`
+		Manifest etcd.Manifest `json:"manifest"`
+	}
+	if err := json.Unmarshal([]byte(out()), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out())
+	}
+
+	if want := filepath.Join(etcdBackupDir, "2025-03-01T120000Z"); got.Location != want {
+		t.Errorf("location = %s, want %s", got.Location, want)
+	}
+
+	if got.Manifest.Revision != 4242 || !got.Manifest.CreatedAt.Equal(testNow) {
+		t.Errorf("manifest = %+v", got.Manifest)
+	}
+
+	if _, err := os.Stat(filepath.Join(got.Location, etcd.SnapshotFile)); err != nil {
+		t.Errorf("snapshot not written: %v", err)
+	}
+}
+
+func TestRunEtcdRestore(t *testing.T) {
+	// Not parallel - modifies global config
+
+	t.Run("from a backup", func(t *testing.T) {
+		dialer, out := setupEtcd(t)
+		must(t, runEtcdBackup(nil, nil))
+
+		backup := filepath.Join(etcdBackupDir, "2025-03-01T120000Z")
+		must(t, runEtcdRestore(nil, []string{backup}))
+
+		if !strings.Contains(out(), "etcd restored") {
+			t.Errorf("output missing the success:\n%s", out())
+		}
+
+		if !strings.Contains(strings.Join(dialer.Commands("203.0.113.10"), "\n"), "etcdutl snapshot restore") {
+			t.Error("etcdutl snapshot restore not run")
+		}
+	})
+
+	t.Run("tampered snapshot", func(t *testing.T) {
+		dialer, _ := setupEtcd(t)
+		must(t, runEtcdBackup(nil, nil))
+
+		snapshot := filepath.Join(etcdBackupDir, "2025-03-01T120000Z", etcd.SnapshotFile)
+		must(t, os.WriteFile(snapshot, []byte("not a snapshot"), 0o600))
+
+		calls := len(dialer.Calls())
+
+		if err := runEtcdRestore(nil, []string{snapshot}); !errors.Is(err, etcd.ErrChecksumMismatch) {
+			t.Errorf("runEtcdRestore() error = %v, want %v", err, etcd.ErrChecksumMismatch)
+		}
+
+		if len(dialer.Calls()) != calls {
+			t.Errorf("commands run with a tampered snapshot: %v", dialer.Calls()[calls:])
+		}
+	})
+}
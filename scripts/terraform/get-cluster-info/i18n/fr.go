@@ -0,0 +1,219 @@
+package i18n
+
+// french is the French catalog, keyed by the English message.
+var french = map[string]string{
+	// -------------------------------------------------------------------------
+	// Cluster summary
+	// -------------------------------------------------------------------------
+	"K8S-LAB CLUSTER":     "CLUSTER K8S-LAB",
+	"Public IP:":          "IP publique :",
+	"Private IP:":         "IP privée :",
+	"SSH CONNECTION":      "CONNEXION SSH",
+	"Key:":                "Clé :",
+	"LIFETIME":            "DURÉE DE VIE",
+	"Expires in %s (%s)":  "Expire dans %s (%s)",
+	"EXPIRED %s ago (%s)": "EXPIRÉ depuis %s (%s)",
+	" - run ":             " - lancez ",
+	"OUTPUTS":             "OUTPUTS TERRAFORM",
+
+	// -------------------------------------------------------------------------
+	// Cluster information and Terraform
+	// -------------------------------------------------------------------------
+	"Loading credentials from %s":                        "Chargement des identifiants depuis %s",
+	"Credentials loaded":                                 "Identifiants chargés",
+	"Retrieving cluster information...":                  "Récupération des informations du cluster...",
+	"Information retrieved":                              "Informations récupérées",
+	"Private network check skipped: %v":                  "Vérification du réseau privé ignorée : %v",
+	"Retrieving outputs...":                              "Récupération des outputs...",
+	"Initializing Terraform (%s)...":                     "Initialisation de Terraform (%s)...",
+	"Terraform initialized":                              "Terraform initialisé",
+	"Terraform already initialized (%s) - skipping init": "Terraform déjà initialisé (%s) - init ignoré",
+	"%s is read-only - initializing in a temporary data dir": "%s est en lecture seule - " +
+		"initialisation dans un répertoire de données temporaire",
+	"Reading the Terraform state...": "Lecture du state Terraform...",
+	"Running terraform plan...":      "Exécution de terraform plan...",
+	"Refreshing the state against the deployed infrastructure...": "Rafraîchissement du state " +
+		"par rapport à l'infrastructure déployée...",
+	"Plan saved to %s - apply it with %s":    "Plan enregistré dans %s - appliquez-le avec %s",
+	"Failed to read output descriptions: %v": "Impossible de lire les descriptions des outputs : %v",
+	"No outputs found - the cluster may not be deployed": "Aucun output trouvé - le cluster n'est " +
+		"peut-être pas déployé",
+	"No security group rules in the state - the cluster may not be deployed": "Aucune règle de " +
+		"security group dans le state - le cluster n'est peut-être pas déployé",
+	"No SSH key found in outputs":                "Aucune clé SSH dans les outputs",
+	"SSH key saved: %s":                          "Clé SSH enregistrée : %s",
+	"Failed to save SSH key: %v":                 "Impossible d'enregistrer la clé SSH : %v",
+	"Failed to write GitHub Actions outputs: %v": "Impossible d'écrire les outputs GitHub Actions : %v",
+	"Updated at %s, watching every %s (Ctrl+C to stop)": "Mis à jour à %s, surveillance toutes les %s " +
+		"(Ctrl+C pour arrêter)",
+	"Cannot read the state version (%v): fetching the outputs on every tick": "Impossible de lire " +
+		"la version du state (%v) : les outputs sont récupérés à chaque intervalle",
+	"--on-change: %v": "--on-change : %v",
+
+	// -------------------------------------------------------------------------
+	// Lab lifetime
+	// -------------------------------------------------------------------------
+	"Lab expires in %s (%s)":              "Le lab expire dans %s (%s)",
+	"Lab expires in %s - nothing to reap": "Le lab expire dans %s - rien à détruire",
+	"Lab expired %s ago - run %s":         "Le lab a expiré depuis %s - lancez %s",
+	"Lab expired %s ago - would run terraform destroy (--dry-run)": "Le lab a expiré depuis %s - " +
+		"terraform destroy serait lancé (--dry-run)",
+	"Lab expired %s ago - running terraform destroy...": "Le lab a expiré depuis %s - " +
+		"exécution de terraform destroy...",
+	"Lab destroyed":                         "Lab détruit",
+	"No TTL set - the lab is never reaped":  "Aucun TTL défini - le lab n'est jamais détruit",
+	"No TTL set - nothing to reap":          "Aucun TTL défini - rien à détruire",
+	"TTL removed - the lab is never reaped": "TTL supprimé - le lab n'est jamais détruit",
+	"Failed to read the lab TTL: %v":        "Impossible de lire le TTL du lab : %v",
+
+	// -------------------------------------------------------------------------
+	// init-config
+	// -------------------------------------------------------------------------
+	"K8S-LAB CONFIGURATION":            "CONFIGURATION K8S-LAB",
+	"Project name":                     "Nom du projet",
+	"Control-plane instance type":      "Type d'instance du control-plane",
+	"Worker instance type":             "Type d'instance du worker",
+	"Zone":                             "Zone",
+	"CIDR allowed for SSH and the API": "CIDR autorisé pour SSH et l'API",
+	"S3 access key":                    "Clé d'accès S3",
+	"S3 secret key":                    "Clé secrète S3",
+	"%s (hidden): ":                    "%s (masquée) : ",
+	"Written (0600): %s":               "Écrit (0600) : %s",
+	"Next step: %s":                    "Étape suivante : %s",
+	"Public IP detected on a local interface: %s": "IP publique détectée sur une interface locale : %s",
+	"No public IP on the local interfaces (NAT?) - pass --public-ip or type your IP/32 " +
+		"(curl ifconfig.me), otherwise SSH stays open to everyone": "Aucune IP publique sur les " +
+		"interfaces locales (NAT ?) - passez --public-ip ou saisissez votre IP/32 (curl ifconfig.me), " +
+		"sinon SSH reste ouvert à tous",
+
+	// -------------------------------------------------------------------------
+	// Nodes: bootstrap, join, CNI, etcd, support bundle, dashboard
+	// -------------------------------------------------------------------------
+	"Bootstrapping Kubernetes %s on %d node(s)...": "Installation de Kubernetes %s sur %d node(s)...",
+	"Cluster bootstrapped - the nodes become Ready once a CNI is installed": "Cluster installé - " +
+		"les nodes deviennent Ready une fois un CNI installé",
+	"Joining %s to the cluster...":                   "Ajout de %s au cluster...",
+	"Waiting for %s to be Ready (timeout %s)...":     "Attente de l'état Ready de %s (délai %s)...",
+	"%s registered as %s, not Ready yet: %s":         "%s enregistré comme %s, pas encore Ready : %s",
+	"%s joined the cluster and is Ready (node %s)":   "%s a rejoint le cluster et est Ready (node %s)",
+	"Installing Cilium %s from the control plane...": "Installation de Cilium %s depuis le control plane...",
+	"Cilium %s installed":                            "Cilium %s installé",
+	"Waiting for the cilium agents (timeout %s)...":  "Attente des agents cilium (délai %s)...",
+	"%d/%d agent(s) ready":                           "%d/%d agent(s) prêt(s)",
+	"Saving an etcd snapshot on %s...":               "Sauvegarde d'un snapshot etcd sur %s...",
+	"Snapshot saved to %s":                           "Snapshot enregistré dans %s",
+	"No %s next to the snapshot: only its etcd checksum is verified": "Pas de %s à côté du snapshot : " +
+		"seule sa somme de contrôle etcd est vérifiée",
+	"Restoring etcd on %s from %s...":            "Restauration d'etcd sur %s depuis %s...",
+	"Waiting for the API server (timeout %s)...": "Attente de l'API server (délai %s)...",
+	"etcd restored - the previous data is kept in /var/lib/etcd/member.bak-*": "etcd restauré - " +
+		"les données précédentes sont conservées dans /var/lib/etcd/member.bak-*",
+	"Collecting the support bundle from %d node(s)...": "Collecte du support bundle sur %d node(s)...",
+	"[%s] collecting logs...":                          "[%s] collecte des logs...",
+	"Some items could not be collected: see %s in the bundle": "Certains éléments n'ont pas pu " +
+		"être collectés : voir %s dans l'archive",
+	"Support bundle written to %s (%d files, secrets scrubbed)": "Support bundle écrit dans %s " +
+		"(%d fichiers, secrets masqués)",
+	"SSH key not found at %s: run get-cluster-info first to save it for the SSH sessions": "Clé SSH " +
+		"introuvable dans %s : lancez d'abord get-cluster-info pour l'enregistrer pour les sessions SSH",
+
+	// -------------------------------------------------------------------------
+	// Errors and hints
+	// -------------------------------------------------------------------------
+	"terraform directory not found: %s": "répertoire terraform introuvable : %s",
+	"failed to find terraform directory: %w\n\nUse --terraform-dir to specify it manually": "impossible " +
+		"de trouver le répertoire terraform : %w\n\nUtilisez --terraform-dir pour l'indiquer",
+	"credentials file not found: %s\n\nCreate the file with your S3 credentials:\n" +
+		"  cp %s/backend.yaml.example %s   # or backend.json.example → backend.json": "fichier " +
+		"d'identifiants introuvable : %s\n\nCréez le fichier avec vos identifiants S3 :\n" +
+		"  cp %s/backend.yaml.example %s   # ou backend.json.example → backend.json",
+	"%w\n\nUse --lenient to display the available information anyway": "%w\n\nUtilisez --lenient " +
+		"pour afficher quand même les informations disponibles",
+	"%w\n\nFix the problem and run %s again: completed steps are skipped": "%w\n\nCorrigez le " +
+		"problème et relancez %s : les étapes terminées sont ignorées",
+	"%w\n\nThe nodes become Ready once a CNI is installed: %s": "%w\n\nLes nodes deviennent Ready " +
+		"une fois un CNI installé : %s",
+	"%w\n\nAdd the node to local.nodes and run terraform apply first: " +
+		"the nodes are read from the nodes output of the module": "%w\n\nAjoutez le node à " +
+		"local.nodes et lancez d'abord terraform apply : les nodes sont lus dans l'output nodes du module",
+	"%w\n\nCheck the etcd container on the control plane: %s": "%w\n\nVérifiez le conteneur etcd " +
+		"sur le control plane : %s",
+	"%w: %d failed check(s)":                       "%w : %d vérification(s) en échec",
+	"%w: %d finding(s) of severity %s or more":     "%w : %d problème(s) de sévérité %s ou plus",
+	"%w: %d resource(s) changed outside Terraform": "%w : %d ressource(s) modifiée(s) hors de Terraform",
+	"invalid --color %q (auto, always or never)":   "--color %q invalide (auto, always ou never)",
+	"invalid --lang: %w":                           "--lang invalide : %w",
+	"invalid --fail-on: %w":                        "--fail-on invalide : %w",
+	"invalid --out: %w":                            "--out invalide : %w",
+	"invalid --public-ip: %w":                      "--public-ip invalide : %w",
+	"invalid --watch %s: the interval must be positive": "--watch %s invalide : l'intervalle doit être " +
+		"positif",
+	"failed to create %s: %w":                 "impossible de créer %s : %w",
+	"failed to read %s: %w":                   "impossible de lire %s : %w",
+	"failed to write SSH key: %w":             "impossible d'écrire la clé SSH : %w",
+	"failed to read the SSH key: %w":          "impossible de lire la clé SSH : %w",
+	"failed to read terraform state: %w":      "impossible de lire le state terraform : %w",
+	"failed to read plan file: %w":            "impossible de lire le fichier de plan : %w",
+	"failed to create temporary plan dir: %w": "impossible de créer le répertoire temporaire du plan : %w",
+	"failed to read --values: %w":             "impossible de lire --values : %w",
+	"failed to create the bundle: %w":         "impossible de créer l'archive : %w",
+	"failed to write the bundle: %w":          "impossible d'écrire l'archive : %w",
+	"failed to encode the cluster info: %w":   "impossible d'encoder les informations du cluster : %w",
+	"failed to run %q: %w":                    "impossible d'exécuter %q : %w",
+	"terraform plan failed: %w":               "échec de terraform plan : %w",
+	"terraform plan -refresh-only failed: %w": "échec de terraform plan -refresh-only : %w",
+	"terraform destroy failed: %w":            "échec de terraform destroy : %w",
+
+	// Sentinel errors of the packages (see translatedErrors in main.go)
+	"no data found - the cluster may not be deployed": "aucune donnée - le cluster n'est peut-être " +
+		"pas déployé",
+	"terraform is not installed or not in PATH":    "terraform n'est pas installé ou pas dans le PATH",
+	"access_key or secret_key missing":             "access_key ou secret_key manquant",
+	"no s3 backend in the terraform configuration": "pas de backend s3 dans la configuration terraform",
+	"invalid TTL (e.g. 8h, 90m, 2d)":               "TTL invalide (ex. 8h, 90m, 2d)",
+	"output is missing":                            "output manquant",
+	"output is not a string":                       "l'output n'est pas une chaîne",
+	"output is not a map of nodes":                 "l'output n'est pas une map de nodes",
+	"output is empty":                              "output vide",
+	"not a valid IP address":                       "adresse IP invalide",
+	"outside the private network":                  "hors du réseau privé",
+	"no control-plane node discovered (expected e.g. control_plane_public_ip)": "aucun node " +
+		"control-plane découvert (attendu par ex. control_plane_public_ip)",
+	"drift detected":                                  "dérive détectée",
+	"doctor found problems":                           "doctor a trouvé des problèmes",
+	"audit found exposed services":                    "l'audit a trouvé des services exposés",
+	"bootstrap step failed":                           "échec d'une étape d'installation",
+	"unknown node":                                    "node inconnu",
+	"not a worker node":                               "ce n'est pas un node worker",
+	"node is not Ready":                               "le node n'est pas Ready",
+	"cilium is not ready":                             "cilium n'est pas prêt",
+	"etcd container not running on the control plane": "le conteneur etcd ne tourne pas sur le control plane",
+	"corrupt etcd snapshot":                           "snapshot etcd corrompu",
+	"snapshot checksum mismatch":                      "somme de contrôle du snapshot incorrecte",
+	"API server not ready":                            "API server pas prêt",
+	"no etcd snapshot":                                "aucun snapshot etcd",
+	"host key changed":                                "la clé d'hôte a changé",
+	"file already exists (use --force to overwrite)": "le fichier existe déjà (utilisez --force pour " +
+		"l'écraser)",
+	"use lowercase letters, digits and dashes, starting with a letter": "utilisez des minuscules, des " +
+		"chiffres et des tirets, en commençant par une lettre",
+	"not a Scaleway instance type (e.g. DEV1-M)": "pas un type d'instance Scaleway (ex. DEV1-M)",
+	"unknown zone":             "zone inconnue",
+	"value is required":        "valeur obligatoire",
+	"no answer (input closed)": "pas de réponse (entrée fermée)",
+
+	// Hints of the error classes (see errorClasses in errors.go)
+	"Run get-cluster-info doctor to check the prerequisites": "Lancez get-cluster-info doctor pour vérifier " +
+		"les prérequis",
+	"Check access_key and secret_key in the credentials file (--credentials)": "Vérifiez access_key et " +
+		"secret_key dans le fichier de credentials (--credentials)",
+	"Check the S3 backend (bucket, endpoint, credentials), or retry with --force-init": "Vérifiez le " +
+		"backend S3 (bucket, endpoint, credentials), ou relancez avec --force-init",
+	"Deploy the cluster with terraform apply": "Déployez le cluster avec terraform apply",
+	"Use --lenient to display the available information anyway": "Utilisez --lenient pour afficher " +
+		"quand même les informations disponibles",
+	"Check that the nodes are running and that the security group allows SSH from this host": "Vérifiez " +
+		"que les nodes tournent et que le security group autorise SSH depuis cette machine",
+	"Check the flags (get-cluster-info --help) and the config file (--config)": "Vérifiez les options " +
+		"(get-cluster-info --help) et le fichier de configuration (--config)",
+}
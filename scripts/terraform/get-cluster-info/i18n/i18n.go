@@ -0,0 +1,126 @@
+// Package i18n translates the user-facing messages of get-cluster-info: log
+// messages, errors and the cluster summary.
+//
+// Messages are written in English in the code and are the keys of the
+// catalogs, like gettext msgids:
+//
+//	logInfo("Loading credentials from %s", path)     // looked up with T
+//	return i18n.Errorf("terraform directory not found: %s", dir)
+//
+// English is the source language and needs no catalog. A message missing from
+// a catalog falls back to English; TestCatalogs checks that every message of
+// the code exists in every catalog, with the same format verbs.
+package i18n
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Language is a supported language.
+type Language string
+
+// Supported languages.
+const (
+	English Language = "en"
+	French  Language = "fr"
+)
+
+// ErrUnknownLanguage is returned by Parse.
+var ErrUnknownLanguage = errors.New("unknown language (en or fr)")
+
+// catalogs maps a language to its translations, keyed by the English message.
+var catalogs = map[Language]map[string]string{
+	French: french,
+}
+
+// current is the language of the messages (see Set).
+var current = English
+
+// Languages returns the supported languages.
+func Languages() []Language {
+	return []Language{English, French}
+}
+
+// Set changes the language of the messages.
+func Set(l Language) {
+	current = l
+}
+
+// Current returns the language of the messages.
+func Current() Language {
+	return current
+}
+
+// Parse accepts a language code ("fr") or a locale ("fr_FR.UTF-8"). The C and
+// POSIX locales are English.
+func Parse(s string) (Language, error) {
+	code := strings.ToLower(s)
+	if i := strings.IndexAny(code, "_.-@"); i >= 0 {
+		code = code[:i]
+	}
+
+	switch code {
+	case "en", "c", "posix":
+		return English, nil
+	case "fr":
+		return French, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnknownLanguage, s)
+	}
+}
+
+// Detect returns the language of the locale (LC_ALL, then LC_MESSAGES, then
+// LANG), English when it is unset or not supported.
+func Detect(getenv func(string) string) Language {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if locale := getenv(name); locale != "" {
+			l, err := Parse(locale)
+			if err != nil {
+				return English
+			}
+
+			return l
+		}
+	}
+
+	return English
+}
+
+// T returns the translation of msg in the current language, or msg itself.
+func T(msg string) string {
+	if translated, ok := catalogs[current][msg]; ok {
+		return translated
+	}
+
+	return msg
+}
+
+// Sprintf formats the translation of format.
+func Sprintf(format string, args ...any) string {
+	return fmt.Sprintf(T(format), args...)
+}
+
+// Errorf is fmt.Errorf with the translation of format (%w is kept).
+func Errorf(format string, args ...any) error {
+	return fmt.Errorf(T(format), args...)
+}
+
+// Has reports whether msg can be displayed in l: always in English, else when the catalog has it.
+func Has(l Language, msg string) bool {
+	if l == English {
+		return true
+	}
+
+	_, ok := catalogs[l][msg]
+
+	return ok
+}
+
+// Messages returns the English messages translated by the catalog of l, sorted.
+func Messages(l Language) []string {
+	return slices.Sorted(maps.Keys(catalogs[l]))
+}
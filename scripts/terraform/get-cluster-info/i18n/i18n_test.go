@@ -0,0 +1,289 @@
+package i18n
+
+import (
+	"errors"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// =============================================================================
+// Language selection tests
+// =============================================================================
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in      string
+		want    Language
+		wantErr error
+	}{
+		{in: "fr", want: French},
+		{in: "fr_FR.UTF-8", want: French},
+		{in: "fr-CA", want: French},
+		{in: "EN", want: English},
+		{in: "en_GB.UTF-8", want: English},
+		{in: "C.UTF-8", want: English},
+		{in: "POSIX", want: English},
+		{in: "de_DE.UTF-8", wantErr: ErrUnknownLanguage},
+		{in: "", wantErr: ErrUnknownLanguage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Parse(tt.in)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Parse(%q) error = %v, want %v", tt.in, err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("Parse(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetect(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		env  map[string]string
+		want Language
+	}{
+		{name: "no locale", env: map[string]string{}, want: English},
+		{name: "LANG", env: map[string]string{"LANG": "fr_FR.UTF-8"}, want: French},
+		{
+			name: "LC_MESSAGES wins over LANG",
+			env:  map[string]string{"LC_MESSAGES": "en_US.UTF-8", "LANG": "fr_FR.UTF-8"},
+			want: English,
+		},
+		{name: "LC_ALL wins", env: map[string]string{"LC_ALL": "fr_FR.UTF-8", "LC_MESSAGES": "C"}, want: French},
+		{name: "unsupported language", env: map[string]string{"LANG": "de_DE.UTF-8"}, want: English},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := Detect(func(k string) string { return tt.env[k] }); got != tt.want {
+				t.Errorf("Detect() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// =============================================================================
+// Catalog tests
+// =============================================================================
+
+// translators are the functions whose first argument is a message: the log
+// helpers of the command, the clusterinfo Logger and this package.
+var translators = map[string]bool{
+	"logInfo": true, "logSuccess": true, "logWarning": true, "logError": true,
+	"log.Infof": true, "log.Successf": true, "log.Warnf": true,
+	"i18n.T": true, "i18n.Sprintf": true, "i18n.Errorf": true,
+}
+
+// extraMessages are translated without a translator call: the sentinel errors
+// listed in translatedErrors (main.go), checked by TestTranslatedErrors, and
+// the hints of the error classes (errors.go), checked by TestErrorClassHints.
+var extraMessages = []string{
+	"no data found - the cluster may not be deployed",
+	"terraform is not installed or not in PATH",
+	"access_key or secret_key missing",
+	"no s3 backend in the terraform configuration",
+	"invalid TTL (e.g. 8h, 90m, 2d)",
+	"output is missing",
+	"output is not a string",
+	"output is not a map of nodes",
+	"output is empty",
+	"not a valid IP address",
+	"outside the private network",
+	"no control-plane node discovered (expected e.g. control_plane_public_ip)",
+	"drift detected",
+	"doctor found problems",
+	"audit found exposed services",
+	"bootstrap step failed",
+	"unknown node",
+	"not a worker node",
+	"node is not Ready",
+	"cilium is not ready",
+	"etcd container not running on the control plane",
+	"corrupt etcd snapshot",
+	"snapshot checksum mismatch",
+	"API server not ready",
+	"no etcd snapshot",
+	"host key changed",
+	"file already exists (use --force to overwrite)",
+	"use lowercase letters, digits and dashes, starting with a letter",
+	"not a Scaleway instance type (e.g. DEV1-M)",
+	"unknown zone",
+	"value is required",
+	"no answer (input closed)",
+	"Run get-cluster-info doctor to check the prerequisites",
+	"Check access_key and secret_key in the credentials file (--credentials)",
+	"Check the S3 backend (bucket, endpoint, credentials), or retry with --force-init",
+	"Deploy the cluster with terraform apply",
+	"Use --lenient to display the available information anyway",
+	"Check that the nodes are running and that the security group allows SSH from this host",
+	"Check the flags (get-cluster-info --help) and the config file (--config)",
+}
+
+// verbRe matches the fmt verbs of a message.
+var verbRe = regexp.MustCompile(`%[-+# 0-9.*]*[a-zA-Z%]`)
+
+// hasWords reports whether msg has text to translate besides its verbs ("%s: %w" has none).
+func hasWords(msg string) bool {
+	return strings.ContainsFunc(verbRe.ReplaceAllString(msg, ""), func(r rune) bool {
+		return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z'
+	})
+}
+
+// sourceMessages returns the messages passed to the translators in the code
+// of the command (the parent directory), with their position.
+func sourceMessages(t *testing.T) map[string]string {
+	t.Helper()
+
+	messages := map[string]string{}
+	fset := token.NewFileSet()
+
+	err := filepath.WalkDir("..", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() && d.Name() == "testdata" {
+			return filepath.SkipDir
+		}
+
+		if d.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			return err
+		}
+
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || len(call.Args) == 0 || !translators[funcName(call.Fun)] {
+				return true
+			}
+
+			if msg, ok := stringConstant(call.Args[0]); ok && hasWords(msg) {
+				messages[msg] = fset.Position(call.Pos()).String()
+			}
+
+			return true
+		})
+
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(messages) == 0 {
+		t.Fatal("no message found in the code")
+	}
+
+	return messages
+}
+
+// funcName returns "name" or "pkg.name" for a called function.
+func funcName(fun ast.Expr) string {
+	switch f := fun.(type) {
+	case *ast.Ident:
+		return f.Name
+	case *ast.SelectorExpr:
+		if x, ok := f.X.(*ast.Ident); ok {
+			return x.Name + "." + f.Sel.Name
+		}
+	}
+
+	return ""
+}
+
+// stringConstant evaluates a string literal, or a concatenation of literals.
+func stringConstant(e ast.Expr) (string, bool) {
+	switch e := e.(type) {
+	case *ast.BasicLit:
+		if e.Kind != token.STRING {
+			return "", false
+		}
+
+		s, err := strconv.Unquote(e.Value)
+
+		return s, err == nil
+	case *ast.BinaryExpr:
+		x, okX := stringConstant(e.X)
+		y, okY := stringConstant(e.Y)
+
+		return x + y, okX && okY && e.Op == token.ADD
+	}
+
+	return "", false
+}
+
+func TestCatalogs(t *testing.T) {
+	t.Parallel()
+
+	messages := sourceMessages(t)
+
+	for _, l := range Languages() {
+		for msg, pos := range messages {
+			if !Has(l, msg) {
+				t.Errorf("%s: message missing from the %q catalog: %q", pos, l, msg)
+			}
+		}
+
+		for _, msg := range Messages(l) {
+			if _, used := messages[msg]; !used && !slices.Contains(extraMessages, msg) {
+				t.Errorf("%q catalog: unused message %q", l, msg)
+			}
+
+			if got, want := verbRe.FindAllString(catalogs[l][msg], -1), verbRe.FindAllString(msg, -1); !slices.Equal(got, want) {
+				t.Errorf("%q catalog: verbs of %q = %v, want %v", l, msg, got, want)
+			}
+		}
+	}
+}
+
+func TestT(t *testing.T) {
+	// Not parallel - changes the current language
+
+	t.Cleanup(func() { Set(English) })
+
+	Set(French)
+
+	if got := T("Credentials loaded"); got != french["Credentials loaded"] {
+		t.Errorf("T() = %q, want the French message", got)
+	}
+
+	if got := T("not in any catalog"); got != "not in any catalog" {
+		t.Errorf("T() = %q, want the message itself", got)
+	}
+
+	err := Errorf("failed to write SSH key: %w", fs.ErrPermission)
+	if !errors.Is(err, fs.ErrPermission) || !strings.HasPrefix(err.Error(), "impossible") {
+		t.Errorf("Errorf() = %v, want a French message wrapping the error", err)
+	}
+
+	Set(English)
+
+	if got := T("Credentials loaded"); got != "Credentials loaded" {
+		t.Errorf("T() in English = %q", got)
+	}
+}
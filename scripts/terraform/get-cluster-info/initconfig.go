@@ -0,0 +1,248 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/netip"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/x/term"
+	"github.com/k8s-lab/get-cluster-info/clusterinfo"
+	"github.com/k8s-lab/get-cluster-info/i18n"
+	"github.com/k8s-lab/get-cluster-info/labconfig"
+	"github.com/k8s-lab/get-cluster-info/render"
+	"github.com/spf13/cobra"
+)
+
+// =============================================================================
+// Init-config subcommand
+// =============================================================================
+//
+// Interactive wizard writing terraform.tfvars and backend.yaml (replaces
+// `make setup` and the manual copy of the *.example files). Defaults come from
+// variables.tf; Enter keeps the default shown in brackets.
+
+var (
+	initConfigForce    bool
+	initConfigPublicIP string
+)
+
+// Test hooks (see initconfig_test.go).
+var (
+	// stdin is where the wizard reads the answers.
+	stdin io.Reader = os.Stdin
+	// interfaceAddrs lists the local addresses, to detect a public IP.
+	interfaceAddrs = net.InterfaceAddrs
+)
+
+var initConfigCmd = &cobra.Command{
+	Use:   "init-config",
+	Short: "Create terraform.tfvars and backend.yaml interactively",
+	Long: `Create terraform.tfvars and backend.yaml interactively.
+
+Prompts for the project name, the instance types, the zone, the CIDR allowed
+to reach SSH and the Kubernetes API, and the S3 credentials. Both files are
+written with 0600 permissions. Existing files are never replaced without --force.
+
+allowed_ssh_cidr defaults to your public IP (/32) when a local interface has
+one, or when it is passed with --public-ip (e.g. --public-ip "$(curl -s ifconfig.me)").
+
+Examples:
+  # Interactive setup
+  get-cluster-info init-config
+
+  # Restrict SSH to a known IP
+  get-cluster-info init-config --public-ip 203.0.113.7`,
+	Args: cobra.NoArgs,
+	RunE: runInitConfig,
+}
+
+func init() {
+	initConfigCmd.Flags().BoolVar(&initConfigForce, "force", false,
+		"Overwrite existing terraform.tfvars and backend.yaml")
+
+	initConfigCmd.Flags().StringVar(&initConfigPublicIP, "public-ip", "",
+		"Your public IP, proposed as allowed_ssh_cidr (default: detected from the local interfaces)")
+
+	rootCmd.AddCommand(initConfigCmd)
+}
+
+func runInitConfig(_ *cobra.Command, _ []string) error {
+	if err := resolveDefaults(); err != nil {
+		return err
+	}
+
+	// Refuse before asking anything.
+	if !initConfigForce {
+		if err := labconfig.CheckNotExist(config.TerraformDir); err != nil {
+			return err
+		}
+	}
+
+	sshCIDR, err := defaultSSHCIDR()
+	if err != nil {
+		return err
+	}
+
+	p := newPrompter(stdin, stdout)
+
+	fmt.Fprintln(stdout, render.SectionStyle.Render(i18n.T("K8S-LAB CONFIGURATION")))
+
+	cfg := labconfig.Config{}
+
+	questions := []struct {
+		label    string
+		variable string
+		fallback string
+		validate func(string) error
+		target   *string
+	}{
+		{i18n.T("Project name"), "project_name", "k8s-lab", labconfig.ValidateProjectName, &cfg.ProjectName},
+		{
+			i18n.T("Control-plane instance type"), "control_plane_flavor", "DEV1-M",
+			labconfig.ValidateFlavor, &cfg.ControlPlaneFlavor,
+		},
+		{i18n.T("Worker instance type"), "worker_flavor", "DEV1-M", labconfig.ValidateFlavor, &cfg.WorkerFlavor},
+		{i18n.T("Zone"), "scaleway_zone", "fr-par-1", labconfig.ValidateZone, &cfg.Zone},
+		{i18n.T("CIDR allowed for SSH and the API"), "", sshCIDR, labconfig.ValidateCIDR, &cfg.AllowedSSHCIDR},
+		{i18n.T("S3 access key"), "", "", labconfig.ValidateNotEmpty, &cfg.Credentials.AccessKey},
+	}
+
+	for _, q := range questions {
+		def := q.fallback
+		if q.variable != "" {
+			if v, err := clusterinfo.LoadVariableValue(config.TerraformDir, q.variable); err == nil && v != "" {
+				def = v
+			}
+		}
+
+		if *q.target, err = p.ask(q.label, def, q.validate); err != nil {
+			return err
+		}
+	}
+
+	if cfg.Credentials.SecretKey, err = p.askSecret(i18n.T("S3 secret key")); err != nil {
+		return err
+	}
+
+	written, err := labconfig.Write(config.TerraformDir, cfg, initConfigForce)
+	if err != nil {
+		return err
+	}
+
+	for _, path := range written {
+		logSuccess("Written (0600): %s", render.PathStyle.Render(path))
+	}
+
+	logInfo("Next step: %s", render.CmdStyle.Render("make init && cd terraform && terraform apply"))
+
+	return nil
+}
+
+// defaultSSHCIDR proposes the public IP from --public-ip or a local interface, or 0.0.0.0/0.
+func defaultSSHCIDR() (string, error) {
+	if initConfigPublicIP != "" {
+		ip, err := netip.ParseAddr(initConfigPublicIP)
+		if err != nil {
+			return "", withClass(errUsage, i18n.Errorf("invalid --public-ip: %w", err))
+		}
+
+		return labconfig.HostCIDR(ip), nil
+	}
+
+	addrs, err := interfaceAddrs()
+	if err == nil {
+		if ip, ok := labconfig.DetectPublicIP(addrs); ok {
+			logInfo("Public IP detected on a local interface: %s", ip)
+
+			return labconfig.HostCIDR(ip), nil
+		}
+	}
+
+	logWarning("No public IP on the local interfaces (NAT?) - pass --public-ip or type your IP/32 " +
+		"(curl ifconfig.me), otherwise SSH stays open to everyone")
+
+	return "0.0.0.0/0", nil
+}
+
+// =============================================================================
+// Prompts
+// =============================================================================
+
+var errNoAnswer = errors.New("no answer (input closed)")
+
+// prompter asks questions on a line-based input.
+type prompter struct {
+	in  *bufio.Reader
+	fd  uintptr // terminal file descriptor for hidden input, 0 if stdin is not a terminal
+	out io.Writer
+}
+
+func newPrompter(in io.Reader, out io.Writer) *prompter {
+	p := &prompter{in: bufio.NewReader(in), out: out}
+
+	if f, ok := in.(*os.File); ok && term.IsTerminal(f.Fd()) {
+		p.fd = f.Fd()
+	}
+
+	return p
+}
+
+// ask prints the question, returns def on an empty answer, and asks again until validate passes.
+func (p *prompter) ask(label, def string, validate func(string) error) (string, error) {
+	for {
+		if def != "" {
+			fmt.Fprintf(p.out, "%s [%s]: ", label, def)
+		} else {
+			fmt.Fprintf(p.out, "%s: ", label)
+		}
+
+		line, readErr := p.in.ReadString('\n')
+
+		answer := strings.TrimSpace(line)
+		if answer == "" {
+			answer = def
+		}
+
+		err := validate(answer)
+		if err == nil {
+			return answer, nil
+		}
+
+		if readErr != nil {
+			fmt.Fprintln(p.out)
+
+			return "", i18n.Errorf("%s: %w", label, errNoAnswer)
+		}
+
+		logWarning("%s: %s", label, localizeError(err))
+	}
+}
+
+// askSecret reads a value without echo on a terminal.
+func (p *prompter) askSecret(label string) (string, error) {
+	if p.fd == 0 {
+		return p.ask(label, "", labconfig.ValidateNotEmpty)
+	}
+
+	for {
+		fmt.Fprint(p.out, i18n.Sprintf("%s (hidden): ", label))
+
+		secret, err := term.ReadPassword(p.fd)
+		fmt.Fprintln(p.out)
+
+		if err != nil {
+			return "", i18n.Errorf("failed to read %s: %w", label, err)
+		}
+
+		if value := strings.TrimSpace(string(secret)); value != "" {
+			return value, nil
+		}
+
+		logWarning("%s: %s", label, localizeError(labconfig.ErrEmptyValue))
+	}
+}
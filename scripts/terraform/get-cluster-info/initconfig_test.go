@@ -0,0 +1,178 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/k8s-lab/get-cluster-info/clusterinfo/clusterinfotest"
+	"github.com/k8s-lab/get-cluster-info/i18n"
+	"github.com/k8s-lab/get-cluster-info/labconfig"
+)
+
+// =============================================================================
+// init-config subcommand tests
+// =============================================================================
+
+// setupInitConfig prepares an empty terraform dir (variables.tf only) and the prompt input.
+func setupInitConfig(t *testing.T, input string, addrs ...string) {
+	t.Helper()
+
+	setupRun(t, clusterinfotest.FixtureDeployed)
+	must(t, os.Remove(config.CredentialsFile))
+	must(t, os.WriteFile(filepath.Join(config.TerraformDir, "variables.tf"),
+		[]byte("variable \"project_name\" {\n  default = \"from-variables\"\n}\n"), 0o600))
+
+	oldStdin, oldAddrs, oldForce, oldPublicIP := stdin, interfaceAddrs, initConfigForce, initConfigPublicIP
+	t.Cleanup(func() {
+		stdin, interfaceAddrs, initConfigForce, initConfigPublicIP = oldStdin, oldAddrs, oldForce, oldPublicIP
+	})
+
+	stdin = strings.NewReader(input)
+	interfaceAddrs = func() ([]net.Addr, error) {
+		result := []net.Addr{}
+
+		for _, a := range addrs {
+			ip, ipNet, err := net.ParseCIDR(a)
+			must(t, err)
+
+			ipNet.IP = ip
+			result = append(result, ipNet)
+		}
+
+		return result, nil
+	}
+	initConfigForce, initConfigPublicIP = false, ""
+}
+
+func TestRunInitConfig(t *testing.T) {
+	// Not parallel - modifies global config
+
+	tests := []struct {
+		name       string
+		input      string
+		addrs      []string
+		publicIP   string
+		wantTFVars []string
+	}{
+		{
+			name: "defaults - public IP detected",
+			// project, control plane, worker, zone, CIDR, access key, secret key
+			input: "\n\n\n\n\nAKIATEST\nsecretTEST\n",
+			addrs: []string{"192.168.1.20/24", "203.0.113.7/24"},
+			wantTFVars: []string{
+				`project_name = "from-variables"`, `control_plane_flavor = "DEV1-M"`,
+				`allowed_ssh_cidr = "203.0.113.7/32"`, `scaleway_region = "fr-par"`, `scaleway_zone = "fr-par-1"`,
+			},
+		},
+		{
+			name:       "behind NAT - --public-ip proposed",
+			input:      "\n\n\n\n\nAKIATEST\nsecretTEST\n",
+			addrs:      []string{"192.168.1.20/24"},
+			publicIP:   "198.51.100.4",
+			wantTFVars: []string{`allowed_ssh_cidr = "198.51.100.4/32"`},
+		},
+		{
+			name: "invalid answers - asked again",
+			input: "My Lab\nmy-lab\n\ngp1-xs\nGP1-XS\nus-east-1\nnl-ams-1\n10.0.0.1\n10.0.0.0/8\n" +
+				"\nAKIATEST\nsecretTEST\n",
+			wantTFVars: []string{
+				`project_name = "my-lab"`, `worker_flavor = "GP1-XS"`,
+				`allowed_ssh_cidr = "10.0.0.0/8"`, `scaleway_region = "nl-ams"`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupInitConfig(t, tt.input, tt.addrs...)
+			initConfigPublicIP = tt.publicIP
+
+			must(t, runInitConfig(nil, nil))
+
+			tfvars, err := os.ReadFile(filepath.Join(config.TerraformDir, "terraform.tfvars"))
+			must(t, err)
+
+			for _, want := range tt.wantTFVars {
+				if !strings.Contains(strings.Join(strings.Fields(string(tfvars)), " "), want) {
+					t.Errorf("terraform.tfvars missing %s:\n%s", want, tfvars)
+				}
+			}
+
+			backend, err := os.ReadFile(config.CredentialsFile)
+			must(t, err)
+
+			if !strings.Contains(string(backend), "secret_key: secretTEST") {
+				t.Errorf("backend.yaml missing the secret key:\n%s", backend)
+			}
+		})
+	}
+}
+
+func TestRunInitConfigExistingFiles(t *testing.T) {
+	// Not parallel - modifies global config
+
+	setupInitConfig(t, "")
+	must(t, os.WriteFile(config.CredentialsFile, []byte("access_key: old\n"), 0o600))
+
+	// Refused before any prompt (stdin is empty).
+	if err := runInitConfig(nil, nil); !errors.Is(err, labconfig.ErrFileExists) {
+		t.Fatalf("runInitConfig() error = %v, want %v", err, labconfig.ErrFileExists)
+	}
+
+	// --force asks and overwrites.
+	initConfigForce = true
+	stdin = strings.NewReader("\n\n\n\n\nAKIANEW\nsecretNEW\n")
+
+	must(t, runInitConfig(nil, nil))
+
+	backend, err := os.ReadFile(config.CredentialsFile)
+	must(t, err)
+
+	if !strings.Contains(string(backend), "AKIANEW") {
+		t.Errorf("backend.yaml not overwritten:\n%s", backend)
+	}
+}
+
+func TestRunInitConfigInputClosed(t *testing.T) {
+	// Not parallel - modifies global config
+
+	setupInitConfig(t, "\n\n\n\n\n") // no credentials
+
+	if err := runInitConfig(nil, nil); !errors.Is(err, errNoAnswer) {
+		t.Fatalf("runInitConfig() error = %v, want %v", err, errNoAnswer)
+	}
+
+	if _, err := os.Stat(filepath.Join(config.TerraformDir, "terraform.tfvars")); !os.IsNotExist(err) {
+		t.Errorf("terraform.tfvars written despite the missing answers: %v", err)
+	}
+}
+
+func TestRunInitConfigFrench(t *testing.T) {
+	// Not parallel - modifies global config and the message language
+
+	setupInitConfig(t, "My Lab\nmy-lab\n\n\n\n\nAKIATEST\nsecretTEST\n")
+	config.Quiet = false
+
+	i18n.Set(i18n.French)
+	t.Cleanup(func() { i18n.Set(i18n.English) })
+
+	must(t, runInitConfig(nil, nil))
+
+	out := stdout.(*bytes.Buffer).String()
+	for _, want := range []string{
+		"CONFIGURATION K8S-LAB",
+		"Nom du projet [from-variables]: ",
+		"Type d'instance du worker [DEV1-M]: ",
+		"Nom du projet: utilisez des minuscules",
+		"Clé secrète S3: ",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,253 @@
+// Package labconfig writes the configuration files of a new lab: terraform.tfvars
+// (Terraform variables) and backend.yaml (S3 backend credentials).
+//
+// It is used by the init-config wizard; validation helpers are exported so that
+// the prompts can re-ask until the input is valid.
+package labconfig
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/k8s-lab/get-cluster-info/clusterinfo"
+	"github.com/zclconf/go-cty/cty"
+	"gopkg.in/yaml.v3"
+)
+
+// =============================================================================
+// Types
+// =============================================================================
+
+// BackendFile is the name of the backend credentials file written by Write.
+const BackendFile = "backend.yaml"
+
+// Headers of the generated files (the Terraform files of the repo are commented in French).
+const (
+	tfvarsHeader = "# Généré par get-cluster-info init-config\n" +
+		"# Voir terraform.tfvars.example pour le détail des variables\n\n"
+	backendHeader = "# Généré par get-cluster-info init-config\n" +
+		"# IMPORTANT : ce fichier contient des secrets, il est exclu du git via .gitignore\n\n"
+)
+
+// filePermissions is used for both files: backend.yaml holds secrets, and
+// terraform.tfvars may (see terraform.tfvars.example).
+const filePermissions = 0o600
+
+// Zones are the Scaleway zones offering instances.
+var Zones = []string{
+	"fr-par-1", "fr-par-2", "fr-par-3",
+	"nl-ams-1", "nl-ams-2", "nl-ams-3",
+	"pl-waw-1", "pl-waw-2", "pl-waw-3",
+}
+
+var (
+	ErrFileExists    = errors.New("file already exists (use --force to overwrite)")
+	ErrInvalidName   = errors.New("use lowercase letters, digits and dashes, starting with a letter")
+	ErrInvalidFlavor = errors.New("not a Scaleway instance type (e.g. DEV1-M)")
+	ErrUnknownZone   = errors.New("unknown zone")
+	ErrEmptyValue    = errors.New("value is required")
+
+	projectNameRegexp = regexp.MustCompile(`^[a-z][a-z0-9-]{0,62}$`)
+	flavorRegexp      = regexp.MustCompile(`^[A-Z0-9]+(-[A-Z0-9]+)+$`)
+	sharedAddressNet  = netip.MustParsePrefix("100.64.0.0/10") // carrier-grade NAT
+)
+
+// Config holds the answers of the wizard.
+type Config struct {
+	ProjectName        string
+	ControlPlaneFlavor string
+	WorkerFlavor       string
+	Zone               string
+	AllowedSSHCIDR     string
+	Credentials        clusterinfo.Credentials
+}
+
+// Region returns the region of the zone (fr-par-1 → fr-par).
+func (c Config) Region() string {
+	if i := strings.LastIndexByte(c.Zone, '-'); i > 0 {
+		return c.Zone[:i]
+	}
+
+	return c.Zone
+}
+
+// =============================================================================
+// Validation
+// =============================================================================
+
+// ValidateProjectName checks a resource name prefix.
+func ValidateProjectName(s string) error {
+	if !projectNameRegexp.MatchString(s) {
+		return ErrInvalidName
+	}
+
+	return nil
+}
+
+// ValidateFlavor checks the shape of an instance type (DEV1-M, GP1-XS, PRO2-S...).
+func ValidateFlavor(s string) error {
+	if !flavorRegexp.MatchString(s) {
+		return ErrInvalidFlavor
+	}
+
+	return nil
+}
+
+// ValidateZone checks that the zone is one of Zones.
+func ValidateZone(s string) error {
+	if !slices.Contains(Zones, s) {
+		return fmt.Errorf("%w %q (one of %s)", ErrUnknownZone, s, strings.Join(Zones, ", "))
+	}
+
+	return nil
+}
+
+// ValidateCIDR checks an IPv4 or IPv6 prefix such as 1.2.3.4/32.
+func ValidateCIDR(s string) error {
+	if _, err := netip.ParsePrefix(s); err != nil {
+		return fmt.Errorf("not a valid CIDR (e.g. 1.2.3.4/32): %w", err)
+	}
+
+	return nil
+}
+
+// ValidateNotEmpty rejects empty answers (e.g. credentials).
+func ValidateNotEmpty(s string) error {
+	if strings.TrimSpace(s) == "" {
+		return ErrEmptyValue
+	}
+
+	return nil
+}
+
+// =============================================================================
+// Public IP detection
+// =============================================================================
+
+// DetectPublicIP returns the first public IPv4 address among addrs (see net.InterfaceAddrs).
+// Behind a NAT box, no local interface has a public address and ok is false.
+func DetectPublicIP(addrs []net.Addr) (netip.Addr, bool) {
+	for _, a := range addrs {
+		ipNet, isIPNet := a.(*net.IPNet)
+		if !isIPNet {
+			continue
+		}
+
+		ip, valid := netip.AddrFromSlice(ipNet.IP)
+		if !valid {
+			continue
+		}
+
+		ip = ip.Unmap()
+		if ip.Is4() && ip.IsGlobalUnicast() && !ip.IsPrivate() && !sharedAddressNet.Contains(ip) {
+			return ip, true
+		}
+	}
+
+	return netip.Addr{}, false
+}
+
+// HostCIDR returns the single-address prefix of ip (1.2.3.4 → 1.2.3.4/32).
+func HostCIDR(ip netip.Addr) string {
+	return netip.PrefixFrom(ip, ip.BitLen()).String()
+}
+
+// =============================================================================
+// Files
+// =============================================================================
+
+// TFVars renders terraform.tfvars.
+func (c Config) TFVars() []byte {
+	f := hclwrite.NewEmptyFile()
+	body := f.Body()
+
+	body.AppendUnstructuredTokens(hclwrite.Tokens{{
+		Type:  hclsyntax.TokenComment,
+		Bytes: []byte(tfvarsHeader),
+	}})
+
+	body.SetAttributeValue("project_name", cty.StringVal(c.ProjectName))
+	body.AppendNewline()
+	body.SetAttributeValue("control_plane_flavor", cty.StringVal(c.ControlPlaneFlavor))
+	body.SetAttributeValue("worker_flavor", cty.StringVal(c.WorkerFlavor))
+	body.AppendNewline()
+	body.SetAttributeValue("allowed_ssh_cidr", cty.StringVal(c.AllowedSSHCIDR))
+	body.AppendNewline()
+	body.SetAttributeValue("scaleway_region", cty.StringVal(c.Region()))
+	body.SetAttributeValue("scaleway_zone", cty.StringVal(c.Zone))
+
+	return hclwrite.Format(f.Bytes())
+}
+
+// BackendYAML renders backend.yaml.
+func (c Config) BackendYAML() ([]byte, error) {
+	data, err := yaml.Marshal(c.Credentials)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s: %w", BackendFile, err)
+	}
+
+	return append([]byte(backendHeader), data...), nil
+}
+
+// Write writes terraform.tfvars and backend.yaml into dir with 0600 permissions.
+// Existing files are only replaced with force; nothing is written if one of them exists.
+func Write(dir string, c Config, force bool) ([]string, error) {
+	backend, err := c.BackendYAML()
+	if err != nil {
+		return nil, err
+	}
+
+	files := []struct {
+		name string
+		data []byte
+	}{
+		{name: clusterinfo.TFVarsFile, data: c.TFVars()},
+		{name: BackendFile, data: backend},
+	}
+
+	if !force {
+		if err := CheckNotExist(dir); err != nil {
+			return nil, err
+		}
+	}
+
+	written := make([]string, 0, len(files))
+
+	for _, f := range files {
+		path := filepath.Join(dir, f.name)
+
+		if err := os.WriteFile(path, f.data, filePermissions); err != nil {
+			return written, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+
+		// WriteFile keeps the mode of an existing file.
+		if err := os.Chmod(path, filePermissions); err != nil {
+			return written, fmt.Errorf("failed to set permissions of %s: %w", path, err)
+		}
+
+		written = append(written, path)
+	}
+
+	return written, nil
+}
+
+// CheckNotExist returns ErrFileExists if terraform.tfvars or backend.yaml exists in dir.
+func CheckNotExist(dir string) error {
+	for _, name := range []string{clusterinfo.TFVarsFile, BackendFile} {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return fmt.Errorf("%s: %w", path, ErrFileExists)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,273 @@
+package labconfig
+
+import (
+	"errors"
+	"net"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/k8s-lab/get-cluster-info/clusterinfo"
+	"gopkg.in/yaml.v3"
+)
+
+// =============================================================================
+// Validation tests
+// =============================================================================
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		validate func(string) error
+		input    string
+		wantErr  bool
+	}{
+		{name: "project name - ok", validate: ValidateProjectName, input: "k8s-lab"},
+		{name: "project name uppercase - error", validate: ValidateProjectName, input: "K8s", wantErr: true},
+		{name: "project name leading digit - error", validate: ValidateProjectName, input: "8lab", wantErr: true},
+		{name: "flavor - ok", validate: ValidateFlavor, input: "DEV1-M"},
+		{name: "flavor with two dashes - ok", validate: ValidateFlavor, input: "POP2-HM-2C"},
+		{name: "flavor lowercase - error", validate: ValidateFlavor, input: "dev1-m", wantErr: true},
+		{name: "zone - ok", validate: ValidateZone, input: "nl-ams-1"},
+		{name: "unknown zone - error", validate: ValidateZone, input: "us-east-1", wantErr: true},
+		{name: "CIDR - ok", validate: ValidateCIDR, input: "203.0.113.7/32"},
+		{name: "IP without prefix - error", validate: ValidateCIDR, input: "203.0.113.7", wantErr: true},
+		{name: "not empty - ok", validate: ValidateNotEmpty, input: "x"},
+		{name: "blank - error", validate: ValidateNotEmpty, input: "  ", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if err := tt.validate(tt.input); (err != nil) != tt.wantErr {
+				t.Errorf("validate(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRegion(t *testing.T) {
+	t.Parallel()
+
+	if got := (Config{Zone: "pl-waw-2"}).Region(); got != "pl-waw" {
+		t.Errorf("Region() = %q, want pl-waw", got)
+	}
+}
+
+// =============================================================================
+// Public IP detection tests
+// =============================================================================
+
+func TestDetectPublicIP(t *testing.T) {
+	t.Parallel()
+
+	ipNet := func(s string) net.Addr {
+		_, n, err := net.ParseCIDR(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ip, _, _ := net.ParseCIDR(s)
+		n.IP = ip
+
+		return n
+	}
+
+	tests := []struct {
+		name   string
+		addrs  []net.Addr
+		want   string
+		wantOK bool
+	}{
+		{name: "no addresses", addrs: nil},
+		{
+			name:  "private and loopback only - behind NAT",
+			addrs: []net.Addr{ipNet("127.0.0.1/8"), ipNet("192.168.1.20/24"), ipNet("fe80::1/64")},
+		},
+		{name: "carrier-grade NAT - not public", addrs: []net.Addr{ipNet("100.72.3.4/10")}},
+		{
+			name:   "public address after a private one",
+			addrs:  []net.Addr{ipNet("10.0.0.5/24"), ipNet("203.0.113.7/24")},
+			want:   "203.0.113.7",
+			wantOK: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := DetectPublicIP(tt.addrs)
+			if ok != tt.wantOK {
+				t.Fatalf("DetectPublicIP() ok = %v, want %v (got %s)", ok, tt.wantOK, got)
+			}
+
+			if ok && got.String() != tt.want {
+				t.Errorf("DetectPublicIP() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHostCIDR(t *testing.T) {
+	t.Parallel()
+
+	if got := HostCIDR(netip.MustParseAddr("203.0.113.7")); got != "203.0.113.7/32" {
+		t.Errorf("HostCIDR() = %q, want 203.0.113.7/32", got)
+	}
+}
+
+// =============================================================================
+// File tests
+// =============================================================================
+
+var testConfig = Config{
+	ProjectName:        "my-lab",
+	ControlPlaneFlavor: "DEV1-L",
+	WorkerFlavor:       "DEV1-M",
+	Zone:               "nl-ams-1",
+	AllowedSSHCIDR:     "203.0.113.7/32",
+	Credentials:        clusterinfo.Credentials{AccessKey: "SCWACCESS", SecretKey: "s3cr3t"},
+}
+
+func TestTFVars(t *testing.T) {
+	t.Parallel()
+
+	data := testConfig.TFVars()
+
+	if !strings.HasPrefix(string(data), "# ") {
+		t.Errorf("TFVars() should start with a comment header:\n%s", data)
+	}
+
+	f, diags := hclparse.NewParser().ParseHCL(data, "terraform.tfvars")
+	if diags.HasErrors() {
+		t.Fatalf("TFVars() is not valid HCL: %s\n%s", diags, data)
+	}
+
+	attrs, _ := f.Body.JustAttributes()
+
+	want := map[string]string{
+		"project_name":         "my-lab",
+		"control_plane_flavor": "DEV1-L",
+		"worker_flavor":        "DEV1-M",
+		"allowed_ssh_cidr":     "203.0.113.7/32",
+		"scaleway_region":      "nl-ams",
+		"scaleway_zone":        "nl-ams-1",
+	}
+
+	if len(attrs) != len(want) {
+		t.Errorf("TFVars() has %d attributes, want %d", len(attrs), len(want))
+	}
+
+	for name, value := range want {
+		attr, ok := attrs[name]
+		if !ok {
+			t.Errorf("TFVars() missing %s", name)
+
+			continue
+		}
+
+		v, _ := attr.Expr.Value(nil)
+		if v.AsString() != value {
+			t.Errorf("%s = %q, want %q", name, v.AsString(), value)
+		}
+	}
+}
+
+func TestWrite(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	written, err := Write(dir, testConfig, false)
+	must(t, err)
+
+	if len(written) != 2 {
+		t.Fatalf("Write() = %v, want 2 files", written)
+	}
+
+	for _, path := range written {
+		info, err := os.Stat(path)
+		must(t, err)
+
+		if perm := info.Mode().Perm(); perm != filePermissions {
+			t.Errorf("%s permissions = %04o, want %04o", path, perm, filePermissions)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, BackendFile))
+	must(t, err)
+
+	var creds clusterinfo.Credentials
+	must(t, yaml.Unmarshal(data, &creds))
+
+	if creds != testConfig.Credentials {
+		t.Errorf("backend.yaml credentials = %+v, want %+v", creds, testConfig.Credentials)
+	}
+}
+
+func TestWriteExisting(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		force   bool
+		wantErr error
+	}{
+		{name: "without force - refused", wantErr: ErrFileExists},
+		{name: "with force - overwritten", force: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			backend := filepath.Join(dir, BackendFile)
+			must(t, os.WriteFile(backend, []byte("access_key: old\n"), 0o644))
+
+			_, err := Write(dir, testConfig, tt.force)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Write() error = %v, want %v", err, tt.wantErr)
+			}
+
+			data, err := os.ReadFile(backend)
+			must(t, err)
+
+			if overwritten := !strings.Contains(string(data), "old"); overwritten != tt.force {
+				t.Errorf("backend.yaml overwritten = %v, want %v", overwritten, tt.force)
+			}
+
+			// Nothing is written when refused.
+			_, statErr := os.Stat(filepath.Join(dir, clusterinfo.TFVarsFile))
+			if exists := statErr == nil; exists != tt.force {
+				t.Errorf("terraform.tfvars exists = %v, want %v", exists, tt.force)
+			}
+
+			info, err := os.Stat(backend)
+			must(t, err)
+
+			if tt.force && info.Mode().Perm() != filePermissions {
+				t.Errorf("permissions after overwrite = %04o, want %04o", info.Mode().Perm(), filePermissions)
+			}
+		})
+	}
+}
+
+// =============================================================================
+// Test helpers
+// =============================================================================
+
+func must(t *testing.T, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -3,6 +3,8 @@
 // =============================================================================
 //
 // Uses tfexec (official HashiCorp library) to interact with Terraform.
+// This command is a thin wrapper: the logic lives in the clusterinfo package
+// (state reading, validation) and the render package (summary, JSON, GitHub).
 //
 // RUN:
 //   cd scripts/terraform/get-cluster-info && go run .
@@ -13,6 +15,52 @@
 //   get-cluster-info -c /path/to/creds.yaml             # Custom credentials file (YAML or JSON)
 //   get-cluster-info --json                             # JSON output
 //   get-cluster-info --no-init                          # Skip terraform init
+//   get-cluster-info --force-init                       # Run terraform init even if up to date
+//   get-cluster-info --lenient                          # Warn instead of failing on invalid outputs
+//   get-cluster-info --output-map worker.public_ip=w_ip # Custom Terraform output names
+//   get-cluster-info --discover                         # Infer nodes from the nodes output or <name>_public_ip pairs
+//   get-cluster-info --verbose                          # Show init / fetch / render timings
+//   get-cluster-info --plain                            # Plain text: no box, emoji or colors
+//   get-cluster-info --color=never                      # Colors: auto (TTY, NO_COLOR), always, never
+//   get-cluster-info --lang fr                          # Messages in French (default: from LANG)
+//   get-cluster-info --watch[=10s] [--on-change CMD]    # Re-render whenever the cluster changes
+//   get-cluster-info outputs                            # List every Terraform output
+//   get-cluster-info outputs --show-sensitive           # ... including sensitive values
+//   get-cluster-info doctor                             # Check prerequisites, with fix hints
+//   get-cluster-info init-config                        # Create terraform.tfvars and backend.yaml
+//   get-cluster-info audit                              # Flag admin ports open to the world
+//   get-cluster-info plan                               # Summarize terraform plan by resource type
+//   get-cluster-info drift                              # Changes made outside Terraform (exit 2)
+//   get-cluster-info ttl set 8h                         # Lab lifetime, stored next to the state
+//   get-cluster-info reap                               # Destroy the lab once its TTL has expired
+//   get-cluster-info cost                               # Hourly, monthly and accumulated cost
+//   get-cluster-info bootstrap                          # Install Kubernetes on the nodes over SSH
+//   get-cluster-info cni install cilium                 # Install Cilium, wait for its agents
+//   get-cluster-info node join worker-2                 # Join a worker added to local.nodes
+//   get-cluster-info etcd backup [--s3]                 # Snapshot etcd into a dated directory
+//   get-cluster-info etcd restore <dir|s3://...>        # Restore etcd from a checked snapshot
+//   get-cluster-info support-bundle                     # Logs of every node, secrets scrubbed
+//   get-cluster-info dashboard                          # Live view: SSH, load, memory, kubelet
+//
+// EXIT CODES:
+//   0  success (drift: no drift)
+//   1  error
+//   2  drift detected (drift subcommand)
+//   3  prerequisites: terraform missing, terraform directory not found, doctor failed
+//   4  credentials: credentials file missing, unreadable or incomplete
+//   5  backend: the S3 backend or a terraform command (init, output, show, plan, destroy) failed
+//   6  cluster not deployed: the state has no cluster outputs
+//   7  validation: invalid or missing Terraform outputs (see --lenient)
+//   8  SSH: node unreachable or host key changed
+//   9  usage: invalid flag value or config file (--config, --output-map)
+//
+//   With --json, errors are printed on stderr as a JSON envelope:
+//     {"code":6,"class":"not_deployed","message":"...","hint":"..."}
+//
+// GITHUB ACTIONS:
+//   When GITHUB_OUTPUT / GITHUB_STEP_SUMMARY are set, the node IPs are also
+//   written as step outputs and as a markdown table in the job summary.
+//   Secrets (S3 credentials, SSH private key) are masked with ::add-mask:: (on stderr).
 //
 // =============================================================================
 
@@ -20,18 +68,33 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/term"
 	"github.com/hashicorp/terraform-exec/tfexec"
+	"github.com/k8s-lab/get-cluster-info/audit"
+	"github.com/k8s-lab/get-cluster-info/bootstrap"
+	"github.com/k8s-lab/get-cluster-info/clusterinfo"
+	"github.com/k8s-lab/get-cluster-info/cni"
+	"github.com/k8s-lab/get-cluster-info/doctor"
+	"github.com/k8s-lab/get-cluster-info/etcd"
+	"github.com/k8s-lab/get-cluster-info/i18n"
+	"github.com/k8s-lab/get-cluster-info/labconfig"
+	"github.com/k8s-lab/get-cluster-info/remote"
+	"github.com/k8s-lab/get-cluster-info/render"
+	"github.com/k8s-lab/get-cluster-info/tfplan"
+	"github.com/muesli/termenv"
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v3"
 )
 
 // =============================================================================
@@ -39,109 +102,65 @@ import (
 // =============================================================================
 
 const (
-	// Style constants
-	stylePaddingH = 2
-	stylePaddingV = 1
-	labelWidth    = 14
-
 	// File permissions
 	dirPermissions  = 0o700
 	filePermissions = 0o600
 )
 
-// =============================================================================
-// Lip Gloss Styles
-// =============================================================================
-
-var (
-	// Colors
-	cyan   = lipgloss.Color("86")
-	green  = lipgloss.Color("42")
-	yellow = lipgloss.Color("214")
-	red    = lipgloss.Color("196")
-	blue   = lipgloss.Color("39")
-
-	// Log styles
-	infoIcon    = lipgloss.NewStyle().Foreground(blue).Render("ℹ")
-	successIcon = lipgloss.NewStyle().Foreground(green).Render("✓")
-	warnIcon    = lipgloss.NewStyle().Foreground(yellow).Render("⚠")
-	errorIcon   = lipgloss.NewStyle().Foreground(red).Render("✗")
-
-	// Summary styles
-	titleStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("15")).
-			Background(lipgloss.Color("62")).
-			Padding(0, stylePaddingH).
-			MarginBottom(1)
-
-	sectionStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(cyan).
-			MarginTop(1)
-
-	labelStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("245")).
-			Width(labelWidth)
-
-	valueStyle = lipgloss.NewStyle().
-			Foreground(green).
-			Bold(true)
-
-	pathStyle = lipgloss.NewStyle().
-			Foreground(yellow)
-
-	cmdStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("252")).
-			Background(lipgloss.Color("236")).
-			Padding(0, 1)
-
-	boxStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("62")).
-			Padding(stylePaddingV, stylePaddingH).
-			MarginTop(1)
+// --color values
+const (
+	colorAuto   = "auto"
+	colorAlways = "always"
+	colorNever  = "never"
 )
 
 // =============================================================================
 // Types
 // =============================================================================
 
-// Credentials holds S3 credentials from the backend file (YAML or JSON).
-// Tags use snake_case to match the expected file format.
-type Credentials struct {
-	AccessKey string `json:"access_key" yaml:"access_key"` //nolint:tagliatelle
-	SecretKey string `json:"secret_key" yaml:"secret_key"` //nolint:tagliatelle
-}
-
-// ClusterInfo contains cluster information.
-// JSON tags use snake_case for consistency with Terraform outputs.
-type ClusterInfo struct {
-	ControlPlane NodeInfo `json:"control_plane"` //nolint:tagliatelle
-	Worker       NodeInfo `json:"worker"`
-	SSHKeyPath   string   `json:"ssh_key_path"` //nolint:tagliatelle
-}
-
-// NodeInfo contains node IP addresses.
-// JSON tags use snake_case for consistency with Terraform outputs.
-type NodeInfo struct {
-	PublicIP  string `json:"public_ip"`  //nolint:tagliatelle
-	PrivateIP string `json:"private_ip"` //nolint:tagliatelle
-}
-
 // Config holds global configuration.
 type Config struct {
 	TerraformDir    string
 	CredentialsFile string
+	ConfigFile      string
 	SSHKeyPath      string
+	OutputMap       map[string]string
 	JSONOutput      bool
 	NoInit          bool
+	ForceInit       bool
 	NoSaveKey       bool
+	Lenient         bool
+	Discover        bool
 	Quiet           bool
+	Verbose         bool
+	Watch           time.Duration
+	OnChange        string
+	Color           string
+	Plain           bool
+	Lang            string
 }
 
 var config Config
 
+// Test hooks (see main_test.go).
+var (
+	// terraformClient replaces the tfexec client when set (e.g. clusterinfotest.FakeTerraform).
+	terraformClient clusterinfo.TerraformClient
+	// stdout receives the rendered output and the log messages.
+	stdout io.Writer = os.Stdout
+	// stderr receives errors and --verbose timings.
+	stderr io.Writer = os.Stderr
+	// doctorLookPath and doctorExec replace the system calls of the doctor checks (nil: real system).
+	doctorLookPath func(file string) (string, error)
+	doctorExec     func(ctx context.Context, name string, args ...string) ([]byte, error)
+	// lifetimeStore replaces the S3 lifetime store when set (e.g. clusterinfotest.MemoryLifetimeStore).
+	lifetimeStore clusterinfo.LifetimeStore
+	// now returns the current time, for the lab TTL.
+	now = time.Now
+	// remoteDialer replaces the SSH connections to the nodes when set (e.g. remotetest.FakeDialer).
+	remoteDialer remote.Dialer
+)
+
 // =============================================================================
 // Cobra Commands
 // =============================================================================
@@ -167,60 +186,238 @@ Examples:
   # JSON output for scripting
   get-cluster-info --json
 
-  # Skip terraform init (if already initialized)
-  get-cluster-info --no-init`,
+  # Watch a terraform apply running in another terminal (JSON lines with --json)
+  get-cluster-info --watch
+  get-cluster-info --watch=10s --json --on-change 'jq -r .control_plane.public_ip'
+
+  # Plain text for CI logs, or no colors only
+  get-cluster-info --plain
+  get-cluster-info --color=never
+
+  # Messages in French (also selected by LANG=fr_FR.UTF-8)
+  get-cluster-info --lang fr
+
+  # Skip terraform init (by default it only runs when .terraform is out of date)
+  get-cluster-info --no-init
+
+  # List every Terraform output (sensitive values masked)
+  get-cluster-info outputs
+
+  # Check the prerequisites
+  get-cluster-info doctor
+
+  # Create terraform.tfvars and backend.yaml interactively
+  get-cluster-info init-config
+
+  # Audit the network exposure (fails on admin ports open to the world)
+  get-cluster-info audit
+
+  # Summarize the pending Terraform changes
+  get-cluster-info plan
+
+  # Detect changes made outside Terraform (exit code 2 on drift)
+  get-cluster-info drift
+
+  # Give the lab 8 hours, then destroy it from cron once expired
+  get-cluster-info ttl set 8h
+  get-cluster-info reap
+
+  # Estimate what the lab costs (hourly, monthly, since creation)
+  get-cluster-info cost
+
+  # Install Kubernetes on the nodes over SSH (kubeadm init, then join)
+  get-cluster-info bootstrap
+
+  # Install Cilium with helm and wait until every node has a ready agent
+  get-cluster-info cni install cilium
+
+  # Join a worker added to local.nodes (and applied), wait until it is Ready
+  get-cluster-info node join worker-2
+
+  # Snapshot etcd into ./etcd-backups/<date>/ (or the state bucket with --s3), then restore it
+  get-cluster-info etcd backup
+  get-cluster-info etcd restore etcd-backups/2025-03-01T120000Z
+
+  # Collect the logs of every node into a tar.gz to attach to a bug report
+  get-cluster-info support-bundle
+
+  # Live dashboard of the nodes, with keys to SSH, copy IPs and tail logs
+  get-cluster-info dashboard`,
 	SilenceUsage:  true,
 	SilenceErrors: true,
-	RunE:          run,
+	PersistentPreRunE: func(_ *cobra.Command, _ []string) error {
+		if err := applyLanguage(); err != nil {
+			return err
+		}
+
+		return applyOutputStyle()
+	},
+	RunE: run,
 }
 
 func init() {
-	// Flags
-	rootCmd.Flags().StringVarP(&config.TerraformDir, "terraform-dir", "t", "",
+	rootCmd.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
+		return withClass(errUsage, err)
+	})
+
+	// Flags shared by all subcommands
+	rootCmd.PersistentFlags().StringVarP(&config.TerraformDir, "terraform-dir", "t", "",
 		"Directory containing Terraform files (default: auto-detect)")
 
-	rootCmd.Flags().StringVarP(&config.CredentialsFile, "credentials", "c", "",
+	rootCmd.PersistentFlags().StringVarP(&config.CredentialsFile, "credentials", "c", "",
 		"Path to credentials file (default: <terraform-dir>/backend.yaml or backend.json)")
 
-	rootCmd.Flags().StringVarP(&config.SSHKeyPath, "ssh-key", "k", "",
-		"Path where to save the SSH key (default: ~/.ssh/k8s-lab.pem)")
+	rootCmd.PersistentFlags().StringVar(&config.ConfigFile, "config", "",
+		"Path to the tool config file (default: <terraform-dir>/"+clusterinfo.ConfigFileName+" if present)")
+
+	rootCmd.PersistentFlags().StringToStringVar(&config.OutputMap, "output-map", nil,
+		"Terraform output names, e.g. control_plane.public_ip=cp_ip (repeatable)")
+
+	rootCmd.PersistentFlags().BoolVar(&config.Discover, "discover", false,
+		"Infer nodes from the nodes output, else from <name>_public_ip / <name>_private_ip output pairs")
 
-	rootCmd.Flags().BoolVarP(&config.JSONOutput, "json", "j", false,
+	rootCmd.PersistentFlags().BoolVarP(&config.JSONOutput, "json", "j", false,
 		"Output in JSON format")
 
-	rootCmd.Flags().BoolVar(&config.NoInit, "no-init", false,
-		"Skip Terraform initialization (useful if already initialized)")
+	rootCmd.PersistentFlags().BoolVar(&config.NoInit, "no-init", false,
+		"Skip Terraform initialization (by default init only runs when .terraform is out of date)")
+
+	rootCmd.PersistentFlags().BoolVar(&config.ForceInit, "force-init", false,
+		"Always run terraform init, even if .terraform is up to date")
+
+	rootCmd.PersistentFlags().BoolVarP(&config.Quiet, "quiet", "q", false,
+		"Quiet mode (less output)")
+
+	rootCmd.PersistentFlags().BoolVarP(&config.Verbose, "verbose", "v", false,
+		"Show how long terraform init, the state fetch and the rendering take (on stderr)")
+
+	rootCmd.PersistentFlags().StringVar(&config.Color, "color", colorAuto,
+		"Colorize the output: auto (only on a terminal, unless NO_COLOR is set), always or never")
+
+	rootCmd.PersistentFlags().BoolVar(&config.Plain, "plain", false,
+		"Plain text output of the summary and the reports: no box, emoji, Unicode symbols or colors (for CI logs)")
+
+	rootCmd.PersistentFlags().StringVar(&config.Lang, "lang", "",
+		"Language of the messages: en or fr (default: from LC_ALL, LC_MESSAGES or LANG)")
+
+	// Flags specific to the cluster summary (root command)
+	rootCmd.Flags().StringVarP(&config.SSHKeyPath, "ssh-key", "k", "",
+		"Path where to save the SSH key (default: ~/.ssh/k8s-lab.pem)")
 
 	rootCmd.Flags().BoolVar(&config.NoSaveKey, "no-save-key", false,
 		"Do not save SSH key to disk")
 
-	rootCmd.Flags().BoolVarP(&config.Quiet, "quiet", "q", false,
-		"Quiet mode (less output)")
+	rootCmd.Flags().BoolVar(&config.Lenient, "lenient", false,
+		"Report invalid or missing outputs as warnings instead of failing")
+
+	rootCmd.Flags().DurationVar(&config.Watch, "watch", 0,
+		"Poll the state at this interval, re-render when the cluster changes (--watch alone: "+
+			defaultWatchInterval.String()+")")
+	rootCmd.Flags().Lookup("watch").NoOptDefVal = defaultWatchInterval.String()
+
+	rootCmd.Flags().StringVar(&config.OnChange, "on-change", "",
+		"Command run with sh on every change in --watch mode, the cluster info JSON on stdin")
 }
 
+// Exit codes (see EXIT CODES above).
+const (
+	exitError         = 1
+	exitDrift         = 2
+	exitPrerequisites = 3
+	exitCredentials   = 4
+	exitBackend       = 5
+	exitNotDeployed   = 6
+	exitValidation    = 7
+	exitSSH           = 8
+	exitUsage         = 9
+)
+
 func main() {
 	if err := rootCmd.Execute(); err != nil {
-		logError("%v", err)
-		os.Exit(1)
+		printError(err)
+		os.Exit(exitCode(err))
+	}
+}
+
+// translatedErrors are the sentinel errors of the packages whose message is
+// translated when main prints an error wrapping them.
+var translatedErrors = []error{
+	clusterinfo.ErrNotDeployed,
+	clusterinfo.ErrTerraformNotFound,
+	clusterinfo.ErrCredentialsIncomplete,
+	clusterinfo.ErrNoS3Backend,
+	clusterinfo.ErrInvalidTTL,
+	clusterinfo.ErrOutputMissing,
+	clusterinfo.ErrOutputNotString,
+	clusterinfo.ErrOutputNotNodes,
+	clusterinfo.ErrOutputEmpty,
+	clusterinfo.ErrInvalidIP,
+	clusterinfo.ErrOutsideNetwork,
+	clusterinfo.ErrNoControlPlane,
+	tfplan.ErrDrift,
+	doctor.ErrChecksFailed,
+	audit.ErrFindings,
+	bootstrap.ErrStep,
+	bootstrap.ErrUnknownNode,
+	bootstrap.ErrNotWorker,
+	bootstrap.ErrNodeNotReady,
+	cni.ErrNotReady,
+	etcd.ErrNoEtcd,
+	etcd.ErrCorruptSnapshot,
+	etcd.ErrChecksumMismatch,
+	etcd.ErrAPIServer,
+	etcd.ErrNoSnapshot,
+	remote.ErrHostKeyChanged,
+	labconfig.ErrFileExists,
+	labconfig.ErrInvalidName,
+	labconfig.ErrInvalidFlavor,
+	labconfig.ErrUnknownZone,
+	labconfig.ErrEmptyValue,
+	errNoAnswer,
+}
+
+// localizeError returns the message of err, with the messages of the translatedErrors it wraps translated.
+func localizeError(err error) string {
+	msg := err.Error()
+
+	for _, sentinel := range translatedErrors {
+		if errors.Is(err, sentinel) {
+			msg = strings.ReplaceAll(msg, sentinel.Error(), i18n.T(sentinel.Error()))
+		}
 	}
+
+	return msg
 }
 
 // =============================================================================
 // Main Logic
 // =============================================================================
 
-func run(_ *cobra.Command, _ []string) error {
+func run(cmd *cobra.Command, _ []string) error {
 	ctx := context.Background()
 
-	tf, err := setupEnvironment(ctx)
+	if err := checkWatchInterval(cmd); err != nil {
+		return err
+	}
+
+	src, err := setupEnvironment(ctx)
 	if err != nil {
 		return err
 	}
 
-	return executeAndDisplay(ctx, tf)
+	defer func() { _ = src.Close() }()
+
+	if config.Watch > 0 {
+		ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		return watch(ctx, src)
+	}
+
+	return executeAndDisplay(ctx, src)
 }
 
-func setupEnvironment(ctx context.Context) (*tfexec.Terraform, error) {
+func setupEnvironment(ctx context.Context) (*clusterinfo.TerraformSource, error) {
 	if err := resolveDefaults(); err != nil {
 		return nil, err
 	}
@@ -231,337 +428,312 @@ func setupEnvironment(ctx context.Context) (*tfexec.Terraform, error) {
 
 	creds, err := loadCredentials()
 	if err != nil {
-		return nil, err
+		return nil, withClass(errCredentials, err)
 	}
 
-	tf, err := setupTerraform(creds)
+	start := time.Now()
+
+	src, err := clusterinfo.NewTerraformSource(ctx, clusterinfo.TerraformConfig{
+		Dir:         config.TerraformDir,
+		Client:      terraformClient,
+		Credentials: clusterinfo.StaticCredentials(*creds),
+		SkipInit:    config.NoInit,
+		ForceInit:   config.ForceInit,
+		Logger:      cliLogger{},
+	})
 	if err != nil {
-		return nil, err
+		return nil, withClass(errBackend, err)
 	}
 
 	if !config.NoInit {
-		if err := initTerraform(ctx, tf); err != nil {
-			return nil, err
-		}
+		logDuration("init", start)
 	}
 
-	return tf, nil
+	return src, nil
 }
 
-func executeAndDisplay(ctx context.Context, tf *tfexec.Terraform) error {
-	info, err := getClusterInfo(ctx, tf)
+// executeAndDisplay reads the state once, then feeds that snapshot to every
+// consumer: cluster info and validation, SSH key, renderers.
+func executeAndDisplay(ctx context.Context, src clusterinfo.StateSource) error {
+	src = clusterinfo.NewCachedSource(timedSource{src})
+
+	info, err := loadClusterInfo(ctx, src)
 	if err != nil {
 		return err
 	}
 
 	if !config.NoSaveKey {
-		if err := saveSSHKey(ctx, tf); err != nil {
+		if err := saveSSHKey(ctx, src); err != nil {
 			logWarning("Failed to save SSH key: %v", err)
 		}
 	}
 
+	info.Lifetime = readLifetime(ctx)
+
+	var renderer render.Renderer = summaryRenderer()
 	if config.JSONOutput {
-		printJSON(info)
-	} else {
-		printSummary(info)
+		renderer = render.JSON{}
+	}
+
+	start := time.Now()
+	defer logDuration("render", start)
+
+	if err := renderer.Render(stdout, info); err != nil {
+		return err
+	}
+
+	if render.RunningInGitHubActions() {
+		if err := render.WriteGitHubActions(info); err != nil {
+			logWarning("Failed to write GitHub Actions outputs: %v", err)
+		}
 	}
 
 	return nil
 }
 
+// summaryRenderer returns the renderer of the summary: the box, or plain text with --plain.
+func summaryRenderer() render.Renderer {
+	if config.Plain {
+		return render.Plain{Now: now}
+	}
+
+	return render.Summary{Now: now}
+}
+
+// loadClusterInfo reads and validates the cluster information from src.
+func loadClusterInfo(ctx context.Context, src clusterinfo.StateSource) (*clusterinfo.ClusterInfo, error) {
+	info, err := clusterinfo.Load(ctx, clusterinfo.Options{
+		TerraformDir: config.TerraformDir,
+		Source:       src,
+		OutputMap:    config.OutputMap,
+		Discover:     config.Discover,
+		Lenient:      config.Lenient,
+		SSHKeyPath:   config.SSHKeyPath,
+		Logger:       cliLogger{},
+	})
+	if err != nil {
+		var verr *clusterinfo.ValidationError
+		if errors.As(err, &verr) {
+			return nil, i18n.Errorf("%w\n\nUse --lenient to display the available information anyway", err)
+		}
+
+		return nil, err
+	}
+
+	return info, nil
+}
+
+// timedSource reports how long fetching the Terraform outputs takes (--verbose).
+type timedSource struct {
+	clusterinfo.StateSource
+}
+
+func (s timedSource) Outputs(ctx context.Context) (map[string]tfexec.OutputMeta, error) {
+	defer logDuration("fetch", time.Now())
+
+	outputs, err := s.StateSource.Outputs(ctx)
+
+	return outputs, withClass(errBackend, err)
+}
+
 func resolveDefaults() error {
 	if config.TerraformDir == "" {
-		projectRoot, err := findProjectRoot()
+		projectRoot, err := clusterinfo.FindProjectRoot("")
 		if err != nil {
-			return fmt.Errorf(
+			return withClass(errPrerequisites, i18n.Errorf(
 				"failed to find terraform directory: %w\n\nUse --terraform-dir to specify it manually",
 				err,
-			)
+			))
 		}
 
 		config.TerraformDir = filepath.Join(projectRoot, "terraform")
 	}
 
 	if _, err := os.Stat(config.TerraformDir); os.IsNotExist(err) {
-		return fmt.Errorf("terraform directory not found: %s", config.TerraformDir)
+		return withClass(errPrerequisites, i18n.Errorf("terraform directory not found: %s", config.TerraformDir))
 	}
 
 	if config.CredentialsFile == "" {
-		config.CredentialsFile = resolveCredentialsFile()
+		config.CredentialsFile = clusterinfo.ResolveCredentialsFile(config.TerraformDir)
 	}
 
 	if config.SSHKeyPath == "" {
 		config.SSHKeyPath = filepath.Join(os.Getenv("HOME"), ".ssh", "k8s-lab.pem")
 	}
 
-	return nil
+	return withClass(errUsage, resolveOutputSettings())
 }
 
-func findProjectRoot() (string, error) {
-	cwd, err := os.Getwd()
-	if err != nil {
-		return "", err
+// resolveOutputSettings merges the output-name mapping and discovery mode from the config file and flags.
+func resolveOutputSettings() error {
+	configFile := config.ConfigFile
+	if configFile == "" {
+		configFile = clusterinfo.ResolveConfigFile(config.TerraformDir)
 	}
 
-	dir := cwd
-	for {
-		if _, err := os.Stat(filepath.Join(dir, "terraform", "main.tf")); err == nil {
-			return dir, nil
-		}
-
-		parent := filepath.Dir(dir)
-		if parent == dir {
-			break
-		}
+	fc, err := clusterinfo.LoadFileConfig(configFile)
+	if err != nil {
+		return err
+	}
 
-		dir = parent
+	mapping, err := clusterinfo.ResolveOutputMap(fc.OutputMap, config.OutputMap)
+	if err != nil {
+		return err
 	}
 
-	return "", fmt.Errorf("could not find terraform/main.tf from %s", cwd)
-}
+	config.OutputMap = mapping
+	config.Discover = config.Discover || fc.Discover
 
-// resolveCredentialsFile returns the path to the first existing backend file (YAML then JSON).
-func resolveCredentialsFile() string {
-	for _, name := range []string{"backend.yaml", "backend.yml", "backend.json"} {
-		p := filepath.Join(config.TerraformDir, name)
-		if _, err := os.Stat(p); err == nil {
-			return p
-		}
-	}
-	return filepath.Join(config.TerraformDir, "backend.yaml")
+	return nil
 }
 
 func checkPrerequisites() error {
-	if _, err := exec.LookPath("terraform"); err != nil {
-		return errors.New("terraform is not installed or not in PATH")
+	if terraformClient == nil {
+		if _, err := exec.LookPath("terraform"); err != nil {
+			return clusterinfo.ErrTerraformNotFound
+		}
 	}
 
 	if _, err := os.Stat(config.CredentialsFile); os.IsNotExist(err) {
-		return fmt.Errorf(
+		return withClass(errCredentials, i18n.Errorf(
 			"credentials file not found: %s\n\n"+
 				"Create the file with your S3 credentials:\n"+
 				"  cp %s/backend.yaml.example %s   # or backend.json.example → backend.json",
 			config.CredentialsFile, config.TerraformDir, config.CredentialsFile,
-		)
+		))
 	}
 
 	return nil
 }
 
-func loadCredentials() (*Credentials, error) {
-	logInfo("Loading credentials from %s", pathStyle.Render(config.CredentialsFile))
+func loadCredentials() (*clusterinfo.Credentials, error) {
+	logInfo("Loading credentials from %s", render.PathStyle.Render(config.CredentialsFile))
 
-	data, err := os.ReadFile(config.CredentialsFile)
+	creds, err := clusterinfo.FileCredentials{Path: config.CredentialsFile}.Credentials(context.Background())
 	if err != nil {
-		return nil, fmt.Errorf("failed to read credentials file: %w", err)
-	}
-
-	var creds Credentials
-	ext := strings.ToLower(filepath.Ext(config.CredentialsFile))
-	switch ext {
-	case ".yaml", ".yml":
-		if err := yaml.Unmarshal(data, &creds); err != nil {
-			return nil, fmt.Errorf("failed to parse YAML file: %w", err)
-		}
-	default:
-		if err := json.Unmarshal(data, &creds); err != nil {
-			return nil, fmt.Errorf("failed to parse JSON file: %w", err)
-		}
+		return nil, err
 	}
 
-	if creds.AccessKey == "" || creds.SecretKey == "" {
-		return nil, fmt.Errorf("access_key or secret_key missing in %s", config.CredentialsFile)
+	if render.RunningInGitHubActions() {
+		render.MaskGitHubValue(stderr, creds.AccessKey)
+		render.MaskGitHubValue(stderr, creds.SecretKey)
 	}
 
 	logSuccess("Credentials loaded")
 
-	return &creds, nil
+	return creds, nil
 }
 
-func setupTerraform(creds *Credentials) (*tfexec.Terraform, error) {
-	execPath, err := exec.LookPath("terraform")
-	if err != nil {
-		return nil, fmt.Errorf("terraform not found: %w", err)
-	}
-
-	tf, err := tfexec.NewTerraform(config.TerraformDir, execPath)
+func saveSSHKey(ctx context.Context, src clusterinfo.StateSource) error {
+	key, err := clusterinfo.ReadSSHKey(ctx, src, config.OutputMap[clusterinfo.FieldSSHPrivateKey])
 	if err != nil {
-		return nil, fmt.Errorf("failed to create Terraform instance: %w", err)
+		return err
 	}
 
-	env := map[string]string{
-		"AWS_ACCESS_KEY_ID":     creds.AccessKey,
-		"AWS_SECRET_ACCESS_KEY": creds.SecretKey,
-	}
-	// tfexec replaces the subprocess env, so pass PATH so Terraform can find getent etc.
-	if path := os.Getenv("PATH"); path != "" {
-		env["PATH"] = path
-	} else {
-		env["PATH"] = "/usr/bin:/usr/local/bin:/bin"
-	}
+	if key == "" {
+		logWarning("No SSH key found in outputs")
 
-	openStackVars := []string{
-		"OS_AUTH_URL", "OS_TENANT_ID", "OS_TENANT_NAME",
-		"OS_USERNAME", "OS_PASSWORD", "OS_REGION_NAME",
+		return nil
 	}
 
-	for _, v := range openStackVars {
-		if val := os.Getenv(v); val != "" {
-			env[v] = val
-		}
+	if render.RunningInGitHubActions() {
+		render.MaskGitHubValue(stderr, key)
 	}
 
-	if err := tf.SetEnv(env); err != nil {
-		return nil, fmt.Errorf("failed to configure environment variables: %w", err)
+	sshDir := filepath.Dir(config.SSHKeyPath)
+	if err := os.MkdirAll(sshDir, dirPermissions); err != nil {
+		return i18n.Errorf("failed to create %s: %w", sshDir, err)
 	}
 
-	return tf, nil
-}
-
-func initTerraform(ctx context.Context, tf *tfexec.Terraform) error {
-	logInfo("Initializing Terraform...")
-
-	err := tf.Init(ctx, tfexec.Upgrade(false))
-	if err != nil {
-		return fmt.Errorf("terraform init failed: %w", err)
+	if err := os.WriteFile(config.SSHKeyPath, []byte(key), filePermissions); err != nil {
+		return i18n.Errorf("failed to write SSH key: %w", err)
 	}
 
-	logSuccess("Terraform initialized")
+	logSuccess("SSH key saved: %s", render.PathStyle.Render(config.SSHKeyPath))
 
 	return nil
 }
 
-func getClusterInfo(ctx context.Context, tf *tfexec.Terraform) (*ClusterInfo, error) {
-	logInfo("Retrieving cluster information...")
+// =============================================================================
+// Output style
+// =============================================================================
 
-	outputs, err := tf.Output(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("terraform output failed: %w", err)
-	}
+// applyLanguage selects the language of the messages: --lang, else the locale.
+func applyLanguage() error {
+	if config.Lang == "" {
+		i18n.Set(i18n.Detect(os.Getenv))
 
-	info := &ClusterInfo{
-		SSHKeyPath: config.SSHKeyPath,
+		return nil
 	}
 
-	info.ControlPlane.PublicIP = extractStringOutput(outputs, "control_plane_public_ip")
-	info.ControlPlane.PrivateIP = extractStringOutput(outputs, "control_plane_private_ip")
-	info.Worker.PublicIP = extractStringOutput(outputs, "worker_public_ip")
-	info.Worker.PrivateIP = extractStringOutput(outputs, "worker_private_ip")
-
-	if info.ControlPlane.PublicIP == "" {
-		return nil, errors.New("no data found - the cluster may not be deployed")
+	lang, err := i18n.Parse(config.Lang)
+	if err != nil {
+		return withClass(errUsage, i18n.Errorf("invalid --lang: %w", err))
 	}
 
-	logSuccess("Information retrieved")
+	i18n.Set(lang)
 
-	return info, nil
+	return nil
 }
 
-func extractStringOutput(outputs map[string]tfexec.OutputMeta, key string) string {
-	v, ok := outputs[key]
-	if !ok {
-		return ""
+// applyOutputStyle sets the color profile and the symbols from --color, --plain,
+// NO_COLOR, the terminal and the locale, before any output.
+func applyOutputStyle() error {
+	switch config.Color {
+	case colorAuto, colorAlways, colorNever:
+	default:
+		return withClass(errUsage, i18n.Errorf("invalid --color %q (auto, always or never)", config.Color))
 	}
 
-	var result string
-	if err := json.Unmarshal(v.Value, &result); err != nil {
-		return ""
-	}
+	render.SetASCII(config.Plain || !render.SupportsUnicode(os.Getenv))
+	render.SetPlain(config.Plain)
+	lipgloss.SetColorProfile(colorProfile())
 
-	return result
+	return nil
 }
 
-func saveSSHKey(ctx context.Context, tf *tfexec.Terraform) error {
-	outputs, err := tf.Output(ctx)
-	if err != nil {
-		return err
-	}
-
-	key := extractStringOutput(outputs, "ssh_private_key")
-	if key == "" {
-		logWarning("No SSH key found in outputs")
+// colorProfile returns the color profile matching --color and --plain.
+func colorProfile() termenv.Profile {
+	switch {
+	case config.Plain || config.Color == colorNever:
+		return termenv.Ascii
+	case config.Color == colorAlways:
+		// Not a terminal: guess from TERM / COLORTERM, with at least 16 colors.
+		profile := termenv.NewOutput(os.Stdout, termenv.WithTTY(true), termenv.WithUnsafe()).ColorProfile()
 
-		return nil
-	}
-
-	sshDir := filepath.Dir(config.SSHKeyPath)
-	if err := os.MkdirAll(sshDir, dirPermissions); err != nil {
-		return fmt.Errorf("failed to create %s: %w", sshDir, err)
+		return min(profile, termenv.ANSI)
 	}
 
-	if err := os.WriteFile(config.SSHKeyPath, []byte(key), filePermissions); err != nil {
-		return fmt.Errorf("failed to write SSH key: %w", err)
+	f, ok := stdout.(*os.File)
+	if !ok || !term.IsTerminal(f.Fd()) || os.Getenv("NO_COLOR") != "" {
+		return termenv.Ascii
 	}
 
-	logSuccess("SSH key saved: %s", pathStyle.Render(config.SSHKeyPath))
-
-	return nil
+	return termenv.NewOutput(f).EnvColorProfile()
 }
 
 // =============================================================================
-// Output
+// Logging
 // =============================================================================
 
-func printJSON(info *ClusterInfo) {
-	data, _ := json.MarshalIndent(info, "", "  ")
-	fmt.Println(string(data))
-}
-
-func printSummary(info *ClusterInfo) {
-	fmt.Println()
-
-	fmt.Println(titleStyle.Render("🚀 K8S-LAB CLUSTER"))
-
-	cpSection := sectionStyle.Render("CONTROL-PLANE")
-	cpPublic := labelStyle.Render("Public IP:") + " " + valueStyle.Render(info.ControlPlane.PublicIP)
-	cpPrivate := labelStyle.Render("Private IP:") + " " + info.ControlPlane.PrivateIP
-
-	workerSection := sectionStyle.Render("WORKER")
-	workerPublic := labelStyle.Render("Public IP:") + " " + valueStyle.Render(info.Worker.PublicIP)
-	workerPrivate := labelStyle.Render("Private IP:") + " " + info.Worker.PrivateIP
-
-	sshSection := sectionStyle.Render("SSH CONNECTION")
-	sshKey := labelStyle.Render("Key:") + " " + pathStyle.Render(info.SSHKeyPath)
-
-	cpCmd := cmdStyle.Render(fmt.Sprintf("ssh -i %s ubuntu@%s", info.SSHKeyPath, info.ControlPlane.PublicIP))
-	workerCmd := cmdStyle.Render(fmt.Sprintf("ssh -i %s ubuntu@%s", info.SSHKeyPath, info.Worker.PublicIP))
-
-	content := fmt.Sprintf(`%s
-  %s
-  %s
-
-%s
-  %s
-  %s
+// cliLogger forwards clusterinfo progress messages to the log helpers below.
+type cliLogger struct{}
 
-%s
-  %s
+func (cliLogger) Infof(format string, args ...any)    { logInfo(format, args...) }
+func (cliLogger) Successf(format string, args ...any) { logSuccess(format, args...) }
+func (cliLogger) Warnf(format string, args ...any)    { logWarning(format, args...) }
 
-  Control-plane:
-  %s
-
-  Worker:
-  %s`,
-		cpSection, cpPublic, cpPrivate,
-		workerSection, workerPublic, workerPrivate,
-		sshSection, sshKey,
-		cpCmd, workerCmd,
-	)
-
-	fmt.Println(boxStyle.Render(content))
-	fmt.Println()
+// logIcon renders a log icon with the current symbols and color profile.
+func logIcon(symbol string, color lipgloss.Color) string {
+	return lipgloss.NewStyle().Foreground(color).Render(symbol)
 }
 
-// =============================================================================
-// Logging
-// =============================================================================
-
 func logInfo(format string, args ...any) {
 	if config.Quiet {
 		return
 	}
 
-	fmt.Printf("%s %s\n", infoIcon, fmt.Sprintf(format, args...))
+	fmt.Fprintf(stdout, "%s %s\n", logIcon(render.Sym.Info, render.Blue), i18n.Sprintf(format, args...))
 }
 
 func logSuccess(format string, args ...any) {
@@ -569,13 +741,23 @@ func logSuccess(format string, args ...any) {
 		return
 	}
 
-	fmt.Printf("%s %s\n", successIcon, fmt.Sprintf(format, args...))
+	fmt.Fprintf(stdout, "%s %s\n", logIcon(render.Sym.OK, render.Green), i18n.Sprintf(format, args...))
 }
 
 func logWarning(format string, args ...any) {
-	fmt.Printf("%s %s\n", warnIcon, fmt.Sprintf(format, args...))
+	fmt.Fprintf(stdout, "%s %s\n", logIcon(render.Sym.Warn, render.Yellow), i18n.Sprintf(format, args...))
 }
 
 func logError(format string, args ...any) {
-	fmt.Fprintf(os.Stderr, "%s %s\n", errorIcon, fmt.Sprintf(format, args...))
+	fmt.Fprintf(stderr, "%s %s\n", logIcon(render.Sym.Fail, render.Red), i18n.Sprintf(format, args...))
+}
+
+// logDuration prints the time elapsed since start for a step, with --verbose.
+func logDuration(step string, start time.Time) {
+	if !config.Verbose {
+		return
+	}
+
+	fmt.Fprintf(stderr, "%s %s took %s\n",
+		logIcon(render.Sym.Time, render.Cyan), step, time.Since(start).Round(time.Millisecond))
 }
@@ -1,196 +1,19 @@
 package main
 
 import (
-	"encoding/json"
+	"bytes"
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
 
-	"github.com/hashicorp/terraform-exec/tfexec"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/k8s-lab/get-cluster-info/clusterinfo"
+	"github.com/k8s-lab/get-cluster-info/i18n"
+	"github.com/k8s-lab/get-cluster-info/render"
+	"github.com/muesli/termenv"
 )
 
-// =============================================================================
-// extractStringOutput tests
-// =============================================================================
-
-func TestExtractStringOutput(t *testing.T) {
-	t.Parallel()
-
-	tests := []struct {
-		name     string
-		outputs  map[string]tfexec.OutputMeta
-		key      string
-		expected string
-	}{
-		{
-			name:     "empty outputs - returns empty string",
-			outputs:  map[string]tfexec.OutputMeta{},
-			key:      "some_key",
-			expected: "",
-		},
-		{
-			name: "key not found - returns empty string",
-			outputs: map[string]tfexec.OutputMeta{
-				"other_key": {Value: json.RawMessage(`"value"`)},
-			},
-			key:      "some_key",
-			expected: "",
-		},
-		{
-			name: "valid string value - returns value",
-			outputs: map[string]tfexec.OutputMeta{
-				"ip_address": {Value: json.RawMessage(`"192.168.1.1"`)},
-			},
-			key:      "ip_address",
-			expected: "192.168.1.1",
-		},
-		{
-			name: "invalid json - returns empty string",
-			outputs: map[string]tfexec.OutputMeta{
-				"bad_value": {Value: json.RawMessage(`not valid json`)},
-			},
-			key:      "bad_value",
-			expected: "",
-		},
-		{
-			name: "non-string json value - returns empty string",
-			outputs: map[string]tfexec.OutputMeta{
-				"number": {Value: json.RawMessage(`123`)},
-			},
-			key:      "number",
-			expected: "",
-		},
-		{
-			name: "null json value - returns empty string",
-			outputs: map[string]tfexec.OutputMeta{
-				"null_value": {Value: json.RawMessage(`null`)},
-			},
-			key:      "null_value",
-			expected: "",
-		},
-		{
-			name: "empty string value - returns empty string",
-			outputs: map[string]tfexec.OutputMeta{
-				"empty": {Value: json.RawMessage(`""`)},
-			},
-			key:      "empty",
-			expected: "",
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			t.Parallel()
-
-			result := extractStringOutput(tt.outputs, tt.key)
-			if result != tt.expected {
-				t.Errorf("extractStringOutput() = %q, want %q", result, tt.expected)
-			}
-		})
-	}
-}
-
-// =============================================================================
-// findProjectRoot tests
-// =============================================================================
-
-func TestFindProjectRoot(t *testing.T) {
-	// Not parallel - modifies working directory
-
-	tests := []struct {
-		name        string
-		setupFunc   func(t *testing.T) (tmpDir string, cleanup func())
-		wantErr     bool
-		errContains string
-	}{
-		{
-			name: "finds project root from nested subdirectory",
-			setupFunc: func(t *testing.T) (string, func()) {
-				t.Helper()
-
-				tmpDir := t.TempDir()
-				tmpDir, _ = filepath.EvalSymlinks(tmpDir) // macOS /var -> /private/var
-
-				terraformDir := filepath.Join(tmpDir, "terraform")
-				subDir := filepath.Join(tmpDir, "some", "nested", "dir")
-
-				must(t, os.MkdirAll(terraformDir, 0o755))
-				must(t, os.MkdirAll(subDir, 0o755))
-				must(t, os.WriteFile(filepath.Join(terraformDir, "main.tf"), []byte("# tf"), 0o644))
-
-				originalWd, _ := os.Getwd()
-				must(t, os.Chdir(subDir))
-
-				return tmpDir, func() { _ = os.Chdir(originalWd) }
-			},
-			wantErr: false,
-		},
-		{
-			name: "finds project root from terraform directory itself",
-			setupFunc: func(t *testing.T) (string, func()) {
-				t.Helper()
-
-				tmpDir := t.TempDir()
-				tmpDir, _ = filepath.EvalSymlinks(tmpDir)
-
-				terraformDir := filepath.Join(tmpDir, "terraform")
-
-				must(t, os.MkdirAll(terraformDir, 0o755))
-				must(t, os.WriteFile(filepath.Join(terraformDir, "main.tf"), []byte("# tf"), 0o644))
-
-				originalWd, _ := os.Getwd()
-				must(t, os.Chdir(tmpDir))
-
-				return tmpDir, func() { _ = os.Chdir(originalWd) }
-			},
-			wantErr: false,
-		},
-		{
-			name: "no terraform directory - returns error",
-			setupFunc: func(t *testing.T) (string, func()) {
-				t.Helper()
-
-				tmpDir := t.TempDir()
-				tmpDir, _ = filepath.EvalSymlinks(tmpDir)
-
-				originalWd, _ := os.Getwd()
-				must(t, os.Chdir(tmpDir))
-
-				return "", func() { _ = os.Chdir(originalWd) }
-			},
-			wantErr:     true,
-			errContains: "could not find terraform/main.tf",
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			// Not parallel - modifies working directory
-
-			expectedDir, cleanup := tt.setupFunc(t)
-			t.Cleanup(cleanup)
-
-			got, err := findProjectRoot()
-
-			if tt.wantErr {
-				if err == nil {
-					t.Error("findProjectRoot() expected error, got nil")
-				}
-
-				return
-			}
-
-			if err != nil {
-				t.Errorf("findProjectRoot() unexpected error = %v", err)
-			}
-
-			if got != expectedDir {
-				t.Errorf("findProjectRoot() = %q, want %q", got, expectedDir)
-			}
-		})
-	}
-}
-
 // =============================================================================
 // loadCredentials tests
 // =============================================================================
@@ -435,7 +258,7 @@ func TestResolveDefaults(t *testing.T) {
 
 			// Check default credentials path is set correctly
 			if tt.inputConfig.CredentialsFile == "" && tt.wantCredentialsFile == "" {
-				expectedCredsPath := filepath.Join(terraformDir, "backend.json")
+				expectedCredsPath := filepath.Join(terraformDir, "backend.yaml")
 				if config.CredentialsFile != expectedCredsPath {
 					t.Errorf("CredentialsFile = %q, want %q", config.CredentialsFile, expectedCredsPath)
 				}
@@ -445,231 +268,239 @@ func TestResolveDefaults(t *testing.T) {
 }
 
 // =============================================================================
-// ClusterInfo JSON marshaling tests
+// checkPrerequisites tests
 // =============================================================================
 
-func TestClusterInfoJSON(t *testing.T) {
-	t.Parallel()
+func TestCheckPrerequisites(t *testing.T) {
+	// Not parallel - modifies global config
 
 	tests := []struct {
-		name           string
-		input          ClusterInfo
-		wantFields     []string // Expected field names in JSON
-		wantPublicIP   string
-		wantPrivateIP  string
-		wantSSHKeyPath string
+		name        string
+		setupFunc   func(t *testing.T) string // Returns credentials file path
+		wantErr     bool
+		errContains string
 	}{
 		{
-			name: "marshals with snake_case field names",
-			input: ClusterInfo{
-				ControlPlane: NodeInfo{
-					PublicIP:  "1.2.3.4",
-					PrivateIP: "10.0.0.1",
-				},
-				Worker: NodeInfo{
-					PublicIP:  "5.6.7.8",
-					PrivateIP: "10.0.0.2",
-				},
-				SSHKeyPath: "/home/user/.ssh/key.pem",
+			name: "credentials file exists - no error",
+			setupFunc: func(t *testing.T) string {
+				t.Helper()
+				tmpDir := t.TempDir()
+				credsFile := filepath.Join(tmpDir, "backend.json")
+				must(t, os.WriteFile(credsFile, []byte(`{}`), 0o600))
+
+				return credsFile
 			},
-			wantFields:     []string{"control_plane", "worker", "ssh_key_path"},
-			wantPublicIP:   "1.2.3.4",
-			wantPrivateIP:  "10.0.0.1",
-			wantSSHKeyPath: "/home/user/.ssh/key.pem",
+			wantErr: false,
 		},
 		{
-			name: "handles empty values",
-			input: ClusterInfo{
-				ControlPlane: NodeInfo{},
-				Worker:       NodeInfo{},
-				SSHKeyPath:   "",
+			name: "credentials file missing - returns error",
+			setupFunc: func(t *testing.T) string {
+				t.Helper()
+
+				return "/nonexistent/path/creds.json"
 			},
-			wantFields:     []string{"control_plane", "worker", "ssh_key_path"},
-			wantPublicIP:   "",
-			wantPrivateIP:  "",
-			wantSSHKeyPath: "",
+			wantErr:     true,
+			errContains: "credentials file not found",
 		},
 	}
 
+	// Hermetic: a stub terraform binary on PATH, whether or not the real one is installed.
+	binDir := t.TempDir()
+	must(t, os.WriteFile(filepath.Join(binDir, "terraform"), []byte("#!/bin/sh\n"), 0o755))
+	t.Setenv("PATH", binDir)
+
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			t.Parallel()
+			credsFile := tt.setupFunc(t)
 
-			data, err := json.Marshal(tt.input)
-			if err != nil {
-				t.Fatalf("json.Marshal() error = %v", err)
+			oldConfig := config
+			config = Config{
+				CredentialsFile: credsFile,
+				TerraformDir:    "/some/dir",
 			}
 
-			var result map[string]any
-			if err := json.Unmarshal(data, &result); err != nil {
-				t.Fatalf("json.Unmarshal() error = %v", err)
-			}
+			t.Cleanup(func() { config = oldConfig })
 
-			// Check expected field names
-			for _, field := range tt.wantFields {
-				if _, ok := result[field]; !ok {
-					t.Errorf("expected field %q in JSON output", field)
-				}
-			}
+			err := checkPrerequisites()
 
-			// Check nested fields
-			if cp, ok := result["control_plane"].(map[string]any); ok {
-				if _, ok := cp["public_ip"]; !ok {
-					t.Error("expected 'public_ip' field in control_plane")
+			if tt.wantErr {
+				if err == nil {
+					t.Error("checkPrerequisites() expected error, got nil")
 				}
 
-				if _, ok := cp["private_ip"]; !ok {
-					t.Error("expected 'private_ip' field in control_plane")
-				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("checkPrerequisites() unexpected error = %v", err)
 			}
 		})
 	}
 }
 
 // =============================================================================
-// Credentials JSON tests
+// Test helpers
 // =============================================================================
 
-func TestCredentialsJSON(t *testing.T) {
-	t.Parallel()
+func must(t *testing.T, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// =============================================================================
+// applyOutputStyle tests
+// =============================================================================
+
+func TestApplyOutputStyle(t *testing.T) {
+	// Not parallel - modifies global config and the lipgloss color profile
 
 	tests := []struct {
-		name          string
-		jsonInput     string
-		wantAccessKey string
-		wantSecretKey string
-		wantErr       bool
+		name        string
+		color       string
+		plain       bool
+		noColor     string
+		lang        string
+		wantErr     bool
+		wantProfile termenv.Profile
+		wantASCII   bool
 	}{
-		{
-			name:          "unmarshals valid credentials",
-			jsonInput:     `{"access_key": "AKIA123", "secret_key": "secret456"}`,
-			wantAccessKey: "AKIA123",
-			wantSecretKey: "secret456",
-			wantErr:       false,
-		},
-		{
-			name:          "handles extra fields gracefully",
-			jsonInput:     `{"access_key": "AKIA123", "secret_key": "secret456", "extra": "ignored"}`,
-			wantAccessKey: "AKIA123",
-			wantSecretKey: "secret456",
-			wantErr:       false,
-		},
-		{
-			name:          "handles missing fields as empty strings",
-			jsonInput:     `{}`,
-			wantAccessKey: "",
-			wantSecretKey: "",
-			wantErr:       false,
-		},
+		{name: "auto, not a terminal", color: colorAuto, lang: "en_US.UTF-8", wantProfile: termenv.Ascii},
+		{name: "never", color: colorNever, wantProfile: termenv.Ascii},
+		{name: "always, even with NO_COLOR", color: colorAlways, noColor: "1", wantProfile: termenv.ANSI},
+		{name: "plain wins over always", color: colorAlways, plain: true, wantProfile: termenv.Ascii, wantASCII: true},
+		{name: "locale without UTF-8", color: colorNever, lang: "C", wantProfile: termenv.Ascii, wantASCII: true},
+		{name: "invalid value", color: "yes", wantErr: true},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			t.Parallel()
-
-			var creds Credentials
-
-			err := json.Unmarshal([]byte(tt.jsonInput), &creds)
-
+			t.Setenv("NO_COLOR", tt.noColor)
+			t.Setenv("LC_ALL", "")
+			t.Setenv("LC_CTYPE", "")
+			t.Setenv("LANG", tt.lang)
+			t.Setenv("TERM", "dumb")
+			t.Setenv("COLORTERM", "")
+
+			oldConfig, oldStdout := config, stdout
+			config = Config{Color: tt.color, Plain: tt.plain}
+			stdout = &bytes.Buffer{}
+
+			t.Cleanup(func() {
+				config, stdout = oldConfig, oldStdout
+				lipgloss.SetColorProfile(termenv.Ascii)
+				render.SetASCII(false)
+				render.SetPlain(false)
+			})
+
+			err := applyOutputStyle()
 			if tt.wantErr {
 				if err == nil {
-					t.Error("expected error, got nil")
+					t.Error("applyOutputStyle() expected error, got nil")
 				}
 
 				return
 			}
 
 			if err != nil {
-				t.Errorf("unexpected error = %v", err)
-
-				return
+				t.Fatalf("applyOutputStyle() unexpected error = %v", err)
 			}
 
-			if creds.AccessKey != tt.wantAccessKey {
-				t.Errorf("AccessKey = %q, want %q", creds.AccessKey, tt.wantAccessKey)
+			if got := lipgloss.ColorProfile(); got != tt.wantProfile {
+				t.Errorf("color profile = %v, want %v", got, tt.wantProfile)
 			}
 
-			if creds.SecretKey != tt.wantSecretKey {
-				t.Errorf("SecretKey = %q, want %q", creds.SecretKey, tt.wantSecretKey)
+			if got := render.Sym == render.ASCIISymbols; got != tt.wantASCII {
+				t.Errorf("ASCII symbols = %v, want %v", got, tt.wantASCII)
 			}
 		})
 	}
 }
 
 // =============================================================================
-// checkPrerequisites tests
+// Language tests
 // =============================================================================
 
-func TestCheckPrerequisites(t *testing.T) {
-	// Not parallel - modifies global config
+func TestApplyLanguage(t *testing.T) {
+	// Not parallel - modifies global config and the message language
 
 	tests := []struct {
-		name        string
-		setupFunc   func(t *testing.T) string // Returns credentials file path
-		wantErr     bool
-		errContains string
+		name    string
+		flag    string
+		lang    string // LANG
+		want    i18n.Language
+		wantErr bool
 	}{
-		{
-			name: "credentials file exists - no error",
-			setupFunc: func(t *testing.T) string {
-				t.Helper()
-				tmpDir := t.TempDir()
-				credsFile := filepath.Join(tmpDir, "backend.json")
-				must(t, os.WriteFile(credsFile, []byte(`{}`), 0o600))
-
-				return credsFile
-			},
-			wantErr: false,
-		},
-		{
-			name: "credentials file missing - returns error",
-			setupFunc: func(t *testing.T) string {
-				t.Helper()
-
-				return "/nonexistent/path/creds.json"
-			},
-			wantErr:     true,
-			errContains: "credentials file not found",
-		},
+		{name: "from LANG", lang: "fr_FR.UTF-8", want: i18n.French},
+		{name: "no locale", want: i18n.English},
+		{name: "flag wins over LANG", flag: "en", lang: "fr_FR.UTF-8", want: i18n.English},
+		{name: "flag", flag: "fr", want: i18n.French},
+		{name: "unknown flag value", flag: "de", wantErr: true},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			credsFile := tt.setupFunc(t)
+			t.Setenv("LC_ALL", "")
+			t.Setenv("LC_MESSAGES", "")
+			t.Setenv("LANG", tt.lang)
 
 			oldConfig := config
-			config = Config{
-				CredentialsFile: credsFile,
-				TerraformDir:    "/some/dir",
-			}
+			config = Config{Lang: tt.flag}
 
-			t.Cleanup(func() { config = oldConfig })
-
-			err := checkPrerequisites()
+			t.Cleanup(func() {
+				config = oldConfig
+				i18n.Set(i18n.English)
+			})
 
+			err := applyLanguage()
 			if tt.wantErr {
 				if err == nil {
-					t.Error("checkPrerequisites() expected error, got nil")
+					t.Error("applyLanguage() expected error, got nil")
 				}
 
 				return
 			}
 
 			if err != nil {
-				t.Errorf("checkPrerequisites() unexpected error = %v", err)
+				t.Fatalf("applyLanguage() unexpected error = %v", err)
+			}
+
+			if got := i18n.Current(); got != tt.want {
+				t.Errorf("language = %q, want %q", got, tt.want)
 			}
 		})
 	}
 }
 
-// =============================================================================
-// Test helpers
-// =============================================================================
+func TestTranslatedErrors(t *testing.T) {
+	t.Parallel()
 
-func must(t *testing.T, err error) {
-	t.Helper()
+	for _, l := range i18n.Languages() {
+		for _, err := range translatedErrors {
+			if !i18n.Has(l, err.Error()) {
+				t.Errorf("%q catalog: missing the error %q", l, err.Error())
+			}
+		}
+	}
+}
 
-	if err != nil {
-		t.Fatal(err)
+func TestLocalizeError(t *testing.T) {
+	// Not parallel - changes the message language
+
+	t.Cleanup(func() { i18n.Set(i18n.English) })
+
+	err := fmt.Errorf("failed to read the state: %w", clusterinfo.ErrNotDeployed)
+
+	if got := localizeError(err); got != err.Error() {
+		t.Errorf("localizeError() in English = %q, want %q", got, err.Error())
+	}
+
+	i18n.Set(i18n.French)
+
+	want := "failed to read the state: aucune donnée - le cluster n'est peut-être pas déployé"
+	if got := localizeError(err); got != want {
+		t.Errorf("localizeError() in French = %q, want %q", got, want)
 	}
 }
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/k8s-lab/get-cluster-info/bootstrap"
+	"github.com/k8s-lab/get-cluster-info/clusterinfo"
+	"github.com/k8s-lab/get-cluster-info/i18n"
+	"github.com/k8s-lab/get-cluster-info/render"
+	"github.com/spf13/cobra"
+)
+
+// =============================================================================
+// Node subcommands
+// =============================================================================
+//
+// Scales the cluster out: once a node is added to local.nodes and applied,
+// "node join" prepares it with the bootstrap steps, joins it with a fresh token
+// from the control plane, and waits until Kubernetes reports it Ready. The
+// extra nodes of local.nodes are read from the nodes output of the module.
+
+var (
+	nodeTimeout time.Duration
+	// nodePollInterval is the delay between two readiness checks.
+	nodePollInterval = 5 * time.Second
+)
+
+var nodeCmd = &cobra.Command{
+	Use:   "node",
+	Short: "Manage the nodes of a bootstrapped cluster",
+	Args:  cobra.NoArgs,
+}
+
+var nodeJoinCmd = &cobra.Command{
+	Use:   "join <name>",
+	Short: "Join a new worker to the cluster",
+	Long: `Join a new worker to the cluster.
+
+Add the node to local.nodes and apply first. The command then runs the
+bootstrap steps on the node (containerd, kubeadm...), joins it with a join
+command freshly created on the control plane, and waits until the node is
+Ready. Steps already done are skipped, so it can be run again after a failure.
+
+The nodes are read from the "nodes" output of the module, which lists every
+node of local.nodes with its IPs. With a module that predates it, use
+--discover and declare <name>_public_ip / <name>_private_ip outputs.
+
+Examples:
+  # Join the worker declared as "worker-2" in local.nodes
+  get-cluster-info node join worker-2`,
+	Args: cobra.ExactArgs(1),
+	RunE: runNodeJoin,
+}
+
+func init() {
+	nodeJoinCmd.Flags().DurationVar(&nodeTimeout, "timeout", 5*time.Minute, "How long to wait for the node to be Ready")
+
+	nodeCmd.AddCommand(nodeJoinCmd)
+	rootCmd.AddCommand(nodeCmd)
+}
+
+func runNodeJoin(_ *cobra.Command, args []string) error {
+	name := args[0]
+	ctx := context.Background()
+
+	info, dialer, closeSrc, err := openCluster(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer closeSrc()
+
+	logInfo("Joining %s to the cluster...", name)
+
+	err = bootstrap.Join(ctx, info, name, bootstrap.Options{Dialer: dialer, Progress: logBootstrapEvent})
+	if errors.Is(err, bootstrap.ErrUnknownNode) {
+		return i18n.Errorf("%w\n\nAdd the node to local.nodes and run terraform apply first: "+
+			"the nodes are read from the nodes output of the module", err)
+	}
+
+	if errors.Is(err, bootstrap.ErrStep) {
+		return i18n.Errorf("%w\n\nFix the problem and run %s again: completed steps are skipped",
+			err, render.CmdStyle.Render("get-cluster-info node join "+name))
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// Both exist: checked by bootstrap.Join.
+	cp, _ := bootstrap.ControlPlane(info)
+	node := info.AllNodes()[slices.IndexFunc(info.AllNodes(), func(n clusterinfo.Node) bool { return n.Name == name })]
+
+	client, err := dialer.Dial(ctx, cp.PublicIP)
+	if err != nil {
+		return i18n.Errorf("%s: %w", cp.Name, err)
+	}
+
+	defer func() { _ = client.Close() }()
+
+	logInfo("Waiting for %s to be Ready (timeout %s)...", name, nodeTimeout)
+
+	waitCtx, cancel := context.WithTimeout(ctx, nodeTimeout)
+	defer cancel()
+
+	reason := ""
+
+	status, err := bootstrap.WaitReady(waitCtx, client, node, nodePollInterval, func(s bootstrap.NodeStatus) {
+		if !s.Ready && s.Reason != reason {
+			reason = s.Reason
+			logInfo("%s registered as %s, not Ready yet: %s", name, s.Name, s.Reason)
+		}
+	})
+	if errors.Is(err, bootstrap.ErrNodeNotReady) {
+		return i18n.Errorf("%w\n\nThe nodes become Ready once a CNI is installed: %s", err,
+			render.CmdStyle.Render("get-cluster-info cni install cilium"))
+	}
+
+	if err != nil {
+		return err
+	}
+
+	logSuccess("%s joined the cluster and is Ready (node %s)", name, status.Name)
+
+	return nil
+}
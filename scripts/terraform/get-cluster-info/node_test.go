@@ -0,0 +1,129 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/k8s-lab/get-cluster-info/bootstrap"
+	"github.com/k8s-lab/get-cluster-info/clusterinfo/clusterinfotest"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// =============================================================================
+// node subcommand tests
+// =============================================================================
+
+// setupNodeJoin is setupBootstrap where every step is done and kubectl reports
+// the worker with the given Ready statuses, one per poll, the last one repeated.
+func setupNodeJoin(t *testing.T, ready ...string) func() string {
+	t.Helper()
+
+	polls := 0
+
+	_, out := setupBootstrap(t, func(_, cmd string) ([]byte, error) {
+		if cmd != "kubectl get nodes -o json" {
+			return nil, nil
+		}
+
+		status := ready[min(polls, len(ready)-1)]
+		polls++
+
+		return []byte(`{"items": [{"metadata": {"name": "k8s-lab-worker-1"}, "status": {
+  "addresses": [{"type": "InternalIP", "address": "10.0.0.11"}],
+  "conditions": [{"type": "Ready", "status": "` + status + `", "message": "cni plugin not initialized"}]}},
+  {"metadata": {"name": "k8s-lab-worker-2"}, "status": {
+  "addresses": [{"type": "InternalIP", "address": "10.0.0.12"}],
+  "conditions": [{"type": "Ready", "status": "` + status + `", "message": "cni plugin not initialized"}]}}]}`), nil
+	})
+
+	oldTimeout, oldInterval := nodeTimeout, nodePollInterval
+	t.Cleanup(func() { nodeTimeout, nodePollInterval = oldTimeout, oldInterval })
+
+	nodeTimeout, nodePollInterval = time.Second, time.Millisecond
+
+	return out
+}
+
+func TestRunNodeJoin(t *testing.T) {
+	// Not parallel - modifies global config
+
+	t.Run("ready", func(t *testing.T) {
+		out := setupNodeJoin(t, "False", "True")
+
+		must(t, runNodeJoin(nil, []string{"worker"}))
+
+		for _, want := range []string{
+			"[worker 1/",
+			"registered as k8s-lab-worker-1, not Ready yet: cni plugin not initialized",
+			"worker joined the cluster and is Ready (node k8s-lab-worker-1)",
+		} {
+			if !strings.Contains(out(), want) {
+				t.Errorf("output missing %q:\n%s", want, out())
+			}
+		}
+	})
+
+	t.Run("not ready hints at the CNI", func(t *testing.T) {
+		setupNodeJoin(t, "False")
+		nodeTimeout = 20 * time.Millisecond
+
+		err := runNodeJoin(nil, []string{"worker"})
+		if !errors.Is(err, bootstrap.ErrNodeNotReady) || !strings.Contains(err.Error(), "cni install cilium") {
+			t.Errorf("runNodeJoin() error = %v, want %v with a CNI hint", err, bootstrap.ErrNodeNotReady)
+		}
+	})
+
+	t.Run("discovered worker", func(t *testing.T) {
+		out := setupNodeJoin(t, "True")
+		addNode(t, "worker-2", "203.0.113.12", "10.0.0.12")
+
+		must(t, runNodeJoin(nil, []string{"worker-2"}))
+
+		for _, want := range []string{"[worker-2 1/", "worker-2 joined the cluster and is Ready (node k8s-lab-worker-2)"} {
+			if !strings.Contains(out(), want) {
+				t.Errorf("output missing %q:\n%s", want, out())
+			}
+		}
+	})
+
+	t.Run("unknown node", func(t *testing.T) {
+		setupNodeJoin(t, "True")
+
+		err := runNodeJoin(nil, []string{"worker-2"})
+		if !errors.Is(err, bootstrap.ErrUnknownNode) || !strings.Contains(err.Error(), "control-plane, worker") ||
+			!strings.Contains(err.Error(), "local.nodes") {
+			t.Errorf("runNodeJoin() error = %v, want %v listing the nodes, with a hint", err, bootstrap.ErrUnknownNode)
+		}
+	})
+}
+
+// addNode adds a worker to the nodes output of the fake Terraform client, as
+// terraform apply does once the node is added to local.nodes.
+func addNode(t *testing.T, name, publicIP, privateIP string) {
+	t.Helper()
+
+	fake, ok := terraformClient.(*clusterinfotest.FakeTerraform)
+	if !ok {
+		t.Fatal("terraformClient is not a fake")
+	}
+
+	out := fake.State.Values.Outputs["nodes"]
+
+	nodes, ok := out.Value.(map[string]any)
+	if !ok {
+		t.Fatalf("nodes output = %#v, want a map", out.Value)
+	}
+
+	nodes[name] = map[string]any{"role": "worker", "public_ip": publicIP, "private_ip": privateIP}
+
+	node := cty.Object(map[string]cty.Type{"role": cty.String, "public_ip": cty.String, "private_ip": cty.String})
+	attrs := map[string]cty.Type{}
+
+	for n := range nodes {
+		attrs[n] = node
+	}
+
+	out.Type = cty.Object(attrs)
+}
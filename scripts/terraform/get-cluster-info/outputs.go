@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/k8s-lab/get-cluster-info/clusterinfo"
+	"github.com/k8s-lab/get-cluster-info/render"
+	"github.com/spf13/cobra"
+)
+
+// =============================================================================
+// Outputs subcommand
+// =============================================================================
+//
+// Lists every Terraform output (not only the four IPs used by the summary),
+// with its type and description. Sensitive outputs are masked unless
+// --show-sensitive is passed.
+
+var showSensitive bool
+
+var outputsCmd = &cobra.Command{
+	Use:   "outputs",
+	Short: "List every Terraform output with its type and description",
+	Long: `List every Terraform output with its type and description.
+
+Strings, numbers, lists and maps are decoded. Outputs marked as sensitive
+in Terraform are masked unless --show-sensitive is passed.
+
+Examples:
+  # Human readable list
+  get-cluster-info outputs
+
+  # JSON for scripting, including sensitive values
+  get-cluster-info outputs --json --show-sensitive`,
+	Args: cobra.NoArgs,
+	RunE: runOutputs,
+}
+
+func init() {
+	outputsCmd.Flags().BoolVar(&showSensitive, "show-sensitive", false,
+		"Show the values of sensitive outputs (e.g. ssh_private_key)")
+
+	rootCmd.AddCommand(outputsCmd)
+}
+
+func runOutputs(_ *cobra.Command, _ []string) error {
+	ctx := context.Background()
+
+	src, err := setupEnvironment(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = src.Close() }()
+
+	logInfo("Retrieving outputs...")
+
+	outputs, err := timedSource{src}.Outputs(ctx)
+	if err != nil {
+		return err
+	}
+
+	descriptions, err := clusterinfo.LoadOutputDescriptions(config.TerraformDir)
+	if err != nil {
+		logWarning("Failed to read output descriptions: %v", err)
+	}
+
+	entries, err := clusterinfo.BuildOutputEntries(outputs, descriptions, showSensitive)
+	if err != nil {
+		return err
+	}
+
+	if showSensitive && render.WritingToGitHubLogs() {
+		maskSensitiveOutputs(entries)
+	}
+
+	if config.JSONOutput {
+		data, _ := json.MarshalIndent(entries, "", "  ")
+		fmt.Fprintln(stdout, string(data))
+
+		return nil
+	}
+
+	return printOutputs(entries)
+}
+
+func printOutputs(entries []clusterinfo.OutputEntry) error {
+	if len(entries) == 0 {
+		logWarning("No outputs found - the cluster may not be deployed")
+
+		return nil
+	}
+
+	return render.OutputsTable(stdout, entries)
+}
+
+// maskSensitiveOutputs masks the revealed sensitive values in the GitHub Actions
+// logs, before they are printed. The mask commands go to stderr, which the runner
+// also reads, so that stdout stays valid JSON with --json.
+func maskSensitiveOutputs(entries []clusterinfo.OutputEntry) {
+	for _, e := range entries {
+		if e.Sensitive {
+			maskOutputValue(e.Value)
+		}
+	}
+}
+
+// maskOutputValue masks every string and number of a decoded output value.
+func maskOutputValue(v any) {
+	switch v := v.(type) {
+	case string:
+		render.MaskGitHubValue(stderr, v)
+	case json.Number:
+		render.MaskGitHubValue(stderr, v.String())
+	case []any:
+		for _, item := range v {
+			maskOutputValue(item)
+		}
+	case map[string]any:
+		for _, item := range v {
+			maskOutputValue(item)
+		}
+	}
+}
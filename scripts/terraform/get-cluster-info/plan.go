@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/terraform-exec/tfexec"
+	"github.com/k8s-lab/get-cluster-info/i18n"
+	"github.com/k8s-lab/get-cluster-info/render"
+	"github.com/k8s-lab/get-cluster-info/tfplan"
+	"github.com/spf13/cobra"
+)
+
+// =============================================================================
+// Plan subcommand
+// =============================================================================
+//
+// Runs `terraform plan -out`, reads the plan file back as JSON (tf.ShowPlanFile)
+// and renders the changes grouped by resource type. Replacing or destroying a
+// server or an IP is highlighted: it loses a node or changes its public IP.
+
+var planOut string
+
+var planCmd = &cobra.Command{
+	Use:   "plan",
+	Short: "Run terraform plan and summarize the changes",
+	Long: `Run terraform plan and summarize the changes.
+
+Changes are grouped by resource type (create, update, replace, destroy).
+Replacing or destroying a server or an IP is highlighted in red.
+With --json, the summary is printed as JSON (e.g. for a CI comment).
+
+The plan file is deleted unless --out is passed; apply it with
+"terraform apply <file>" from the terraform directory.
+
+Examples:
+  # Summary of the pending changes
+  get-cluster-info plan
+
+  # Keep the plan file, JSON summary for CI
+  get-cluster-info plan --out tfplan --json`,
+	Args: cobra.NoArgs,
+	RunE: runPlan,
+}
+
+func init() {
+	planCmd.Flags().StringVarP(&planOut, "out", "o", "",
+		"Keep the plan file at this path (default: temporary file, deleted)")
+
+	rootCmd.AddCommand(planCmd)
+}
+
+func runPlan(_ *cobra.Command, _ []string) error {
+	ctx := context.Background()
+
+	planPath, cleanup, err := planFilePath(planOut)
+	if err != nil {
+		return err
+	}
+
+	defer cleanup()
+
+	src, err := setupEnvironment(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = src.Close() }()
+
+	tf := src.Client()
+
+	logInfo("Running terraform plan...")
+
+	if _, err := tf.Plan(ctx, tfexec.Out(planPath)); err != nil {
+		return withClass(errBackend, i18n.Errorf("terraform plan failed: %w", err))
+	}
+
+	plan, err := tf.ShowPlanFile(ctx, planPath)
+	if err != nil {
+		return withClass(errBackend, i18n.Errorf("failed to read plan file: %w", err))
+	}
+
+	summary := tfplan.Summarize(plan)
+
+	if config.JSONOutput {
+		data, _ := json.MarshalIndent(summary, "", "  ")
+		fmt.Fprintln(stdout, string(data))
+	} else if err := render.PlanSummary(stdout, summary); err != nil {
+		return err
+	}
+
+	if planOut != "" && summary.HasChanges() {
+		logInfo("Plan saved to %s - apply it with %s", render.PathStyle.Render(planPath),
+			render.CmdStyle.Render("terraform apply "+planPath))
+	}
+
+	return nil
+}
+
+// planFilePath returns the absolute path of out (terraform runs in the terraform
+// directory), or a temporary file removed by cleanup if out is empty.
+func planFilePath(out string) (string, func(), error) {
+	if out != "" {
+		path, err := filepath.Abs(out)
+		if err != nil {
+			return "", nil, withClass(errUsage, i18n.Errorf("invalid --out: %w", err))
+		}
+
+		return path, func() {}, nil
+	}
+
+	dir, err := os.MkdirTemp("", "get-cluster-info-plan-*")
+	if err != nil {
+		return "", nil, i18n.Errorf("failed to create temporary plan dir: %w", err)
+	}
+
+	return filepath.Join(dir, "tfplan"), func() { _ = os.RemoveAll(dir) }, nil
+}
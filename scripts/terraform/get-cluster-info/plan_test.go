@@ -0,0 +1,109 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/k8s-lab/get-cluster-info/clusterinfo/clusterinfotest"
+	"github.com/k8s-lab/get-cluster-info/tfplan"
+)
+
+// =============================================================================
+// plan subcommand tests
+// =============================================================================
+
+// setupPlan runs setupRun with the given fixture plan (empty: no changes).
+func setupPlan(t *testing.T, planFixture string) (*clusterinfotest.FakeTerraform, func() []byte) {
+	t.Helper()
+
+	out, fake := setupRun(t, clusterinfotest.FixtureDeployed)
+
+	if planFixture != "" {
+		plan, err := clusterinfotest.LoadPlanFixture(planFixture)
+		must(t, err)
+
+		fake.Planned = plan
+	}
+
+	oldOut := planOut
+	t.Cleanup(func() { planOut = oldOut })
+
+	planOut = ""
+
+	return fake, out.Bytes
+}
+
+func TestRunPlanGolden(t *testing.T) {
+	// Not parallel - modifies global config
+
+	tests := []struct {
+		name    string
+		fixture string
+		json    bool
+	}{
+		{name: "plan_changes_summary", fixture: clusterinfotest.FixturePlanChanges},
+		{name: "plan_changes_json", fixture: clusterinfotest.FixturePlanChanges, json: true},
+		{name: "plan_no_changes_summary"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake, out := setupPlan(t, tt.fixture)
+			config.JSONOutput = tt.json
+
+			must(t, runPlan(nil, nil))
+
+			assertGolden(t, tt.name, out())
+
+			if want := []string{"init", "plan", "show plan"}; !slices.Equal(fake.Calls(), want) {
+				t.Errorf("calls = %v, want %v", fake.Calls(), want)
+			}
+		})
+	}
+}
+
+func TestRunPlan(t *testing.T) {
+	// Not parallel - modifies global config
+
+	t.Run("JSON summary", func(t *testing.T) {
+		_, out := setupPlan(t, clusterinfotest.FixturePlanChanges)
+		config.JSONOutput = true
+
+		must(t, runPlan(nil, nil))
+
+		var summary tfplan.Summary
+		if err := json.Unmarshal(out(), &summary); err != nil {
+			t.Fatalf("invalid JSON summary: %v\n%s", err, out())
+		}
+
+		if summary.Destructive != 3 || summary.Totals.Total() != 6 {
+			t.Errorf("summary = %+v, want 6 changes, 3 destructive", summary)
+		}
+	})
+
+	t.Run("plan failure", func(t *testing.T) {
+		fake, _ := setupPlan(t, "")
+		fake.PlanErr = errors.New("provider credentials missing")
+
+		if err := runPlan(nil, nil); !errors.Is(err, fake.PlanErr) {
+			t.Fatalf("runPlan() error = %v, want %v", err, fake.PlanErr)
+		}
+	})
+
+	t.Run("--out is made absolute", func(t *testing.T) {
+		path, cleanup, err := planFilePath("tfplan")
+		must(t, err)
+		cleanup()
+
+		wd, err := os.Getwd()
+		must(t, err)
+
+		if want := filepath.Join(wd, "tfplan"); path != want {
+			t.Errorf("plan path = %q, want %q", path, want)
+		}
+	})
+}
@@ -0,0 +1,343 @@
+// Package remote runs commands on the cluster nodes over SSH, and transfers
+// files with SFTP.
+//
+// Host keys are trusted on first use: unknown hosts are added to a dedicated
+// known_hosts file, and a changed key is an error (the lab was redeployed on a
+// reused IP, or the connection is intercepted).
+package remote
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// =============================================================================
+// Types
+// =============================================================================
+
+const (
+	// DefaultUser is the login user of the Ubuntu images.
+	DefaultUser = "ubuntu"
+	// DefaultPort is the SSH port.
+	DefaultPort = 22
+	// DefaultTimeout bounds the TCP connection and the SSH handshake.
+	DefaultTimeout = 15 * time.Second
+
+	dirPermissions  = 0o700
+	filePermissions = 0o600
+)
+
+// ErrHostKeyChanged is returned when a node presents another host key than the recorded one.
+var ErrHostKeyChanged = errors.New("host key changed")
+
+// DialError is returned by SSHDialer.Dial when a node is unreachable or the SSH handshake fails.
+type DialError struct {
+	Addr string
+	Err  error
+}
+
+func (e *DialError) Error() string { return e.Err.Error() }
+
+func (e *DialError) Unwrap() error { return e.Err }
+
+// Client runs commands on a node.
+type Client interface {
+	// Run runs cmd in the login shell of the node and returns its standard output.
+	// A non-zero exit status is returned as an *ExitError.
+	Run(ctx context.Context, cmd string) ([]byte, error)
+	// Download copies the file at path on the node to w.
+	Download(ctx context.Context, path string, w io.Writer) error
+	// Upload copies r to the file at path on the node, created with mode 0600.
+	Upload(ctx context.Context, r io.Reader, path string) error
+	Close() error
+}
+
+// Dialer connects to a node by address.
+type Dialer interface {
+	Dial(ctx context.Context, host string) (Client, error)
+}
+
+// ExitError is a command that ran and exited with a non-zero status.
+type ExitError struct {
+	Status int
+	Stderr string
+}
+
+func (e *ExitError) Error() string {
+	if e.Stderr == "" {
+		return "exit status " + strconv.Itoa(e.Status)
+	}
+
+	return fmt.Sprintf("exit status %d: %s", e.Status, e.Stderr)
+}
+
+// Quote single-quotes s for a POSIX shell.
+func Quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// =============================================================================
+// SSH
+// =============================================================================
+
+// SSHDialer connects with a private key (the ssh_private_key Terraform output).
+type SSHDialer struct {
+	PrivateKey []byte
+	// KnownHostsPath records the host keys (trust on first use).
+	KnownHostsPath string
+	User           string        // default DefaultUser
+	Port           int           // default DefaultPort
+	Timeout        time.Duration // default DefaultTimeout
+}
+
+var _ Dialer = SSHDialer{}
+
+// knownHostsMu serializes the writes to the known_hosts files.
+var knownHostsMu sync.Mutex
+
+// Dial implements Dialer.
+func (d SSHDialer) Dial(ctx context.Context, host string) (Client, error) {
+	signer, err := ssh.ParsePrivateKey(d.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse SSH private key: %w", err)
+	}
+
+	hostKeys, err := d.hostKeyCallback()
+	if err != nil {
+		return nil, err
+	}
+
+	user, port, timeout := d.User, d.Port, d.Timeout
+	if user == "" {
+		user = DefaultUser
+	}
+
+	if port == 0 {
+		port = DefaultPort
+	}
+
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+
+	addr := net.JoinHostPort(host, strconv.Itoa(port))
+
+	conn, err := (&net.Dialer{Timeout: timeout}).DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, &DialError{Addr: addr, Err: fmt.Errorf("failed to connect to %s: %w", addr, err)}
+	}
+
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+
+	c, chans, reqs, err := ssh.NewClientConn(conn, addr, &ssh.ClientConfig{
+		User:            user,
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(signer)},
+		HostKeyCallback: hostKeys,
+		Timeout:         timeout,
+	})
+	if err != nil {
+		_ = conn.Close()
+
+		return nil, &DialError{Addr: addr, Err: fmt.Errorf("SSH handshake with %s failed: %w", addr, err)}
+	}
+
+	_ = conn.SetDeadline(time.Time{})
+
+	return &sshClient{client: ssh.NewClient(c, chans, reqs)}, nil
+}
+
+// hostKeyCallback checks the host keys against KnownHostsPath, adding unknown hosts.
+func (d SSHDialer) hostKeyCallback() (ssh.HostKeyCallback, error) {
+	path := d.KnownHostsPath
+
+	knownHostsMu.Lock()
+	defer knownHostsMu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(path), dirPermissions); err != nil {
+		return nil, fmt.Errorf("failed to create known hosts directory: %w", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDONLY, filePermissions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open known hosts: %w", err)
+	}
+
+	_ = f.Close()
+
+	known, err := knownhosts.New(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read known hosts %s: %w", path, err)
+	}
+
+	return func(hostname string, addr net.Addr, key ssh.PublicKey) error {
+		err := known(hostname, addr, key)
+
+		var keyErr *knownhosts.KeyError
+		if !errors.As(err, &keyErr) {
+			return err
+		}
+
+		if len(keyErr.Want) > 0 {
+			host, _, _ := net.SplitHostPort(hostname)
+
+			return fmt.Errorf("%w for %s - if the lab was redeployed, forget the old key with: ssh-keygen -R %s -f %s",
+				ErrHostKeyChanged, hostname, host, path)
+		}
+
+		return appendKnownHost(path, hostname, key)
+	}, nil
+}
+
+func appendKnownHost(path, hostname string, key ssh.PublicKey) error {
+	knownHostsMu.Lock()
+	defer knownHostsMu.Unlock()
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, filePermissions)
+	if err != nil {
+		return fmt.Errorf("failed to open known hosts: %w", err)
+	}
+
+	defer func() { _ = f.Close() }()
+
+	if _, err := fmt.Fprintln(f, knownhosts.Line([]string{knownhosts.Normalize(hostname)}, key)); err != nil {
+		return fmt.Errorf("failed to record host key: %w", err)
+	}
+
+	return nil
+}
+
+type sshClient struct {
+	client *ssh.Client
+}
+
+// Run implements Client. Cancelling ctx kills the remote command.
+func (c *sshClient) Run(ctx context.Context, cmd string) ([]byte, error) {
+	sess, err := c.client.NewSession()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open SSH session: %w", err)
+	}
+
+	defer func() { _ = sess.Close() }()
+
+	var stdout, stderr bytes.Buffer
+
+	sess.Stdout, sess.Stderr = &stdout, &stderr
+
+	done := make(chan error, 1)
+
+	go func() { done <- sess.Run(cmd) }()
+
+	select {
+	case <-ctx.Done():
+		_ = sess.Signal(ssh.SIGKILL)
+
+		return nil, ctx.Err()
+	case err = <-done:
+	}
+
+	var exitErr *ssh.ExitError
+	if errors.As(err, &exitErr) {
+		return stdout.Bytes(), &ExitError{Status: exitErr.ExitStatus(), Stderr: lastLines(stderr.String())}
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("SSH command failed: %w", err)
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// Download implements Client. Cancelling ctx aborts the transfer.
+func (c *sshClient) Download(ctx context.Context, path string, w io.Writer) error {
+	return c.sftp(ctx, func(s *sftp.Client) error {
+		f, err := s.Open(path)
+		if err != nil {
+			return fmt.Errorf("failed to open %s: %w", path, err)
+		}
+
+		defer func() { _ = f.Close() }()
+
+		if _, err := f.WriteTo(w); err != nil {
+			return fmt.Errorf("failed to download %s: %w", path, err)
+		}
+
+		return nil
+	})
+}
+
+// Upload implements Client. Cancelling ctx aborts the transfer.
+func (c *sshClient) Upload(ctx context.Context, r io.Reader, path string) error {
+	return c.sftp(ctx, func(s *sftp.Client) error {
+		f, err := s.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", path, err)
+		}
+
+		defer func() { _ = f.Close() }()
+
+		if err := f.Chmod(filePermissions); err != nil {
+			return fmt.Errorf("failed to restrict %s: %w", path, err)
+		}
+
+		if _, err := f.ReadFrom(r); err != nil {
+			return fmt.Errorf("failed to upload %s: %w", path, err)
+		}
+
+		return nil
+	})
+}
+
+// sftp runs fn with an SFTP session, closed when fn returns or ctx is done.
+func (c *sshClient) sftp(ctx context.Context, fn func(*sftp.Client) error) error {
+	s, err := sftp.NewClient(c.client)
+	if err != nil {
+		return fmt.Errorf("failed to open SFTP session: %w", err)
+	}
+
+	stop := context.AfterFunc(ctx, func() { _ = s.Close() })
+	defer stop()
+
+	defer func() { _ = s.Close() }()
+
+	if err := fn(s); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// Close implements Client.
+func (c *sshClient) Close() error {
+	return c.client.Close()
+}
+
+// stderrLines is the number of lines of standard error kept in an ExitError.
+const stderrLines = 5
+
+// lastLines keeps the end of a command error output.
+func lastLines(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	if len(lines) > stderrLines {
+		lines = lines[len(lines)-stderrLines:]
+	}
+
+	return strings.Join(lines, "\n")
+}
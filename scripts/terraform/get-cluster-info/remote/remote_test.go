@@ -0,0 +1,173 @@
+package remote
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// =============================================================================
+// Remote tests
+// =============================================================================
+
+func TestQuote(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "plain", want: "'plain'"},
+		{in: "", want: "''"},
+		{in: "it's $HOME", want: `'it'\''s $HOME'`},
+	}
+
+	for _, tt := range tests {
+		if got := Quote(tt.in); got != tt.want {
+			t.Errorf("Quote(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+
+	// The quoted string survives the shell unchanged.
+	if sh, err := exec.LookPath("sh"); err == nil {
+		in := `it's "$HOME" ; echo \n`
+
+		out, err := exec.Command(sh, "-c", "printf %s "+Quote(in)).Output()
+		if err != nil || string(out) != in {
+			t.Errorf("sh printf %s = %q, %v, want %q", Quote(in), out, err, in)
+		}
+	}
+}
+
+func TestExitError(t *testing.T) {
+	t.Parallel()
+
+	if got := (&ExitError{Status: 1}).Error(); got != "exit status 1" {
+		t.Errorf("Error() = %q", got)
+	}
+
+	if got := (&ExitError{Status: 2, Stderr: "no such file"}).Error(); got != "exit status 2: no such file" {
+		t.Errorf("Error() = %q", got)
+	}
+
+	if got := lastLines("1\n2\n3\n4\n5\n6\n7\n"); got != "3\n4\n5\n6\n7" {
+		t.Errorf("lastLines() = %q", got)
+	}
+}
+
+func newHostKey(t *testing.T) ssh.PublicKey {
+	t.Helper()
+
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return key
+}
+
+func TestHostKeyTrustOnFirstUse(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), ".ssh", "k8s-lab_known_hosts")
+	d := SSHDialer{KnownHostsPath: path}
+	addr := &net.TCPAddr{IP: net.ParseIP("203.0.113.10"), Port: DefaultPort}
+	key, otherKey := newHostKey(t), newHostKey(t)
+
+	check := func(key ssh.PublicKey) error {
+		t.Helper()
+
+		cb, err := d.hostKeyCallback()
+		if err != nil {
+			t.Fatalf("hostKeyCallback() error = %v", err)
+		}
+
+		return cb("203.0.113.10:22", addr, key)
+	}
+
+	// Unknown host: recorded.
+	if err := check(key); err != nil {
+		t.Fatalf("first connection error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil || !strings.HasPrefix(string(data), "203.0.113.10 ssh-ed25519 ") {
+		t.Fatalf("known hosts = %q, %v", data, err)
+	}
+
+	// Same key: accepted, not recorded twice.
+	if err := check(key); err != nil {
+		t.Errorf("second connection error = %v", err)
+	}
+
+	if data2, _ := os.ReadFile(path); string(data2) != string(data) {
+		t.Errorf("known hosts rewritten: %q", data2)
+	}
+
+	// Other key: refused.
+	if err := check(otherKey); !errors.Is(err, ErrHostKeyChanged) || !strings.Contains(err.Error(), "ssh-keygen -R") {
+		t.Errorf("changed key error = %v, want %v with a hint", err, ErrHostKeyChanged)
+	}
+}
+
+func TestDialInvalidKey(t *testing.T) {
+	t.Parallel()
+
+	d := SSHDialer{PrivateKey: []byte("not a key"), KnownHostsPath: filepath.Join(t.TempDir(), "known_hosts")}
+
+	if _, err := d.Dial(context.Background(), "203.0.113.10"); err == nil || !strings.Contains(err.Error(), "private key") {
+		t.Errorf("Dial() error = %v, want a private key error", err)
+	}
+}
+
+func TestDialUnreachable(t *testing.T) {
+	t.Parallel()
+
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	block, err := ssh.MarshalPrivateKey(priv, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A port that was just released: the connection is refused.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	port := l.Addr().(*net.TCPAddr).Port
+	_ = l.Close()
+
+	d := SSHDialer{
+		PrivateKey:     pem.EncodeToMemory(block),
+		KnownHostsPath: filepath.Join(t.TempDir(), "known_hosts"),
+		Port:           port,
+	}
+
+	_, err = d.Dial(context.Background(), "127.0.0.1")
+
+	var dialErr *DialError
+	if !errors.As(err, &dialErr) || dialErr.Addr != net.JoinHostPort("127.0.0.1", strconv.Itoa(port)) {
+		t.Errorf("Dial() error = %v, want a *DialError", err)
+	}
+}
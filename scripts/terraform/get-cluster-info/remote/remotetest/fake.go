@@ -0,0 +1,166 @@
+// Package remotetest provides a fake remote.Dialer, to test code running
+// commands on the nodes without SSH.
+//
+//	dialer := &remotetest.FakeDialer{Handler: func(host, cmd string) ([]byte, error) {
+//		if strings.Contains(cmd, "kubeadm token create") {
+//			return []byte("kubeadm join 10.0.0.10:6443 --token abc"), nil
+//		}
+//		return nil, nil
+//	}}
+package remotetest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"sync"
+
+	"github.com/k8s-lab/get-cluster-info/remote"
+)
+
+// Call is a command run on a host.
+type Call struct {
+	Host string
+	Cmd  string
+}
+
+// FakeDialer records the commands and answers them with Handler.
+type FakeDialer struct {
+	// Handler answers the commands (nil: every command succeeds without output).
+	Handler func(host, cmd string) ([]byte, error)
+	// DialErr fails the connection to the hosts it contains.
+	DialErr map[string]error
+
+	mu     sync.Mutex
+	calls  []Call
+	closed int
+	files  map[string][]byte // host + ":" + path
+}
+
+var _ remote.Dialer = (*FakeDialer)(nil)
+
+// Exit returns the error of a command exiting with status.
+func Exit(status int) error {
+	return &remote.ExitError{Status: status}
+}
+
+// Dial implements remote.Dialer.
+func (f *FakeDialer) Dial(_ context.Context, host string) (remote.Client, error) {
+	if err := f.DialErr[host]; err != nil {
+		return nil, err
+	}
+
+	return &fakeClient{dialer: f, host: host}, nil
+}
+
+// Calls returns the commands run so far, on every host.
+func (f *FakeDialer) Calls() []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]Call(nil), f.calls...)
+}
+
+// Commands returns the commands run on host.
+func (f *FakeDialer) Commands(host string) []string {
+	var cmds []string
+
+	for _, c := range f.Calls() {
+		if c.Host == host {
+			cmds = append(cmds, c.Cmd)
+		}
+	}
+
+	return cmds
+}
+
+// Closed returns the number of closed clients.
+func (f *FakeDialer) Closed() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.closed
+}
+
+// WriteFile creates the file at path on host, as a command or an upload would.
+func (f *FakeDialer) WriteFile(host, path string, data []byte) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.files == nil {
+		f.files = map[string][]byte{}
+	}
+
+	f.files[host+":"+path] = append([]byte(nil), data...)
+}
+
+// ReadFile returns the file at path on host.
+func (f *FakeDialer) ReadFile(host, path string) ([]byte, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	data, ok := f.files[host+":"+path]
+
+	return data, ok
+}
+
+type fakeClient struct {
+	dialer *FakeDialer
+	host   string
+}
+
+func (c *fakeClient) Run(ctx context.Context, cmd string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	c.dialer.mu.Lock()
+	c.dialer.calls = append(c.dialer.calls, Call{Host: c.host, Cmd: cmd})
+	c.dialer.mu.Unlock()
+
+	if c.dialer.Handler == nil {
+		return nil, nil
+	}
+
+	return c.dialer.Handler(c.host, cmd)
+}
+
+func (c *fakeClient) Download(ctx context.Context, path string, w io.Writer) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	data, ok := c.dialer.ReadFile(c.host, path)
+	if !ok {
+		return fmt.Errorf("failed to open %s: %w", path, fs.ErrNotExist)
+	}
+
+	_, err := w.Write(data)
+
+	return err
+}
+
+func (c *fakeClient) Upload(ctx context.Context, r io.Reader, path string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	c.dialer.WriteFile(c.host, path, data)
+
+	return nil
+}
+
+func (c *fakeClient) Close() error {
+	c.dialer.mu.Lock()
+	defer c.dialer.mu.Unlock()
+
+	c.dialer.closed++
+
+	return nil
+}
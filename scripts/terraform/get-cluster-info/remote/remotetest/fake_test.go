@@ -0,0 +1,93 @@
+package remotetest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/fs"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/k8s-lab/get-cluster-info/remote"
+)
+
+func TestFakeDialer(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	errRefused := errors.New("connection refused")
+
+	dialer := &FakeDialer{
+		Handler: func(host, cmd string) ([]byte, error) {
+			if cmd == "false" {
+				return nil, Exit(1)
+			}
+
+			return []byte(host + ": " + cmd), nil
+		},
+		DialErr: map[string]error{"203.0.113.99": errRefused},
+	}
+
+	if _, err := dialer.Dial(ctx, "203.0.113.99"); !errors.Is(err, errRefused) {
+		t.Errorf("Dial() error = %v, want %v", err, errRefused)
+	}
+
+	client, err := dialer.Dial(ctx, "203.0.113.10")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if out, err := client.Run(ctx, "hostname"); err != nil || string(out) != "203.0.113.10: hostname" {
+		t.Errorf("Run() = %q, %v", out, err)
+	}
+
+	var exitErr *remote.ExitError
+	if _, err := client.Run(ctx, "false"); !errors.As(err, &exitErr) || exitErr.Status != 1 {
+		t.Errorf("Run(false) error = %v, want exit status 1", err)
+	}
+
+	_ = client.Close()
+
+	if want := []string{"hostname", "false"}; !slices.Equal(dialer.Commands("203.0.113.10"), want) {
+		t.Errorf("Commands() = %v, want %v", dialer.Commands("203.0.113.10"), want)
+	}
+
+	if dialer.Closed() != 1 {
+		t.Errorf("Closed() = %d, want 1", dialer.Closed())
+	}
+}
+
+func TestFakeDialerFiles(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dialer := &FakeDialer{}
+
+	client, err := dialer.Dial(ctx, "203.0.113.10")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.Upload(ctx, strings.NewReader("snapshot"), "/tmp/snapshot.db"); err != nil {
+		t.Fatalf("Upload() error = %v", err)
+	}
+
+	if data, ok := dialer.ReadFile("203.0.113.10", "/tmp/snapshot.db"); !ok || string(data) != "snapshot" {
+		t.Errorf("ReadFile() = %q, %v", data, ok)
+	}
+
+	var buf bytes.Buffer
+	if err := client.Download(ctx, "/tmp/snapshot.db", &buf); err != nil || buf.String() != "snapshot" {
+		t.Errorf("Download() = %q, %v", buf.String(), err)
+	}
+
+	// Files are per host.
+	if _, ok := dialer.ReadFile("203.0.113.11", "/tmp/snapshot.db"); ok {
+		t.Error("file visible on another host")
+	}
+
+	if err := client.Download(ctx, "/tmp/missing", &buf); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Download(missing) error = %v, want %v", err, fs.ErrNotExist)
+	}
+}
@@ -0,0 +1,53 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/k8s-lab/get-cluster-info/audit"
+)
+
+// =============================================================================
+// Audit report
+// =============================================================================
+
+// severityStyles maps a finding severity to its color.
+var severityStyles = map[audit.Severity]lipgloss.Style{
+	audit.SeverityHigh:   lipgloss.NewStyle().Foreground(Red).Bold(true),
+	audit.SeverityMedium: lipgloss.NewStyle().Foreground(Yellow),
+	audit.SeverityLow:    lipgloss.NewStyle().Foreground(Blue),
+}
+
+// AuditReport renders the findings with their fix hints, and a summary line.
+func AuditReport(w io.Writer, report audit.Report) error {
+	lines := []string{SectionStyle.Render("NETWORK EXPOSURE AUDIT")}
+
+	if len(report.Findings) == 0 {
+		lines = append(lines, "  "+lipgloss.NewStyle().Foreground(Green).Render(Sym.OK)+" No exposed service found")
+	}
+
+	sevWidth := 0
+	for _, sev := range audit.Severities {
+		sevWidth = max(sevWidth, len(sev))
+	}
+
+	for _, f := range report.Findings {
+		sev := severityStyles[f.Severity].Width(sevWidth + stylePaddingH).Render(strings.ToUpper(string(f.Severity)))
+
+		lines = append(lines,
+			"  "+sev+f.Message,
+			"  "+strings.Repeat(" ", sevWidth+stylePaddingH)+LabelStyle.Width(0).Render(f.Rule+" ("+f.Resource+")"))
+
+		if f.Hint != "" {
+			lines = append(lines, "  "+strings.Repeat(" ", sevWidth+stylePaddingH)+Sym.Arrow+" "+CmdStyle.Render(f.Hint))
+		}
+	}
+
+	lines = append(lines, "", fmt.Sprintf("  %d rule(s) checked: %d high, %d medium, %d low",
+		report.RulesChecked, report.Count(audit.SeverityHigh), report.Count(audit.SeverityMedium),
+		report.Count(audit.SeverityLow)))
+
+	return writeReport(w, lines)
+}
@@ -0,0 +1,81 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/k8s-lab/get-cluster-info/cni"
+)
+
+// =============================================================================
+// Cilium status
+// =============================================================================
+
+// CiliumStatus renders the Cilium workloads and the agent of every node, like `cilium status`.
+func CiliumStatus(w io.Writer, s cni.Status) error {
+	ok := lipgloss.NewStyle().Foreground(Green).Render(Sym.OK)
+	ko := lipgloss.NewStyle().Foreground(Red).Render(Sym.Fail)
+
+	lines := []string{SectionStyle.Render("CILIUM")}
+
+	if s.Ready() {
+		lines = append(lines, "  "+ok+" Cilium is ready on every node")
+	} else {
+		lines = append(lines, "  "+lipgloss.NewStyle().Foreground(Yellow).Render(Sym.Warn+" Cilium is not ready"))
+	}
+
+	nameWidth := 0
+	for _, wl := range s.Workloads {
+		nameWidth = max(nameWidth, len(wl.Name))
+	}
+
+	if len(s.Workloads) > 0 {
+		lines = append(lines, "")
+	}
+
+	for _, wl := range s.Workloads {
+		icon := ok
+		if !wl.OK() {
+			icon = ko
+		}
+
+		lines = append(lines, fmt.Sprintf("  %s %-10s %-*s  %s  %s", icon, wl.Kind, nameWidth, wl.Name,
+			ValueStyle.Render(fmt.Sprintf("%d/%d ready", wl.Ready, wl.Desired)),
+			LabelStyle.Width(0).Render(shortImage(wl.Image))))
+	}
+
+	lines = append(lines, "", SectionStyle.MarginTop(0).Render("NODES"))
+
+	nodeWidth := 0
+	for _, a := range s.Agents {
+		nodeWidth = max(nodeWidth, len(a.Node))
+	}
+
+	for _, a := range s.Agents {
+		icon, detail := ok, a.Pod
+		if !a.Ready {
+			icon, detail = ko, lipgloss.NewStyle().Foreground(Yellow).Render(a.Reason)
+		}
+
+		if a.Restarts > 0 {
+			detail += fmt.Sprintf(" (%d restart(s))", a.Restarts)
+		}
+
+		lines = append(lines, fmt.Sprintf("  %s %-*s  %-15s %s", icon, nodeWidth, a.Node, a.NodeIP, detail))
+	}
+
+	if len(s.Agents) == 0 {
+		lines = append(lines, "  "+LabelStyle.Width(0).Render("No node registered - run get-cluster-info bootstrap"))
+	}
+
+	return writeReport(w, lines)
+}
+
+// shortImage drops the digest of an image reference.
+func shortImage(image string) string {
+	name, _, _ := strings.Cut(image, "@")
+
+	return name
+}
@@ -0,0 +1,86 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/k8s-lab/get-cluster-info/cost"
+)
+
+// =============================================================================
+// Cost estimate
+// =============================================================================
+
+// costTitles names the estimate by its source.
+var costTitles = map[cost.Source]string{
+	cost.SourceState: "COST ESTIMATE (deployed)",
+	cost.SourcePlan:  "COST ESTIMATE (after plan)",
+}
+
+// CostEstimate renders the billed resources with their hourly and monthly
+// prices, the totals and, for a deployed lab, the cost accumulated up to now.
+func CostEstimate(w io.Writer, e cost.Estimate, now time.Time) error {
+	lines := []string{SectionStyle.Render(costTitles[e.Source])}
+
+	if len(e.Flavors) > 0 {
+		flavors := make([]string, 0, len(e.Flavors))
+		for _, f := range e.Flavors {
+			flavors = append(flavors, fmt.Sprintf("%d %s %s", f.Count, Sym.Times, f.Flavor))
+		}
+
+		lines = append(lines, "  "+LabelStyle.Render("Nodes:")+" "+ValueStyle.Render(strings.Join(flavors, ", ")), "")
+	}
+
+	width := len("Total")
+	for _, it := range e.Items {
+		width = max(width, len(costLabel(it)))
+	}
+
+	for _, it := range e.Items {
+		lines = append(lines,
+			"  "+fmt.Sprintf("%-*s  %s", width, costLabel(it), costPrices(e.Currency, it.Hourly, it.Monthly)))
+	}
+
+	if len(e.Items) == 0 {
+		lines = append(lines, "  "+LabelStyle.Width(0).Render("No billed resource - the cluster may not be deployed"))
+	}
+
+	lines = append(lines, "  "+strings.Repeat(Sym.Rule, width+2+len(costPrices(e.Currency, 0, 0))),
+		"  "+ValueStyle.Render(fmt.Sprintf("%-*s  %s", width, "Total", costPrices(e.Currency, e.Hourly, e.Monthly))))
+
+	switch {
+	case e.Since != nil && e.Accumulated != nil:
+		lines = append(lines, "", fmt.Sprintf("  Since %s (%s): %s",
+			e.Since.Format("2006-01-02 15:04 UTC"), FormatDuration(now.Sub(*e.Since)),
+			ValueStyle.Render(fmt.Sprintf("%.2f %s", *e.Accumulated, e.Currency))))
+	case e.Source == cost.SourceState && len(e.Items) > 0:
+		lines = append(lines, "", "  "+LabelStyle.Width(0).Render(
+			"Accumulated cost unknown - set a TTL to record the creation time: get-cluster-info ttl set"))
+	}
+
+	for _, address := range e.Unpriced {
+		lines = append(lines, "  "+lipgloss.NewStyle().Foreground(Yellow).Render(
+			Sym.Warn+" No price for "+address+" - add its flavor to a price table (--prices)"))
+	}
+
+	lines = append(lines, "", "  "+LabelStyle.Width(0).Render("Indicative prices, excluding VAT"))
+
+	return writeReport(w, lines)
+}
+
+// costLabel is the address of an item, with the flavor of servers.
+func costLabel(it cost.Item) string {
+	if it.Flavor != "" {
+		return it.Address + " " + it.Flavor
+	}
+
+	return it.Address
+}
+
+// costPrices formats the hourly and monthly prices in aligned columns.
+func costPrices(currency string, hourly, monthly float64) string {
+	return fmt.Sprintf("%8.4f %s/h  %8.2f %s/month", hourly, currency, monthly, currency)
+}
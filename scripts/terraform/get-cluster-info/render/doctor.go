@@ -0,0 +1,52 @@
+package render
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/k8s-lab/get-cluster-info/doctor"
+)
+
+// =============================================================================
+// Doctor report
+// =============================================================================
+
+// statusIcon returns the icon of a check status.
+func statusIcon(s doctor.Status) string {
+	switch s {
+	case doctor.StatusOK:
+		return lipgloss.NewStyle().Foreground(Green).Render(Sym.OK)
+	case doctor.StatusWarn:
+		return lipgloss.NewStyle().Foreground(Yellow).Render(Sym.Warn)
+	case doctor.StatusFail:
+		return lipgloss.NewStyle().Foreground(Red).Render(Sym.Fail)
+	default:
+		return LabelStyle.Width(0).Render("-")
+	}
+}
+
+// DoctorReport renders the checks with their fix hints, and a summary line.
+func DoctorReport(w io.Writer, report doctor.Report) error {
+	nameWidth := 0
+	for _, c := range report.Checks {
+		nameWidth = max(nameWidth, len(c.Name))
+	}
+
+	nameStyle := LabelStyle.Width(nameWidth + stylePaddingH)
+
+	lines := []string{SectionStyle.Render("DOCTOR")}
+
+	for _, c := range report.Checks {
+		lines = append(lines, "  "+statusIcon(c.Status)+" "+nameStyle.Render(c.Name)+c.Message)
+
+		if c.Hint != "" {
+			lines = append(lines, "      "+Sym.Arrow+" "+CmdStyle.Render(c.Hint))
+		}
+	}
+
+	lines = append(lines, "", fmt.Sprintf("  %d ok, %d warning(s), %d failure(s)",
+		report.Count(doctor.StatusOK), report.Count(doctor.StatusWarn), report.Count(doctor.StatusFail)))
+
+	return writeReport(w, lines)
+}
@@ -0,0 +1,49 @@
+package render
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/k8s-lab/get-cluster-info/tfplan"
+)
+
+// =============================================================================
+// Drift report
+// =============================================================================
+
+// driftDescriptions explains the drift actions.
+var driftDescriptions = map[tfplan.Action]string{
+	tfplan.ActionUpdate:  "changed outside Terraform",
+	tfplan.ActionDestroy: "deleted outside Terraform",
+	tfplan.ActionCreate:  "created outside Terraform",
+}
+
+// DriftReport renders the drifted resources with their attribute diffs.
+func DriftReport(w io.Writer, r tfplan.DriftReport) error {
+	lines := []string{SectionStyle.Render("DRIFT")}
+
+	if len(r.Resources) == 0 {
+		lines = append(lines, "  "+lipgloss.NewStyle().Foreground(Green).Render(Sym.OK)+
+			" No drift - the deployed infrastructure matches the Terraform state")
+	}
+
+	for _, res := range r.Resources {
+		lines = append(lines, "  "+actionSymbols[res.Action]+" "+ValueStyle.Render(res.Address)+"  "+
+			LabelStyle.Width(0).Render(driftDescriptions[res.Action]))
+
+		for _, d := range res.Diffs {
+			lines = append(lines, "        "+d.Path+": "+
+				lipgloss.NewStyle().Foreground(Red).Render(d.Before)+" "+Sym.Arrow+" "+
+				lipgloss.NewStyle().Foreground(Green).Render(d.After))
+		}
+	}
+
+	if len(r.Resources) > 0 {
+		lines = append(lines, "",
+			fmt.Sprintf("  %d resource(s) drifted - revert the change with %s, or update the code",
+				len(r.Resources), CmdStyle.Render("terraform apply")))
+	}
+
+	return writeReport(w, lines)
+}
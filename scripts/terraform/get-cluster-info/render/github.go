@@ -0,0 +1,178 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/k8s-lab/get-cluster-info/clusterinfo"
+)
+
+// =============================================================================
+// GitHub Actions integration
+// =============================================================================
+//
+// When the tool runs inside a GitHub Actions step, the runner exposes two files:
+//   - GITHUB_OUTPUT       : step outputs, readable by later steps and jobs
+//   - GITHUB_STEP_SUMMARY : markdown rendered on the workflow run page
+//
+// Reference: https://docs.github.com/actions/reference/workflow-commands-for-github-actions
+
+const (
+	githubOutputEnv  = "GITHUB_OUTPUT"
+	githubSummaryEnv = "GITHUB_STEP_SUMMARY"
+
+	// Set to "true" by the runner for every step.
+	githubActionsEnv = "GITHUB_ACTIONS"
+
+	// Delimiter used for multi-line step outputs (name<<EOF ... EOF).
+	githubOutputDelimiter = "GET_CLUSTER_INFO_EOF"
+
+	githubFilePermissions = 0o600
+)
+
+// githubOutput is a single step output (name=value).
+type githubOutput struct {
+	Name  string
+	Value string
+}
+
+// RunningInGitHubActions reports whether the GitHub Actions output files are available.
+func RunningInGitHubActions() bool {
+	return os.Getenv(githubOutputEnv) != "" || os.Getenv(githubSummaryEnv) != ""
+}
+
+// WritingToGitHubLogs reports whether the output goes to the logs of a GitHub Actions
+// runner, which redacts the values masked with MaskGitHubValue.
+func WritingToGitHubLogs() bool {
+	return os.Getenv(githubActionsEnv) == "true" || RunningInGitHubActions()
+}
+
+// clusterInfoOutputs flattens ClusterInfo into step outputs: the legacy
+// control_plane_* and worker_* names, then <name>_public_ip / <name>_private_ip
+// for every other node (worker-2 gives worker_2_public_ip).
+// The SSH private key is never part of ClusterInfo, so it can never leak here.
+func clusterInfoOutputs(info *clusterinfo.ClusterInfo) []githubOutput {
+	outputs := []githubOutput{
+		{Name: "control_plane_public_ip", Value: info.ControlPlane.PublicIP},
+		{Name: "control_plane_private_ip", Value: info.ControlPlane.PrivateIP},
+		{Name: "worker_public_ip", Value: info.Worker.PublicIP},
+		{Name: "worker_private_ip", Value: info.Worker.PrivateIP},
+		{Name: "ssh_key_path", Value: info.SSHKeyPath},
+	}
+
+	written := map[string]bool{}
+	for _, o := range outputs {
+		written[o.Name] = true
+	}
+
+	for _, n := range info.AllNodes() {
+		prefix := strings.ReplaceAll(n.Name, "-", "_")
+
+		for _, o := range []githubOutput{
+			{Name: prefix + "_public_ip", Value: n.PublicIP},
+			{Name: prefix + "_private_ip", Value: n.PrivateIP},
+		} {
+			if !written[o.Name] {
+				written[o.Name] = true
+				outputs = append(outputs, o)
+			}
+		}
+	}
+
+	return outputs
+}
+
+// WriteGitHubActions writes step outputs and the job summary, if the runner provides them.
+func WriteGitHubActions(info *clusterinfo.ClusterInfo) error {
+	if path := os.Getenv(githubOutputEnv); path != "" {
+		if err := appendToFile(path, func(w io.Writer) error {
+			return writeGitHubOutputs(w, clusterInfoOutputs(info))
+		}); err != nil {
+			return fmt.Errorf("failed to write step outputs: %w", err)
+		}
+	}
+
+	if path := os.Getenv(githubSummaryEnv); path != "" {
+		if err := appendToFile(path, func(w io.Writer) error {
+			return GitHubSummary{}.Render(w, info)
+		}); err != nil {
+			return fmt.Errorf("failed to write job summary: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// writeGitHubOutputs writes outputs using the GITHUB_OUTPUT file format.
+// Values containing a newline use the heredoc syntax.
+func writeGitHubOutputs(w io.Writer, outputs []githubOutput) error {
+	for _, o := range outputs {
+		var err error
+		if strings.Contains(o.Value, "\n") {
+			_, err = fmt.Fprintf(w, "%s<<%s\n%s\n%s\n", o.Name, githubOutputDelimiter, o.Value, githubOutputDelimiter)
+		} else {
+			_, err = fmt.Fprintf(w, "%s=%s\n", o.Name, o.Value)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GitHubSummary renders a markdown table describing the cluster (job summary format).
+type GitHubSummary struct{}
+
+// Render implements Renderer.
+func (GitHubSummary) Render(w io.Writer, info *clusterinfo.ClusterInfo) error {
+	var b strings.Builder
+
+	b.WriteString("## 🚀 K8S-LAB cluster\n\n")
+	b.WriteString("| Node | Public IP | Private IP |\n")
+	b.WriteString("| --- | --- | --- |\n")
+
+	for _, n := range info.AllNodes() {
+		fmt.Fprintf(&b, "| %s | `%s` | `%s` |\n", n.Name, n.PublicIP, n.PrivateIP)
+	}
+
+	b.WriteString("\n")
+	fmt.Fprintf(&b, "SSH: `ssh -i %s ubuntu@%s`\n\n", info.SSHKeyPath, info.ControlPlane.PublicIP)
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+// MaskGitHubValue asks the runner to redact a value from the logs. Write it to
+// stderr: the runner reads the workflow commands of both streams, and stdout
+// may be piped (--json). Each line is masked separately: the runner matches
+// masks line by line.
+func MaskGitHubValue(w io.Writer, value string) {
+	for _, line := range strings.Split(value, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		fmt.Fprintf(w, "::add-mask::%s\n", line)
+	}
+}
+
+func appendToFile(path string, write func(w io.Writer) error) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, githubFilePermissions) //nolint:gosec // path comes from the runner
+	if err != nil {
+		return err
+	}
+
+	if err := write(f); err != nil {
+		_ = f.Close()
+
+		return err
+	}
+
+	return f.Close()
+}
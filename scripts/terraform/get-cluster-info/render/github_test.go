@@ -0,0 +1,199 @@
+package render
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/k8s-lab/get-cluster-info/clusterinfo"
+)
+
+// =============================================================================
+// writeGitHubOutputs tests
+// =============================================================================
+
+func TestWriteGitHubOutputs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		outputs  []githubOutput
+		expected string
+	}{
+		{
+			name:     "no outputs - writes nothing",
+			outputs:  nil,
+			expected: "",
+		},
+		{
+			name: "single-line values - name=value",
+			outputs: []githubOutput{
+				{Name: "control_plane_public_ip", Value: "1.2.3.4"},
+				{Name: "worker_public_ip", Value: "5.6.7.8"},
+			},
+			expected: "control_plane_public_ip=1.2.3.4\nworker_public_ip=5.6.7.8\n",
+		},
+		{
+			name: "multi-line value - heredoc syntax",
+			outputs: []githubOutput{
+				{Name: "multi", Value: "a\nb"},
+			},
+			expected: "multi<<" + githubOutputDelimiter + "\na\nb\n" + githubOutputDelimiter + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			if err := writeGitHubOutputs(&buf, tt.outputs); err != nil {
+				t.Fatalf("writeGitHubOutputs() unexpected error = %v", err)
+			}
+
+			if buf.String() != tt.expected {
+				t.Errorf("writeGitHubOutputs() = %q, want %q", buf.String(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestClusterInfoOutputs(t *testing.T) {
+	t.Parallel()
+
+	info := &clusterinfo.ClusterInfo{
+		ControlPlane: clusterinfo.NodeInfo{PublicIP: "1.2.3.4", PrivateIP: "10.0.0.10"},
+		Worker:       clusterinfo.NodeInfo{PublicIP: "5.6.7.8", PrivateIP: "10.0.0.11"},
+		Nodes: []clusterinfo.Node{
+			{Name: "control-plane", Role: "control-plane", NodeInfo: clusterinfo.NodeInfo{PublicIP: "1.2.3.4", PrivateIP: "10.0.0.10"}},
+			{Name: "worker", Role: "worker", NodeInfo: clusterinfo.NodeInfo{PublicIP: "5.6.7.8", PrivateIP: "10.0.0.11"}},
+			{Name: "worker-2", Role: "worker", NodeInfo: clusterinfo.NodeInfo{PublicIP: "5.6.7.9", PrivateIP: "10.0.0.12"}},
+		},
+		SSHKeyPath: "/home/user/.ssh/k8s-lab.pem",
+	}
+
+	var buf bytes.Buffer
+	must(t, writeGitHubOutputs(&buf, clusterInfoOutputs(info)))
+
+	want := "control_plane_public_ip=1.2.3.4\n" +
+		"control_plane_private_ip=10.0.0.10\n" +
+		"worker_public_ip=5.6.7.8\n" +
+		"worker_private_ip=10.0.0.11\n" +
+		"ssh_key_path=/home/user/.ssh/k8s-lab.pem\n" +
+		"worker_2_public_ip=5.6.7.9\n" +
+		"worker_2_private_ip=10.0.0.12\n"
+	if buf.String() != want {
+		t.Errorf("step outputs = %q, want %q", buf.String(), want)
+	}
+}
+
+// =============================================================================
+// MaskGitHubValue tests
+// =============================================================================
+
+func TestMaskGitHubValue(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		value    string
+		expected string
+	}{
+		{
+			name:     "empty value - no mask",
+			value:    "",
+			expected: "",
+		},
+		{
+			name:     "single line - one mask",
+			value:    "secret",
+			expected: "::add-mask::secret\n",
+		},
+		{
+			name:     "multi-line key - one mask per non-empty line",
+			value:    "-----BEGIN KEY-----\nabc\n\n-----END KEY-----\n",
+			expected: "::add-mask::-----BEGIN KEY-----\n::add-mask::abc\n::add-mask::-----END KEY-----\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+
+			MaskGitHubValue(&buf, tt.value)
+
+			if buf.String() != tt.expected {
+				t.Errorf("MaskGitHubValue() = %q, want %q", buf.String(), tt.expected)
+			}
+		})
+	}
+}
+
+// =============================================================================
+// WriteGitHubActions tests
+// =============================================================================
+
+func TestWriteGitHubActions(t *testing.T) {
+	// Not parallel - modifies environment
+
+	tmpDir := t.TempDir()
+	outputFile := filepath.Join(tmpDir, "output")
+	summaryFile := filepath.Join(tmpDir, "summary")
+
+	t.Setenv(githubOutputEnv, outputFile)
+	t.Setenv(githubSummaryEnv, summaryFile)
+
+	if !RunningInGitHubActions() {
+		t.Fatal("RunningInGitHubActions() = false, want true")
+	}
+
+	info := &clusterinfo.ClusterInfo{
+		ControlPlane: clusterinfo.NodeInfo{PublicIP: "1.2.3.4", PrivateIP: "10.0.0.10"},
+		Worker:       clusterinfo.NodeInfo{PublicIP: "5.6.7.8", PrivateIP: "10.0.0.11"},
+		SSHKeyPath:   "/home/user/.ssh/k8s-lab.pem",
+	}
+
+	must(t, WriteGitHubActions(info))
+
+	outputs, err := os.ReadFile(outputFile)
+	must(t, err)
+
+	for _, want := range []string{
+		"control_plane_public_ip=1.2.3.4\n",
+		"control_plane_private_ip=10.0.0.10\n",
+		"worker_public_ip=5.6.7.8\n",
+		"worker_private_ip=10.0.0.11\n",
+		"ssh_key_path=/home/user/.ssh/k8s-lab.pem\n",
+	} {
+		if !strings.Contains(string(outputs), want) {
+			t.Errorf("step outputs missing %q, got:\n%s", want, outputs)
+		}
+	}
+
+	if strings.Contains(string(outputs), "private_key") {
+		t.Errorf("step outputs must never contain the private key, got:\n%s", outputs)
+	}
+
+	summary, err := os.ReadFile(summaryFile)
+	must(t, err)
+
+	if !strings.Contains(string(summary), "| control-plane | `1.2.3.4` | `10.0.0.10` |") {
+		t.Errorf("job summary missing control-plane row, got:\n%s", summary)
+	}
+}
+
+// =============================================================================
+// Test helpers
+// =============================================================================
+
+func must(t *testing.T, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+}
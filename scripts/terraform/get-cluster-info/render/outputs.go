@@ -0,0 +1,52 @@
+package render
+
+import (
+	"io"
+	"strings"
+
+	"github.com/k8s-lab/get-cluster-info/clusterinfo"
+	"github.com/k8s-lab/get-cluster-info/i18n"
+)
+
+// =============================================================================
+// Outputs table
+// =============================================================================
+
+// OutputsTable renders every Terraform output with its type, value and description.
+func OutputsTable(w io.Writer, entries []clusterinfo.OutputEntry) error {
+	nameWidth := 0
+	for _, e := range entries {
+		nameWidth = max(nameWidth, len(e.Name))
+	}
+
+	nameStyle := LabelStyle.Width(nameWidth + stylePaddingH)
+	typeStyle := LabelStyle.Width(labelWidth)
+
+	lines := []string{SectionStyle.Render(i18n.T("OUTPUTS"))}
+
+	for _, e := range entries {
+		value := clusterinfo.FormatOutputValue(e.Value)
+		if e.Sensitive && e.Value == clusterinfo.SensitivePlaceholder {
+			value = PathStyle.Render(value)
+		} else {
+			value = ValueStyle.Render(firstLine(value))
+		}
+
+		lines = append(lines, "  "+nameStyle.Render(e.Name)+typeStyle.Render(e.Type)+value)
+
+		if e.Description != "" {
+			lines = append(lines, "  "+LabelStyle.Width(0).Render("  "+e.Description))
+		}
+	}
+
+	return writeReport(w, lines)
+}
+
+// firstLine truncates multi-line values (e.g. a revealed private key) for the table view.
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i] + " " + Sym.Ellipsis
+	}
+
+	return s
+}
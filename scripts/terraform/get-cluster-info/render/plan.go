@@ -0,0 +1,83 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/k8s-lab/get-cluster-info/tfplan"
+)
+
+// =============================================================================
+// Plan summary
+// =============================================================================
+
+// actionSymbols are the markers of `terraform plan` (right-aligned), in the summary colors.
+var actionSymbols = map[tfplan.Action]string{
+	tfplan.ActionCreate:  lipgloss.NewStyle().Foreground(Green).Render("  +"),
+	tfplan.ActionUpdate:  lipgloss.NewStyle().Foreground(Yellow).Render("  ~"),
+	tfplan.ActionReplace: lipgloss.NewStyle().Foreground(Yellow).Render("-/+"),
+	tfplan.ActionDestroy: lipgloss.NewStyle().Foreground(Red).Render("  -"),
+}
+
+var destructiveStyle = lipgloss.NewStyle().Foreground(Red).Bold(true)
+
+// PlanSummary renders the changes grouped by resource type, destructive changes
+// to servers and IPs in red.
+func PlanSummary(w io.Writer, s tfplan.Summary) error {
+	lines := []string{SectionStyle.Render("PLAN")}
+
+	if !s.HasChanges() {
+		lines = append(lines, "  "+lipgloss.NewStyle().Foreground(Green).Render(Sym.OK)+
+			" No changes - the infrastructure matches the configuration")
+	}
+
+	for _, g := range s.Groups {
+		lines = append(lines, "  "+ValueStyle.Render(g.Type)+"  "+LabelStyle.Width(0).Render(formatCounts(g.Counts, "")))
+
+		for _, c := range g.Changes {
+			address, reasonStyle := c.Address, LabelStyle.Width(0)
+			if c.Destructive {
+				address, reasonStyle = destructiveStyle.Render(c.Address+" "+Sym.Warn), destructiveStyle
+			}
+
+			line := "    " + actionSymbols[c.Action] + " " + address
+			if c.Reason != "" {
+				line += reasonStyle.Render(" (" + c.Reason + ")")
+			}
+
+			lines = append(lines, line)
+		}
+	}
+
+	if s.HasChanges() {
+		lines = append(lines, "", "  Plan: "+formatCounts(s.Totals, " to "))
+	}
+
+	if s.Destructive > 0 {
+		lines = append(lines, "  "+destructiveStyle.Render(
+			fmt.Sprintf("%s %d destructive change(s) to servers or IPs - nodes or public IPs will be lost",
+				Sym.Warn, s.Destructive)))
+	}
+
+	return writeReport(w, lines)
+}
+
+// formatCounts formats the non-zero counts: "1 create, 2 destroy", or with
+// sep " to ": "1 to create, 2 to destroy".
+func formatCounts(c tfplan.Counts, sep string) string {
+	if sep == "" {
+		sep = " "
+	}
+
+	parts := []string{}
+
+	for _, a := range tfplan.Actions {
+		if n := c.Get(a); n > 0 {
+			parts = append(parts, fmt.Sprintf("%d%s%s", n, sep, a))
+		}
+	}
+
+	return strings.Join(parts, ", ")
+}
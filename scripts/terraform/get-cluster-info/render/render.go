@@ -0,0 +1,216 @@
+// Package render displays clusterinfo results: lipgloss summary box, JSON,
+// GitHub Actions outputs and summary.
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/k8s-lab/get-cluster-info/clusterinfo"
+	"github.com/k8s-lab/get-cluster-info/i18n"
+)
+
+// Renderer writes a ClusterInfo to w.
+type Renderer interface {
+	Render(w io.Writer, info *clusterinfo.ClusterInfo) error
+}
+
+// RendererFunc adapts a function to the Renderer interface.
+type RendererFunc func(w io.Writer, info *clusterinfo.ClusterInfo) error
+
+// Render implements Renderer.
+func (f RendererFunc) Render(w io.Writer, info *clusterinfo.ClusterInfo) error {
+	return f(w, info)
+}
+
+// =============================================================================
+// JSON
+// =============================================================================
+
+// JSON renders the cluster information as indented JSON.
+type JSON struct{}
+
+// Render implements Renderer.
+func (JSON) Render(w io.Writer, info *clusterinfo.ClusterInfo) error {
+	data, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(w, string(data))
+
+	return err
+}
+
+// =============================================================================
+// Summary
+// =============================================================================
+
+// Summary renders the lipgloss summary box: node IPs, SSH commands and the lab TTL.
+type Summary struct {
+	// Now returns the current time, for the remaining TTL (nil: time.Now).
+	Now func() time.Time
+}
+
+// Render implements Renderer.
+func (s Summary) Render(w io.Writer, info *clusterinfo.ClusterInfo) error {
+	nodes := info.AllNodes()
+	blocks := make([]string, 0, len(nodes)+1)
+
+	for _, n := range nodes {
+		blocks = append(blocks, fmt.Sprintf("%s\n  %s\n  %s",
+			SectionStyle.Render(strings.ToUpper(n.Name)),
+			LabelStyle.Render(i18n.T("Public IP:"))+" "+ValueStyle.Render(n.PublicIP),
+			LabelStyle.Render(i18n.T("Private IP:"))+" "+n.PrivateIP,
+		))
+	}
+
+	ssh := SectionStyle.Render(i18n.T("SSH CONNECTION")) + "\n  " +
+		LabelStyle.Render(i18n.T("Key:")) + " " + PathStyle.Render(info.SSHKeyPath)
+
+	for _, n := range nodes {
+		ssh += fmt.Sprintf("\n\n  %s:\n  %s",
+			capitalize(n.Name),
+			CmdStyle.Render(fmt.Sprintf("ssh -i %s ubuntu@%s", info.SSHKeyPath, n.PublicIP)),
+		)
+	}
+
+	blocks = append(blocks, ssh)
+
+	if info.Lifetime != nil {
+		now := time.Now
+		if s.Now != nil {
+			now = s.Now
+		}
+
+		blocks = append(blocks, SectionStyle.Render(i18n.T("LIFETIME"))+"\n  "+lifetimeLine(*info.Lifetime, now()))
+	}
+
+	_, err := fmt.Fprintf(w, "\n%s\n%s\n\n",
+		TitleStyle.Render(Sym.Title+i18n.T("K8S-LAB CLUSTER")),
+		BoxStyle.Render(strings.Join(blocks, "\n\n")),
+	)
+
+	return err
+}
+
+// =============================================================================
+// Plain
+// =============================================================================
+
+// Plain renders the summary as plain text, without box, emoji nor ANSI codes,
+// for CI logs and pipes (--plain).
+type Plain struct {
+	// Now returns the current time, for the remaining TTL (nil: time.Now).
+	Now func() time.Time
+}
+
+// Render implements Renderer.
+func (p Plain) Render(w io.Writer, info *clusterinfo.ClusterInfo) error {
+	var b strings.Builder
+
+	b.WriteString(i18n.T("K8S-LAB CLUSTER") + "\n")
+
+	nodes := info.AllNodes()
+	for _, n := range nodes {
+		fmt.Fprintf(&b, "\n%s\n  %s%s\n  %s%s\n", strings.ToUpper(n.Name),
+			padRight(i18n.T("Public IP:"), labelWidth), n.PublicIP,
+			padRight(i18n.T("Private IP:"), labelWidth), n.PrivateIP)
+	}
+
+	sshWidth := labelWidth
+	for _, n := range nodes {
+		sshWidth = max(sshWidth, utf8.RuneCountInString(n.Name)+len(": "))
+	}
+
+	fmt.Fprintf(&b, "\n%s\n  %s%s\n", i18n.T("SSH CONNECTION"), padRight(i18n.T("Key:"), sshWidth), info.SSHKeyPath)
+
+	for _, n := range nodes {
+		fmt.Fprintf(&b, "  %sssh -i %s ubuntu@%s\n", padRight(capitalize(n.Name)+":", sshWidth), info.SSHKeyPath, n.PublicIP)
+	}
+
+	if info.Lifetime != nil {
+		now := time.Now
+		if p.Now != nil {
+			now = p.Now
+		}
+
+		fmt.Fprintf(&b, "\n%s\n  %s\n", i18n.T("LIFETIME"), lifetimeText(*info.Lifetime, now()))
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+// lifetimeText is lifetimeLine without styles.
+func lifetimeText(l clusterinfo.Lifetime, now time.Time) string {
+	remaining := l.Remaining(now)
+	expires := l.ExpiresAt.UTC().Format("2006-01-02 15:04 UTC")
+
+	if l.Expired(now) {
+		return i18n.Sprintf("EXPIRED %s ago (%s)", FormatDuration(-remaining), expires) +
+			i18n.T(" - run ") + "get-cluster-info reap"
+	}
+
+	return i18n.Sprintf("Expires in %s (%s)", FormatDuration(remaining), expires)
+}
+
+// padRight pads s with spaces to width runes.
+func padRight(s string, width int) string {
+	return s + strings.Repeat(" ", max(0, width-utf8.RuneCountInString(s)))
+}
+
+const (
+	// lifetimeWarning is the remaining TTL below which the lifetime is highlighted.
+	lifetimeWarning = time.Hour
+	day             = 24 * time.Hour
+)
+
+// lifetimeLine describes the remaining TTL: yellow within the last hour, red once expired.
+func lifetimeLine(l clusterinfo.Lifetime, now time.Time) string {
+	remaining := l.Remaining(now)
+	expires := l.ExpiresAt.UTC().Format("2006-01-02 15:04 UTC")
+
+	switch {
+	case l.Expired(now):
+		return lipgloss.NewStyle().Foreground(Red).Render(
+			i18n.Sprintf("EXPIRED %s ago (%s)", FormatDuration(-remaining), expires)) +
+			i18n.T(" - run ") + CmdStyle.Render("get-cluster-info reap")
+	case remaining < lifetimeWarning:
+		return lipgloss.NewStyle().Foreground(Yellow).Render(
+			i18n.Sprintf("Expires in %s (%s)", FormatDuration(remaining), expires))
+	default:
+		return i18n.Sprintf("Expires in %s (%s)", ValueStyle.Render(FormatDuration(remaining)), expires)
+	}
+}
+
+// FormatDuration rounds d to the minute for display: "2d 3h", "5h 12m", "42m".
+func FormatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	days := d / day
+	hours := (d % day) / time.Hour
+	minutes := (d % time.Hour) / time.Minute
+
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd %dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	default:
+		return fmt.Sprintf("%dm", minutes)
+	}
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+
+	return strings.ToUpper(s[:1]) + s[1:]
+}
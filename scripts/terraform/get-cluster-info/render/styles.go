@@ -0,0 +1,166 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// =============================================================================
+// Constants
+// =============================================================================
+
+const (
+	// Style constants
+	stylePaddingH = 2
+	stylePaddingV = 1
+	labelWidth    = 14
+)
+
+// =============================================================================
+// Lip Gloss Styles
+// =============================================================================
+
+var (
+	// Colors
+	Cyan   = lipgloss.Color("86")
+	Green  = lipgloss.Color("42")
+	Yellow = lipgloss.Color("214")
+	Red    = lipgloss.Color("196")
+	Blue   = lipgloss.Color("39")
+
+	// Summary styles
+	TitleStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("15")).
+			Background(lipgloss.Color("62")).
+			Padding(0, stylePaddingH).
+			MarginBottom(1)
+
+	SectionStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(Cyan).
+			MarginTop(1)
+
+	LabelStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("245")).
+			Width(labelWidth)
+
+	ValueStyle = lipgloss.NewStyle().
+			Foreground(Green).
+			Bold(true)
+
+	PathStyle = lipgloss.NewStyle().
+			Foreground(Yellow)
+
+	CmdStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("252")).
+			Background(lipgloss.Color("236")).
+			Padding(0, 1)
+
+	BoxStyle = lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("62")).
+			Padding(stylePaddingV, stylePaddingH).
+			MarginTop(1)
+)
+
+// =============================================================================
+// Symbols
+// =============================================================================
+
+// Symbols holds the non-ASCII characters of the output.
+type Symbols struct {
+	OK, Warn, Fail, Info, Time   string
+	Arrow, Times, Rule, Ellipsis string
+	// Title precedes the summary title (an emoji, or nothing).
+	Title string
+}
+
+var (
+	// UnicodeSymbols are used by default.
+	UnicodeSymbols = Symbols{
+		OK: "✓", Warn: "⚠", Fail: "✗", Info: "ℹ", Time: "⏱",
+		Arrow: "→", Times: "×", Rule: "─", Ellipsis: "…",
+		Title: "🚀 ",
+	}
+
+	// ASCIISymbols replace them on terminals without Unicode, and with --plain.
+	ASCIISymbols = Symbols{
+		OK: "+", Warn: "!", Fail: "x", Info: "i", Time: "~",
+		Arrow: "->", Times: "x", Rule: "-", Ellipsis: "...",
+	}
+
+	// Sym is the symbol set in use (see SetASCII).
+	Sym = UnicodeSymbols
+
+	asciiBorder = lipgloss.Border{
+		Top: "-", Bottom: "-", Left: "|", Right: "|",
+		TopLeft: "+", TopRight: "+", BottomLeft: "+", BottomRight: "+",
+	}
+)
+
+// SetASCII switches the symbols and the summary box border to ASCII (or back to Unicode).
+func SetASCII(ascii bool) {
+	if ascii {
+		Sym = ASCIISymbols
+		BoxStyle = BoxStyle.Border(asciiBorder)
+
+		return
+	}
+
+	Sym = UnicodeSymbols
+	BoxStyle = BoxStyle.Border(lipgloss.RoundedBorder())
+}
+
+// plain drops the boxes of the reports (see SetPlain).
+var plain bool
+
+// SetPlain renders the reports as plain text, without box, margin nor padding
+// around the commands (or back to the boxes). Colors are up to the color profile.
+func SetPlain(p bool) {
+	plain = p
+
+	if p {
+		CmdStyle = CmdStyle.Padding(0)
+		SectionStyle = SectionStyle.MarginTop(0)
+
+		return
+	}
+
+	CmdStyle = CmdStyle.Padding(0, 1)
+	SectionStyle = SectionStyle.MarginTop(1)
+}
+
+// writeReport writes the lines of a report in a box, or as is with SetPlain.
+func writeReport(w io.Writer, lines []string) error {
+	report := strings.Join(lines, "\n")
+	if !plain {
+		report = BoxStyle.Render(report)
+	}
+
+	_, err := fmt.Fprintf(w, "%s\n\n", report)
+
+	return err
+}
+
+// SupportsUnicode reports whether the terminal can display Unicode: the locale
+// (LC_ALL, then LC_CTYPE, then LANG) is UTF-8 or unset, and TERM is not the
+// Linux console, whose font has no emoji nor box-drawing glyphs.
+func SupportsUnicode(getenv func(string) string) bool {
+	if getenv("TERM") == "linux" {
+		return false
+	}
+
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if locale := getenv(name); locale != "" {
+			locale = strings.ToLower(locale)
+
+			return strings.Contains(locale, "utf-8") || strings.Contains(locale, "utf8")
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,75 @@
+package render
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/k8s-lab/get-cluster-info/doctor"
+)
+
+// =============================================================================
+// SupportsUnicode tests
+// =============================================================================
+
+func TestSupportsUnicode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		env  map[string]string
+		want bool
+	}{
+		{name: "no locale", env: map[string]string{}, want: true},
+		{name: "UTF-8 LANG", env: map[string]string{"LANG": "fr_FR.UTF-8"}, want: true},
+		{name: "utf8 spelling", env: map[string]string{"LANG": "en_US.utf8"}, want: true},
+		{name: "C locale", env: map[string]string{"LANG": "C"}, want: false},
+		{name: "LC_ALL wins over LANG", env: map[string]string{"LC_ALL": "POSIX", "LANG": "en_US.UTF-8"}, want: false},
+		{name: "LC_CTYPE wins over LANG", env: map[string]string{"LC_CTYPE": "C.UTF-8", "LANG": "C"}, want: true},
+		{name: "Linux console", env: map[string]string{"TERM": "linux", "LANG": "en_US.UTF-8"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := SupportsUnicode(func(k string) string { return tt.env[k] }); got != tt.want {
+				t.Errorf("SupportsUnicode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// =============================================================================
+// SetPlain tests
+// =============================================================================
+
+func TestSetPlain(t *testing.T) {
+	// Not parallel - changes the styles
+
+	report := doctor.Report{Checks: []doctor.Result{
+		{Name: "terraform", Status: doctor.StatusFail, Message: "not found", Hint: "install terraform"},
+	}}
+
+	render := func() string {
+		var buf bytes.Buffer
+		if err := DoctorReport(&buf, report); err != nil {
+			t.Fatalf("DoctorReport() unexpected error = %v", err)
+		}
+
+		return buf.String()
+	}
+
+	t.Cleanup(func() { SetPlain(false) })
+
+	if boxed := render(); !strings.Contains(boxed, "╭") {
+		t.Errorf("report not boxed:\n%s", boxed)
+	}
+
+	SetPlain(true)
+
+	want := "DOCTOR\n  ✗ terraform  not found\n      → install terraform\n\n  0 ok, 0 warning(s), 1 failure(s)\n\n"
+	if got := render(); got != want {
+		t.Errorf("plain report = %q, want %q", got, want)
+	}
+}
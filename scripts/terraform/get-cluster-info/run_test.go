@@ -0,0 +1,451 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/k8s-lab/get-cluster-info/clusterinfo"
+	"github.com/k8s-lab/get-cluster-info/clusterinfo/clusterinfotest"
+	"github.com/k8s-lab/get-cluster-info/i18n"
+	"github.com/k8s-lab/get-cluster-info/render"
+	"github.com/muesli/termenv"
+)
+
+// =============================================================================
+// End-to-end tests (run / executeAndDisplay with a fake Terraform client)
+// =============================================================================
+//
+// Golden files live in testdata/. Regenerate them after an intended change with:
+//   go test -run TestRunGolden -update
+
+var update = flag.Bool("update", false, "update golden files in testdata/")
+
+// testNow is the current time of the tests (lab TTL).
+var testNow = time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func TestMain(m *testing.M) {
+	// Golden files are compared without ANSI escape codes.
+	lipgloss.SetColorProfile(termenv.Ascii)
+
+	os.Exit(m.Run())
+}
+
+func TestRunGolden(t *testing.T) {
+	// Not parallel - modifies global config
+
+	tests := []struct {
+		name    string
+		fixture string
+		json    bool
+		lenient bool
+		plain   bool
+		ascii   bool          // terminal without Unicode
+		lang    i18n.Language // default: English
+		ttl     time.Duration // remaining at testNow, negative once expired
+	}{
+		{name: "deployed_summary", fixture: clusterinfotest.FixtureDeployed},
+		{name: "deployed_json", fixture: clusterinfotest.FixtureDeployed, json: true},
+		{name: "half_applied_lenient", fixture: clusterinfotest.FixtureHalfApplied, lenient: true},
+		{name: "deployed_ttl_summary", fixture: clusterinfotest.FixtureDeployed, ttl: 5*time.Hour + 20*time.Minute},
+		{name: "deployed_ttl_expiring_summary", fixture: clusterinfotest.FixtureDeployed, ttl: 42 * time.Minute},
+		{name: "deployed_ttl_expired_summary", fixture: clusterinfotest.FixtureDeployed, ttl: -90 * time.Minute},
+		{name: "deployed_ttl_json", fixture: clusterinfotest.FixtureDeployed, json: true, ttl: 8 * time.Hour},
+		{name: "deployed_plain", fixture: clusterinfotest.FixtureDeployed, plain: true},
+		{name: "deployed_ttl_expired_plain", fixture: clusterinfotest.FixtureDeployed, plain: true, ttl: -90 * time.Minute},
+		{name: "deployed_ascii_summary", fixture: clusterinfotest.FixtureDeployed, ascii: true},
+		{
+			name: "deployed_ttl_fr_summary", fixture: clusterinfotest.FixtureDeployed,
+			lang: i18n.French, ttl: 42 * time.Minute,
+		},
+		{
+			name: "deployed_ttl_fr_plain", fixture: clusterinfotest.FixtureDeployed,
+			lang: i18n.French, plain: true, ttl: 8 * time.Hour,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, _ := setupRun(t, tt.fixture)
+			config.SSHKeyPath = "/home/lab/.ssh/k8s-lab.pem"
+			config.NoSaveKey = true
+			config.JSONOutput = tt.json
+			config.Lenient = tt.lenient
+			config.Plain = tt.plain
+
+			if tt.lang != "" {
+				i18n.Set(tt.lang)
+				t.Cleanup(func() { i18n.Set(i18n.English) })
+			}
+
+			if tt.ascii {
+				render.SetASCII(true)
+				t.Cleanup(func() { render.SetASCII(false) })
+			}
+
+			if tt.ttl != 0 {
+				l := clusterinfo.NewLifetime(testNow.Add(-2*time.Hour), 2*time.Hour+tt.ttl, "alice", nil)
+				lifetimeStore = &clusterinfotest.MemoryLifetimeStore{Value: &l}
+			}
+
+			if err := run(nil, nil); err != nil {
+				t.Fatalf("run() unexpected error = %v", err)
+			}
+
+			assertGolden(t, tt.name, out.Bytes())
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	// Not parallel - modifies global config
+
+	errBoom := errors.New("boom")
+
+	tests := []struct {
+		name        string
+		fixture     string
+		setup       func(t *testing.T, fake *clusterinfotest.FakeTerraform)
+		wantErr     error
+		errContains string
+		wantCalls   []string
+		wantOutput  string
+	}{
+		{
+			name:       "deployed - prints summary",
+			fixture:    clusterinfotest.FixtureDeployed,
+			wantCalls:  []string{"init", "output"},
+			wantOutput: "ssh -i ",
+		},
+		{
+			name:    "not deployed - returns ErrNotDeployed",
+			fixture: clusterinfotest.FixtureNotDeployed,
+			wantErr: clusterinfo.ErrNotDeployed,
+		},
+		{
+			name:        "half applied - strict mode suggests --lenient",
+			fixture:     clusterinfotest.FixtureHalfApplied,
+			errContains: "Use --lenient",
+		},
+		{
+			name:    "no-init - skips terraform init",
+			fixture: clusterinfotest.FixtureDeployed,
+			setup: func(t *testing.T, _ *clusterinfotest.FakeTerraform) {
+				t.Helper()
+				config.NoInit = true
+			},
+			wantCalls: []string{"output"},
+		},
+		{
+			name:    "init fails - returns error",
+			fixture: clusterinfotest.FixtureDeployed,
+			setup: func(t *testing.T, fake *clusterinfotest.FakeTerraform) {
+				t.Helper()
+				fake.InitErr = errBoom
+			},
+			wantErr:   errBoom,
+			wantCalls: []string{"init"},
+		},
+		{
+			name:    "output fails - returns error",
+			fixture: clusterinfotest.FixtureDeployed,
+			setup: func(t *testing.T, fake *clusterinfotest.FakeTerraform) {
+				t.Helper()
+				fake.OutputErr = errBoom
+			},
+			wantErr: errBoom,
+		},
+		{
+			name:    "credentials file missing - returns error before terraform",
+			fixture: clusterinfotest.FixtureDeployed,
+			setup: func(t *testing.T, _ *clusterinfotest.FakeTerraform) {
+				t.Helper()
+				must(t, os.Remove(config.CredentialsFile))
+			},
+			errContains: "credentials file not found",
+			wantCalls:   []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, fake := setupRun(t, tt.fixture)
+			if tt.setup != nil {
+				tt.setup(t, fake)
+			}
+
+			err := run(nil, nil)
+
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("run() error = %v, want %v", err, tt.wantErr)
+				}
+			case tt.errContains != "":
+				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("run() error = %v, want containing %q", err, tt.errContains)
+				}
+			case err != nil:
+				t.Fatalf("run() unexpected error = %v", err)
+			}
+
+			if tt.wantCalls != nil && !slices.Equal(fake.Calls(), tt.wantCalls) {
+				t.Errorf("terraform calls = %v, want %v", fake.Calls(), tt.wantCalls)
+			}
+
+			if !strings.Contains(out.String(), tt.wantOutput) {
+				t.Errorf("output = %q, want containing %q", out.String(), tt.wantOutput)
+			}
+		})
+	}
+}
+
+func TestRunSavesSSHKey(t *testing.T) {
+	// Not parallel - modifies global config
+
+	tests := []struct {
+		name      string
+		fixture   string
+		noSaveKey bool
+		wantSaved bool
+	}{
+		{name: "deployed - key saved", fixture: clusterinfotest.FixtureDeployed, wantSaved: true},
+		{name: "no-save-key - key not saved", fixture: clusterinfotest.FixtureDeployed, noSaveKey: true},
+		{name: "no key in state - warning only", fixture: clusterinfotest.FixtureHalfApplied},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupRun(t, tt.fixture)
+			config.NoSaveKey = tt.noSaveKey
+			config.Lenient = true
+
+			if err := run(nil, nil); err != nil {
+				t.Fatalf("run() unexpected error = %v", err)
+			}
+
+			stat, err := os.Stat(config.SSHKeyPath)
+			if !tt.wantSaved {
+				if err == nil {
+					t.Errorf("SSH key unexpectedly written to %s", config.SSHKeyPath)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("SSH key not written: %v", err)
+			}
+
+			if perm := stat.Mode().Perm(); perm != filePermissions {
+				t.Errorf("SSH key permissions = %o, want %o", perm, filePermissions)
+			}
+
+			data, err := os.ReadFile(config.SSHKeyPath)
+			must(t, err)
+
+			if !strings.Contains(string(data), "FAKE-KEY-FOR-TESTS") {
+				t.Errorf("SSH key content = %q", data)
+			}
+		})
+	}
+}
+
+func TestRunVerbose(t *testing.T) {
+	// Not parallel - modifies global config
+
+	tests := []struct {
+		name      string
+		verbose   bool
+		noInit    bool
+		wantSteps []string
+	}{
+		{name: "verbose - times every step", verbose: true, wantSteps: []string{"init", "fetch", "render"}},
+		{name: "verbose no-init - no init timing", verbose: true, noInit: true, wantSteps: []string{"fetch", "render"}},
+		{name: "not verbose - silent", wantSteps: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, _ := setupRun(t, clusterinfotest.FixtureDeployed)
+			config.Verbose = tt.verbose
+			config.NoInit = tt.noInit
+
+			errOut := &bytes.Buffer{}
+			stderr = errOut
+
+			if err := run(nil, nil); err != nil {
+				t.Fatalf("run() unexpected error = %v", err)
+			}
+
+			var steps []string
+
+			for _, line := range strings.Split(strings.TrimSpace(errOut.String()), "\n") {
+				if fields := strings.Fields(line); len(fields) >= 3 && fields[2] == "took" {
+					steps = append(steps, fields[1])
+				}
+			}
+
+			if !slices.Equal(steps, tt.wantSteps) {
+				t.Errorf("timed steps = %v, want %v (stderr %q)", steps, tt.wantSteps, errOut.String())
+			}
+
+			// Timings never pollute stdout (e.g. --json output).
+			if strings.Contains(out.String(), " took ") {
+				t.Errorf("stdout contains timings: %q", out.String())
+			}
+		})
+	}
+}
+
+func TestRunGitHubActions(t *testing.T) {
+	// Not parallel - modifies global config and environment
+
+	out, _ := setupRun(t, clusterinfotest.FixtureDeployed)
+	errOut := &bytes.Buffer{}
+	stderr = errOut
+
+	outputFile := filepath.Join(t.TempDir(), "github_output")
+	summaryFile := filepath.Join(t.TempDir(), "github_summary")
+	t.Setenv("GITHUB_OUTPUT", outputFile)
+	t.Setenv("GITHUB_STEP_SUMMARY", summaryFile)
+
+	if err := run(nil, nil); err != nil {
+		t.Fatalf("run() unexpected error = %v", err)
+	}
+
+	outputs, err := os.ReadFile(outputFile)
+	must(t, err)
+
+	for _, want := range []string{"control_plane_public_ip=203.0.113.10", "worker_private_ip=10.0.0.11"} {
+		if !strings.Contains(string(outputs), want) {
+			t.Errorf("GITHUB_OUTPUT = %q, want containing %q", outputs, want)
+		}
+	}
+
+	if strings.Contains(string(outputs), "FAKE-KEY-FOR-TESTS") {
+		t.Error("GITHUB_OUTPUT leaks the SSH private key")
+	}
+
+	// Credentials and every line of the SSH key are masked in the logs, from stderr.
+	for _, want := range []string{"::add-mask::AKIATEST", "::add-mask::FAKE-KEY-FOR-TESTS"} {
+		if !strings.Contains(errOut.String(), want) {
+			t.Errorf("stderr = %q, want containing %q", errOut.String(), want)
+		}
+	}
+
+	if strings.Contains(out.String(), "::add-mask::") {
+		t.Errorf("stdout contains mask commands: %q", out.String())
+	}
+
+	if _, err := os.Stat(summaryFile); err != nil {
+		t.Errorf("job summary not written: %v", err)
+	}
+}
+
+func TestOutputsMasksRevealedValues(t *testing.T) {
+	// Not parallel - modifies global config and environment
+
+	out, _ := setupRun(t, clusterinfotest.FixtureDeployed)
+	errOut := &bytes.Buffer{}
+	stderr = errOut
+	t.Setenv("GITHUB_ACTIONS", "true")
+
+	showSensitive = true
+	config.JSONOutput = true
+	t.Cleanup(func() { showSensitive = false })
+
+	if err := runOutputs(nil, nil); err != nil {
+		t.Fatalf("runOutputs() unexpected error = %v", err)
+	}
+
+	// Every line of the key is masked, on stderr.
+	for _, line := range []string{"FAKE-KEY-FOR-TESTS", "NOT-A-REAL-PRIVATE-KEY"} {
+		if !strings.Contains(errOut.String(), "::add-mask::"+line) {
+			t.Errorf("stderr = %q, want %q masked", errOut.String(), line)
+		}
+	}
+
+	if strings.Contains(errOut.String(), "::add-mask::203.0.113.10") {
+		t.Error("non-sensitive output masked")
+	}
+
+	// stdout stays valid JSON, e.g. for jq.
+	var entries []clusterinfo.OutputEntry
+	if err := json.Unmarshal(out.Bytes(), &entries); err != nil {
+		t.Errorf("stdout is not valid JSON: %v\n%s", err, out)
+	}
+}
+
+// =============================================================================
+// Test helpers
+// =============================================================================
+
+// setupRun points the global config at a temporary terraform directory served by
+// a fake Terraform client, and captures stdout. Everything is restored on cleanup.
+func setupRun(t *testing.T, fixture string) (*bytes.Buffer, *clusterinfotest.FakeTerraform) {
+	t.Helper()
+
+	t.Setenv("GITHUB_OUTPUT", "")
+	t.Setenv("GITHUB_STEP_SUMMARY", "")
+
+	tmpDir := t.TempDir()
+	terraformDir := filepath.Join(tmpDir, "terraform")
+	must(t, os.MkdirAll(terraformDir, 0o755))
+
+	credsFile := filepath.Join(terraformDir, "backend.yaml")
+	must(t, os.WriteFile(credsFile, []byte("access_key: AKIATEST\nsecret_key: secretTEST\n"), 0o600))
+
+	fake := clusterinfotest.MustFixture(t, fixture)
+	out := &bytes.Buffer{}
+
+	oldConfig, oldClient, oldStdout, oldStderr := config, terraformClient, stdout, stderr
+	oldStore, oldNow := lifetimeStore, now
+	config = Config{
+		TerraformDir:    terraformDir,
+		CredentialsFile: credsFile,
+		SSHKeyPath:      filepath.Join(tmpDir, ".ssh", "k8s-lab.pem"),
+		Quiet:           true,
+	}
+	terraformClient = fake
+	stdout = out
+	stderr = &bytes.Buffer{}
+	lifetimeStore = &clusterinfotest.MemoryLifetimeStore{}
+	now = func() time.Time { return testNow }
+
+	t.Cleanup(func() {
+		config, terraformClient, stdout, stderr = oldConfig, oldClient, oldStdout, oldStderr
+		lifetimeStore, now = oldStore, oldNow
+	})
+
+	return out, fake
+}
+
+// assertGolden compares got with testdata/<name>.golden, rewriting it with -update.
+func assertGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name+".golden")
+
+	if *update {
+		must(t, os.MkdirAll("testdata", 0o755))
+		must(t, os.WriteFile(path, got, 0o644))
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file (run with -update to create it): %v", err)
+	}
+
+	if !bytes.Equal(got, want) {
+		t.Errorf("output differs from %s:\n--- got ---\n%s\n--- want ---\n%s", path, got, want)
+	}
+}
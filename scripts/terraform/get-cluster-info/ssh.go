@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/k8s-lab/get-cluster-info/clusterinfo"
+	"github.com/k8s-lab/get-cluster-info/i18n"
+	"github.com/k8s-lab/get-cluster-info/remote"
+)
+
+// =============================================================================
+// SSH connections to the nodes
+// =============================================================================
+
+// knownHostsFile records the host keys of the lab nodes, next to the SSH key, so
+// that the IPs reused by successive labs do not pollute ~/.ssh/known_hosts.
+const knownHostsFile = "k8s-lab_known_hosts"
+
+// openCluster reads the cluster information from the state and returns it with
+// the SSH dialer of the nodes. close releases the Terraform source.
+func openCluster(ctx context.Context) (*clusterinfo.ClusterInfo, remote.Dialer, func(), error) {
+	tfSrc, err := setupEnvironment(ctx)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	closeSrc := func() { _ = tfSrc.Close() }
+	src := clusterinfo.NewCachedSource(tfSrc)
+
+	info, err := loadClusterInfo(ctx, src)
+	if err != nil {
+		closeSrc()
+
+		return nil, nil, nil, err
+	}
+
+	dialer, err := nodeDialer(ctx, src)
+	if err != nil {
+		closeSrc()
+
+		return nil, nil, nil, err
+	}
+
+	return info, dialer, closeSrc, nil
+}
+
+// nodeDialer returns the SSH dialer of the nodes: the remoteDialer test hook, or
+// SSH with the private key read from the state.
+func nodeDialer(ctx context.Context, src clusterinfo.StateSource) (remote.Dialer, error) {
+	if remoteDialer != nil {
+		return remoteDialer, nil
+	}
+
+	key, err := clusterinfo.ReadSSHKey(ctx, src, config.OutputMap[clusterinfo.FieldSSHPrivateKey])
+	if err != nil {
+		return nil, i18n.Errorf("failed to read the SSH key: %w", err)
+	}
+
+	return remote.SSHDialer{
+		PrivateKey:     []byte(key),
+		KnownHostsPath: knownHostsPath(),
+	}, nil
+}
+
+// knownHostsPath returns the known_hosts file of the lab nodes.
+func knownHostsPath() string {
+	return filepath.Join(filepath.Dir(config.SSHKeyPath), knownHostsFile)
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/k8s-lab/get-cluster-info/bundle"
+	"github.com/k8s-lab/get-cluster-info/i18n"
+	"github.com/spf13/cobra"
+)
+
+// =============================================================================
+// support-bundle subcommand
+// =============================================================================
+//
+// Collects the logs and state of every node (see the bundle package) into a
+// timestamped tar.gz to attach to a bug report, instead of pasting logs from
+// many places.
+
+var supportBundleDir string
+
+var supportBundleCmd = &cobra.Command{
+	Use:   "support-bundle",
+	Short: "Collect the logs of every node into a tar.gz",
+	Long: `Collect the logs of every node into a tar.gz.
+
+From every node, over SSH:
+  - journalctl of kubelet and containerd, cloud-init logs
+  - crictl ps -a, ip addr, ip route, sysctl -a
+  - the kubeadm configuration (control plane)
+plus the cluster information with the public IPs redacted.
+
+Secrets (private keys, join tokens, passwords...) and the public IPs are
+scrubbed from every file. What cannot be collected is listed in errors.txt:
+the bundle is still written when a node is unreachable.
+
+Examples:
+  # Write k8s-lab-support-<date>.tar.gz in the current directory
+  get-cluster-info support-bundle
+
+  get-cluster-info support-bundle --output /tmp`,
+	Args: cobra.NoArgs,
+	RunE: runSupportBundle,
+}
+
+func init() {
+	supportBundleCmd.Flags().StringVarP(&supportBundleDir, "output", "o", ".", "Directory where to write the bundle")
+
+	rootCmd.AddCommand(supportBundleCmd)
+}
+
+func runSupportBundle(_ *cobra.Command, _ []string) error {
+	ctx := context.Background()
+
+	info, dialer, closeSrc, err := openCluster(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer closeSrc()
+
+	logInfo("Collecting the support bundle from %d node(s)...", len(info.AllNodes()))
+
+	current := ""
+	files := bundle.Collect(ctx, info, bundle.Options{Dialer: dialer, Progress: func(node, _ string) {
+		if node != current {
+			current = node
+			logInfo("[%s] collecting logs...", node)
+		}
+	}})
+
+	created := now()
+	path := filepath.Join(supportBundleDir, bundle.Name(created)+".tar.gz")
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return i18n.Errorf("failed to create the bundle: %w", err)
+	}
+
+	if err := bundle.Write(f, files, created); err != nil {
+		_ = f.Close()
+		_ = os.Remove(path)
+
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return i18n.Errorf("failed to write the bundle: %w", err)
+	}
+
+	if slices.ContainsFunc(files, func(f bundle.File) bool { return f.Path == bundle.ErrorsFile }) {
+		logWarning("Some items could not be collected: see %s in the bundle", bundle.ErrorsFile)
+	}
+
+	logSuccess("Support bundle written to %s (%d files, secrets scrubbed)", path, len(files))
+
+	return nil
+}
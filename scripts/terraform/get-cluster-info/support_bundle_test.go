@@ -0,0 +1,75 @@
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/k8s-lab/get-cluster-info/bundle"
+)
+
+// =============================================================================
+// support-bundle subcommand tests
+// =============================================================================
+
+func TestRunSupportBundle(t *testing.T) {
+	// Not parallel - modifies global config
+	_, out := setupBootstrap(t, func(host, cmd string) ([]byte, error) {
+		return []byte(host + ": " + cmd + "\n"), nil
+	})
+
+	oldDir := supportBundleDir
+	t.Cleanup(func() { supportBundleDir = oldDir })
+
+	supportBundleDir = t.TempDir()
+
+	must(t, runSupportBundle(nil, nil))
+
+	path := filepath.Join(supportBundleDir, "k8s-lab-support-2025-03-01T120000Z.tar.gz")
+	if !strings.Contains(out(), "Support bundle written to "+path) {
+		t.Errorf("output missing the bundle path:\n%s", out())
+	}
+
+	f, err := os.Open(path)
+	must(t, err)
+
+	defer func() { _ = f.Close() }()
+
+	gz, err := gzip.NewReader(f)
+	must(t, err)
+
+	tr := tar.NewReader(gz)
+	files := map[string]string{}
+
+	for {
+		h, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		must(t, err)
+
+		data, err := io.ReadAll(tr)
+		must(t, err)
+
+		files[strings.TrimPrefix(h.Name, bundle.Name(testNow)+"/")] = string(data)
+	}
+
+	if got := files["worker/ip-route.txt"]; got != "<public-ip:worker>: ip route\n" {
+		t.Errorf("worker/ip-route.txt = %q, want the public IP scrubbed", got)
+	}
+
+	if !strings.Contains(files[bundle.ClusterInfoFile], `"private_ip": "10.0.0.10"`) {
+		t.Errorf("cluster-info.json = %s", files[bundle.ClusterInfoFile])
+	}
+
+	// A second bundle in the same second does not overwrite the first one.
+	if err := runSupportBundle(nil, nil); err == nil {
+		t.Error("runSupportBundle() overwrote an existing bundle")
+	}
+}
@@ -0,0 +1,172 @@
+package tfplan
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+// =============================================================================
+// Drift
+// =============================================================================
+
+// ErrDrift is returned when the deployed infrastructure differs from the state.
+var ErrDrift = errors.New("drift detected")
+
+// sensitiveValue replaces sensitive attribute values in diffs, and
+// sensitiveChanged the new value of a sensitive attribute that changed.
+const (
+	sensitiveValue   = "(sensitive)"
+	sensitiveChanged = "(sensitive value changed)"
+)
+
+// masked is a sensitive attribute value: never printed, only compared.
+type masked struct {
+	value any
+}
+
+// AttributeDiff is an attribute changed outside Terraform. Before and After are
+// JSON encoded ("null" when the attribute was added or removed).
+type AttributeDiff struct {
+	Path   string `json:"path"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// DriftedResource is a resource whose real state differs from the recorded state:
+// updated (with the attribute diffs) or deleted outside Terraform.
+type DriftedResource struct {
+	Address string          `json:"address"`
+	Type    string          `json:"type"`
+	Action  Action          `json:"action"`
+	Diffs   []AttributeDiff `json:"diffs"`
+}
+
+// DriftReport lists the drifted resources of a refresh-only plan.
+type DriftReport struct {
+	Resources []DriftedResource `json:"resources"`
+}
+
+// Drift reads the resource_drift of a refresh-only plan. A nil plan has no drift.
+func Drift(p *tfjson.Plan) DriftReport {
+	r := DriftReport{Resources: []DriftedResource{}}
+	if p == nil {
+		return r
+	}
+
+	for _, rc := range p.ResourceDrift {
+		action, ok := actionOf(rc)
+		if !ok {
+			continue
+		}
+
+		// A deleted resource has no attributes left to compare.
+		diffs := []AttributeDiff{}
+		if action != ActionDestroy {
+			diffs = attributeDiffs(rc.Change)
+		}
+
+		r.Resources = append(r.Resources, DriftedResource{
+			Address: rc.Address,
+			Type:    rc.Type,
+			Action:  action,
+			Diffs:   diffs,
+		})
+	}
+
+	return r
+}
+
+// attributeDiffs compares the leaf attributes before and after the refresh, sorted by path.
+func attributeDiffs(c *tfjson.Change) []AttributeDiff {
+	before, after := map[string]any{}, map[string]any{}
+	flatten("", c.Before, c.BeforeSensitive, before)
+	flatten("", c.After, c.AfterSensitive, after)
+
+	paths := make([]string, 0, len(before)+len(after))
+	for p := range before {
+		paths = append(paths, p)
+	}
+
+	for p := range after {
+		if _, ok := before[p]; !ok {
+			paths = append(paths, p)
+		}
+	}
+
+	slices.Sort(paths)
+
+	diffs := []AttributeDiff{}
+
+	for _, p := range paths {
+		b, a := before[p], after[p]
+		if reflect.DeepEqual(b, a) {
+			continue
+		}
+
+		d := AttributeDiff{Path: p, Before: encode(b), After: encode(a)}
+		if d.Before == sensitiveValue && d.After == sensitiveValue {
+			d.After = sensitiveChanged
+		}
+
+		diffs = append(diffs, d)
+	}
+
+	return diffs
+}
+
+// flatten stores the leaf values of v by path ("tags[3]", "root_volume.size_in_gb").
+// Values marked true in the parallel sensitive tree are masked.
+func flatten(prefix string, v, sensitive any, out map[string]any) {
+	if sensitive == true {
+		out[prefix] = masked{value: v}
+
+		return
+	}
+
+	switch val := v.(type) {
+	case map[string]any:
+		sens, _ := sensitive.(map[string]any)
+
+		for k, child := range val {
+			path := k
+			if prefix != "" {
+				path = prefix + "." + k
+			}
+
+			flatten(path, child, sens[k], out)
+		}
+	case []any:
+		sens, _ := sensitive.([]any)
+
+		for i, child := range val {
+			var childSens any
+			if i < len(sens) {
+				childSens = sens[i]
+			}
+
+			flatten(fmt.Sprintf("%s[%d]", prefix, i), child, childSens, out)
+		}
+	default:
+		if prefix != "" {
+			out[prefix] = val
+		}
+	}
+}
+
+func encode(v any) string {
+	if _, ok := v.(masked); ok {
+		return sensitiveValue
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+
+	return string(data)
+}
@@ -0,0 +1,116 @@
+package tfplan
+
+import (
+	"slices"
+	"testing"
+
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/k8s-lab/get-cluster-info/clusterinfo/clusterinfotest"
+)
+
+// =============================================================================
+// Drift tests
+// =============================================================================
+
+func TestDrift(t *testing.T) {
+	t.Parallel()
+
+	plan, err := clusterinfotest.LoadPlanFixture(clusterinfotest.FixturePlanDrift)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := Drift(plan)
+
+	if len(r.Resources) != 2 {
+		t.Fatalf("Drift() = %d resource(s), want 2: %+v", len(r.Resources), r.Resources)
+	}
+
+	ip := r.Resources[0]
+	if ip.Action != ActionDestroy || len(ip.Diffs) != 0 {
+		t.Errorf("IP drift = %+v, want deleted without diffs", ip)
+	}
+
+	worker := r.Resources[1]
+	if worker.Action != ActionUpdate {
+		t.Errorf("worker action = %s, want %s", worker.Action, ActionUpdate)
+	}
+
+	want := []AttributeDiff{
+		{Path: "tags[3]", Before: "null", After: `"edited-in-console"`},
+		{Path: "type", Before: `"DEV1-M"`, After: `"DEV1-L"`},
+		{Path: "user_data", Before: "(sensitive)", After: "(sensitive value changed)"},
+	}
+	if !slices.Equal(worker.Diffs, want) {
+		t.Errorf("worker diffs = %+v, want %+v (user_data is sensitive: changed, not shown)", worker.Diffs, want)
+	}
+}
+
+func TestDriftNone(t *testing.T) {
+	t.Parallel()
+
+	for _, p := range []*tfjson.Plan{nil, {}} {
+		if r := Drift(p); len(r.Resources) != 0 || r.Resources == nil {
+			t.Errorf("Drift(%v) = %+v, want an empty list", p, r)
+		}
+	}
+}
+
+func TestAttributeDiffs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		change *tfjson.Change
+		want   []AttributeDiff
+	}{
+		{
+			name: "nested attribute changed",
+			change: &tfjson.Change{
+				Before: map[string]any{"root_volume": []any{map[string]any{"size_in_gb": float64(20)}}},
+				After:  map[string]any{"root_volume": []any{map[string]any{"size_in_gb": float64(40)}}},
+			},
+			want: []AttributeDiff{{Path: "root_volume[0].size_in_gb", Before: "20", After: "40"}},
+		},
+		{
+			name: "attribute removed",
+			change: &tfjson.Change{
+				Before: map[string]any{"name": "a", "ipv6": true},
+				After:  map[string]any{"name": "a"},
+			},
+			want: []AttributeDiff{{Path: "ipv6", Before: "true", After: "null"}},
+		},
+		{
+			name: "sensitive attribute changed",
+			change: &tfjson.Change{
+				Before:          map[string]any{"password": "old", "token": "same"},
+				After:           map[string]any{"password": "new", "token": "same", "name": "b"},
+				BeforeSensitive: map[string]any{"password": true, "token": true},
+				AfterSensitive:  map[string]any{"password": true, "token": true},
+			},
+			want: []AttributeDiff{
+				{Path: "name", Before: "null", After: `"b"`},
+				{Path: "password", Before: "(sensitive)", After: "(sensitive value changed)"},
+			},
+		},
+		{
+			name: "attribute became sensitive",
+			change: &tfjson.Change{
+				Before:         map[string]any{"password": "old"},
+				After:          map[string]any{"password": "old"},
+				AfterSensitive: map[string]any{"password": true},
+			},
+			want: []AttributeDiff{{Path: "password", Before: `"old"`, After: "(sensitive)"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := attributeDiffs(tt.change); !slices.Equal(got, tt.want) {
+				t.Errorf("attributeDiffs() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,226 @@
+// Package tfplan summarizes Terraform plans (`terraform show -json <planfile>`):
+// changes grouped by resource type, with the destructive ones flagged.
+package tfplan
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+// =============================================================================
+// Types
+// =============================================================================
+
+// Action is the planned change of a resource.
+type Action string
+
+// Planned actions, in display order. No-op and read changes are not reported.
+const (
+	ActionCreate  Action = "create"
+	ActionUpdate  Action = "update"
+	ActionReplace Action = "replace"
+	ActionDestroy Action = "destroy"
+)
+
+// Actions lists the actions in display order.
+var Actions = []Action{ActionCreate, ActionUpdate, ActionReplace, ActionDestroy}
+
+// Change is a planned change of a resource.
+type Change struct {
+	Address string `json:"address"`
+	Type    string `json:"type"`
+	Action  Action `json:"action"`
+	// Destructive is set when a server or an IP is replaced or destroyed.
+	Destructive bool `json:"destructive"`
+	// Reason explains a replacement, e.g. "image forces replacement".
+	Reason string `json:"reason,omitempty"`
+}
+
+// Counts is the number of changes per action.
+type Counts struct {
+	Create  int `json:"create"`
+	Update  int `json:"update"`
+	Replace int `json:"replace"`
+	Destroy int `json:"destroy"`
+}
+
+// Get returns the count of an action.
+func (c Counts) Get(a Action) int {
+	switch a {
+	case ActionCreate:
+		return c.Create
+	case ActionUpdate:
+		return c.Update
+	case ActionReplace:
+		return c.Replace
+	case ActionDestroy:
+		return c.Destroy
+	}
+
+	return 0
+}
+
+// Total returns the number of changes.
+func (c Counts) Total() int {
+	return c.Create + c.Update + c.Replace + c.Destroy
+}
+
+func (c *Counts) add(a Action) {
+	switch a {
+	case ActionCreate:
+		c.Create++
+	case ActionUpdate:
+		c.Update++
+	case ActionReplace:
+		c.Replace++
+	case ActionDestroy:
+		c.Destroy++
+	}
+}
+
+// Group is the changes of a resource type.
+type Group struct {
+	Type    string   `json:"type"`
+	Counts  Counts   `json:"counts"`
+	Changes []Change `json:"changes"`
+}
+
+// Summary is a plan grouped by resource type (sorted by type).
+type Summary struct {
+	Groups      []Group `json:"groups"`
+	Totals      Counts  `json:"totals"`
+	Destructive int     `json:"destructive"`
+}
+
+// HasChanges reports whether the plan changes anything.
+func (s Summary) HasChanges() bool {
+	return s.Totals.Total() > 0
+}
+
+// =============================================================================
+// Summarize
+// =============================================================================
+
+// criticalTypes are the resources whose replacement or destruction loses the
+// cluster (servers) or changes its public addresses (IPs).
+var criticalTypes = []string{"scaleway_instance_server", "scaleway_instance_ip"}
+
+// Summarize groups the resource changes of p by type. A nil plan has no changes.
+func Summarize(p *tfjson.Plan) Summary {
+	s := Summary{Groups: []Group{}}
+	if p == nil {
+		return s
+	}
+
+	groups := map[string]*Group{}
+
+	for _, rc := range p.ResourceChanges {
+		action, ok := actionOf(rc)
+		if !ok {
+			continue
+		}
+
+		c := Change{
+			Address: rc.Address,
+			Type:    rc.Type,
+			Action:  action,
+			Destructive: (action == ActionReplace || action == ActionDestroy) &&
+				slices.Contains(criticalTypes, rc.Type),
+			Reason: reasonOf(rc, action),
+		}
+
+		g, ok := groups[rc.Type]
+		if !ok {
+			g = &Group{Type: rc.Type}
+			groups[rc.Type] = g
+		}
+
+		g.Changes = append(g.Changes, c)
+		g.Counts.add(action)
+		s.Totals.add(action)
+
+		if c.Destructive {
+			s.Destructive++
+		}
+	}
+
+	types := make([]string, 0, len(groups))
+	for t := range groups {
+		types = append(types, t)
+	}
+
+	slices.Sort(types)
+
+	for _, t := range types {
+		s.Groups = append(s.Groups, *groups[t])
+	}
+
+	return s
+}
+
+// actionOf maps the tfjson actions of a change; no-op and read are skipped.
+func actionOf(rc *tfjson.ResourceChange) (Action, bool) {
+	if rc.Change == nil {
+		return "", false
+	}
+
+	switch a := rc.Change.Actions; {
+	case a.Replace():
+		return ActionReplace, true
+	case a.Create():
+		return ActionCreate, true
+	case a.Update():
+		return ActionUpdate, true
+	case a.Delete():
+		return ActionDestroy, true
+	default:
+		return "", false
+	}
+}
+
+// reasonOf lists the attributes forcing a replacement.
+func reasonOf(rc *tfjson.ResourceChange, action Action) string {
+	paths := replacePaths(rc.Change.ReplacePaths)
+	if action != ActionReplace || len(paths) == 0 {
+		return ""
+	}
+
+	return strings.Join(paths, ", ") + " forces replacement"
+}
+
+// replacePaths formats the replace_paths of a change: [["image"], ["root_volume", 0, "size_in_gb"]]
+// becomes ["image", "root_volume[0].size_in_gb"].
+func replacePaths(raw []any) []string {
+	paths := make([]string, 0, len(raw))
+
+	for _, p := range raw {
+		steps, ok := p.([]any)
+		if !ok {
+			continue
+		}
+
+		var b strings.Builder
+
+		for _, step := range steps {
+			switch v := step.(type) {
+			case string:
+				if b.Len() > 0 {
+					b.WriteByte('.')
+				}
+
+				b.WriteString(v)
+			case float64:
+				fmt.Fprintf(&b, "[%d]", int(v))
+			}
+		}
+
+		if b.Len() > 0 {
+			paths = append(paths, b.String())
+		}
+	}
+
+	return paths
+}
@@ -0,0 +1,102 @@
+package tfplan
+
+import (
+	"slices"
+	"testing"
+
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/k8s-lab/get-cluster-info/clusterinfo/clusterinfotest"
+)
+
+// =============================================================================
+// Summarize tests
+// =============================================================================
+
+func TestSummarize(t *testing.T) {
+	t.Parallel()
+
+	plan, err := clusterinfotest.LoadPlanFixture(clusterinfotest.FixturePlanChanges)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := Summarize(plan)
+
+	if want := (Counts{Create: 2, Update: 1, Replace: 1, Destroy: 2}); s.Totals != want {
+		t.Errorf("Totals = %+v, want %+v", s.Totals, want)
+	}
+
+	types := make([]string, 0, len(s.Groups))
+	for _, g := range s.Groups {
+		types = append(types, g.Type)
+	}
+
+	wantTypes := []string{"scaleway_instance_ip", "scaleway_instance_security_group_rules", "scaleway_instance_server"}
+	if !slices.Equal(types, wantTypes) {
+		t.Errorf("groups = %v, want %v (no-op resources skipped)", types, wantTypes)
+	}
+
+	// Replaced control plane, destroyed worker and worker IP.
+	if s.Destructive != 3 {
+		t.Errorf("Destructive = %d, want 3", s.Destructive)
+	}
+
+	servers := s.Groups[2]
+	if want := (Counts{Create: 1, Replace: 1, Destroy: 1}); servers.Counts != want {
+		t.Errorf("server counts = %+v, want %+v", servers.Counts, want)
+	}
+
+	cp := servers.Changes[0]
+	if cp.Action != ActionReplace || !cp.Destructive || cp.Reason != "image forces replacement" {
+		t.Errorf("control plane change = %+v, want a destructive replacement forced by image", cp)
+	}
+
+	if rules := s.Groups[1].Changes[0]; rules.Action != ActionUpdate || rules.Destructive {
+		t.Errorf("rules change = %+v, want a non-destructive update", rules)
+	}
+}
+
+func TestSummarizeNoChanges(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		plan *tfjson.Plan
+	}{
+		{name: "nil plan", plan: nil},
+		{name: "empty plan", plan: &tfjson.Plan{}},
+		{
+			name: "no-op and read only",
+			plan: &tfjson.Plan{ResourceChanges: []*tfjson.ResourceChange{
+				{Address: "a.b", Type: "a", Change: &tfjson.Change{Actions: tfjson.Actions{tfjson.ActionNoop}}},
+				{Address: "data.c.d", Type: "c", Change: &tfjson.Change{Actions: tfjson.Actions{tfjson.ActionRead}}},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := Summarize(tt.plan)
+			if s.HasChanges() || len(s.Groups) != 0 {
+				t.Errorf("Summarize() = %+v, want no changes", s)
+			}
+		})
+	}
+}
+
+func TestReplacePaths(t *testing.T) {
+	t.Parallel()
+
+	raw := []any{
+		[]any{"image"},
+		[]any{"root_volume", float64(0), "size_in_gb"},
+		"garbage",
+	}
+
+	want := []string{"image", "root_volume[0].size_in_gb"}
+	if got := replacePaths(raw); !slices.Equal(got, want) {
+		t.Errorf("replacePaths() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,297 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/k8s-lab/get-cluster-info/clusterinfo"
+	"github.com/k8s-lab/get-cluster-info/i18n"
+	"github.com/k8s-lab/get-cluster-info/render"
+	"github.com/spf13/cobra"
+)
+
+// =============================================================================
+// TTL and reap subcommands
+// =============================================================================
+//
+// The lab lifetime is stored in the S3 backend next to the state key (see
+// clusterinfo.LifetimeStore). `ttl set` is run after `terraform apply` (make up),
+// the summary shows the time left, and `reap` destroys the lab once expired,
+// from cron or a scheduled CI job.
+
+// lifetimeTimeout bounds the best-effort lifetime read of the summary.
+const lifetimeTimeout = 10 * time.Second
+
+var reapDryRun bool
+
+var ttlCmd = &cobra.Command{
+	Use:   "ttl",
+	Short: "Manage the lab time-to-live",
+	Long: `Manage the lab time-to-live.
+
+The TTL is stored in the S3 backend, next to the state key (<key>.ttl.json),
+so every machine reading the state sees it. Once it has expired, the summary
+shows the lab as expired and "get-cluster-info reap" destroys it.
+
+Examples:
+  # Expire in 8 hours (or extend the lab by 8 hours from now)
+  get-cluster-info ttl set 8h
+
+  # Time left
+  get-cluster-info ttl show
+
+  # Keep the lab running
+  get-cluster-info ttl clear`,
+	Args: cobra.NoArgs,
+}
+
+var ttlSetCmd = &cobra.Command{
+	Use:   "set <duration>",
+	Short: "Set the lab TTL from now (e.g. 8h, 90m, 2d)",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runTTLSet,
+}
+
+var ttlShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the time left before the lab expires",
+	Args:  cobra.NoArgs,
+	RunE:  runTTLShow,
+}
+
+var ttlClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove the lab TTL",
+	Args:  cobra.NoArgs,
+	RunE:  runTTLClear,
+}
+
+var reapCmd = &cobra.Command{
+	Use:   "reap",
+	Short: "Destroy the lab if its TTL has expired",
+	Long: `Destroy the lab if its TTL has expired.
+
+Runs "terraform destroy" when the TTL set with "get-cluster-info ttl set"
+has expired, then removes the TTL. Does nothing (exit code 0) when no TTL
+is set or the lab has not expired yet, so it can run from cron or CI.
+
+Examples:
+  # Hourly cron job
+  get-cluster-info reap
+
+  # Only report what would be destroyed
+  get-cluster-info reap --dry-run`,
+	Args: cobra.NoArgs,
+	RunE: runReap,
+}
+
+func init() {
+	reapCmd.Flags().BoolVar(&reapDryRun, "dry-run", false, "Report whether the lab has expired without destroying it")
+
+	ttlCmd.AddCommand(ttlSetCmd, ttlShowCmd, ttlClearCmd)
+	rootCmd.AddCommand(ttlCmd, reapCmd)
+}
+
+func runTTLSet(_ *cobra.Command, args []string) error {
+	ttl, err := clusterinfo.ParseTTL(args[0])
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	store, err := openLifetimeStore()
+	if err != nil {
+		return err
+	}
+
+	prev, err := store.Lifetime(ctx)
+	if err != nil && !errors.Is(err, clusterinfo.ErrNoLifetime) {
+		return withClass(errBackend, err)
+	}
+
+	l := clusterinfo.NewLifetime(now(), ttl, os.Getenv("USER"), prev)
+	if err := store.SetLifetime(ctx, l); err != nil {
+		return withClass(errBackend, err)
+	}
+
+	if config.JSONOutput {
+		return printLifetime(&l)
+	}
+
+	logSuccess("Lab expires in %s (%s)", render.FormatDuration(ttl), l.ExpiresAt.Format(time.RFC3339))
+
+	return nil
+}
+
+func runTTLShow(_ *cobra.Command, _ []string) error {
+	store, err := openLifetimeStore()
+	if err != nil {
+		return err
+	}
+
+	l, err := store.Lifetime(context.Background())
+
+	switch {
+	case errors.Is(err, clusterinfo.ErrNoLifetime):
+		if config.JSONOutput {
+			return printLifetime(nil)
+		}
+
+		logInfo("No TTL set - the lab is never reaped")
+
+		return nil
+	case err != nil:
+		return withClass(errBackend, err)
+	case config.JSONOutput:
+		return printLifetime(l)
+	case l.Expired(now()):
+		logWarning("Lab expired %s ago - run %s", render.FormatDuration(-l.Remaining(now())),
+			render.CmdStyle.Render("get-cluster-info reap"))
+	default:
+		logInfo("Lab expires in %s (%s)", render.FormatDuration(l.Remaining(now())),
+			l.ExpiresAt.Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+func runTTLClear(_ *cobra.Command, _ []string) error {
+	store, err := openLifetimeStore()
+	if err != nil {
+		return err
+	}
+
+	if err := store.DeleteLifetime(context.Background()); err != nil {
+		return withClass(errBackend, err)
+	}
+
+	logSuccess("TTL removed - the lab is never reaped")
+
+	return nil
+}
+
+func runReap(_ *cobra.Command, _ []string) error {
+	ctx := context.Background()
+
+	store, err := openLifetimeStore()
+	if err != nil {
+		return err
+	}
+
+	l, err := store.Lifetime(ctx)
+	if errors.Is(err, clusterinfo.ErrNoLifetime) {
+		logInfo("No TTL set - nothing to reap")
+
+		return nil
+	}
+
+	if err != nil {
+		return withClass(errBackend, err)
+	}
+
+	if !l.Expired(now()) {
+		logInfo("Lab expires in %s - nothing to reap", render.FormatDuration(l.Remaining(now())))
+
+		return nil
+	}
+
+	expired := render.FormatDuration(-l.Remaining(now()))
+
+	if reapDryRun {
+		logWarning("Lab expired %s ago - would run terraform destroy (--dry-run)", expired)
+
+		return nil
+	}
+
+	src, err := setupEnvironment(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = src.Close() }()
+
+	logWarning("Lab expired %s ago - running terraform destroy...", expired)
+
+	if err := src.Client().Destroy(ctx); err != nil {
+		return withClass(errBackend, i18n.Errorf("terraform destroy failed: %w", err))
+	}
+
+	if err := store.DeleteLifetime(ctx); err != nil {
+		return withClass(errBackend, err)
+	}
+
+	logSuccess("Lab destroyed")
+
+	return nil
+}
+
+// openLifetimeStore returns the store of the lab TTL: the lifetimeStore test hook,
+// or the S3 backend of the terraform directory with the backend credentials.
+func openLifetimeStore() (clusterinfo.LifetimeStore, error) {
+	if lifetimeStore != nil {
+		return lifetimeStore, nil
+	}
+
+	if err := resolveDefaults(); err != nil {
+		return nil, err
+	}
+
+	backend, err := clusterinfo.LoadS3Backend(config.TerraformDir)
+	if err != nil {
+		return nil, err
+	}
+
+	creds, err := clusterinfo.FileCredentials{Path: config.CredentialsFile}.Credentials(context.Background())
+	if err != nil {
+		return nil, withClass(errCredentials, err)
+	}
+
+	store, err := clusterinfo.NewS3LifetimeStore(backend, *creds)
+	if err != nil {
+		return nil, withClass(errBackend, err)
+	}
+
+	return store, nil
+}
+
+// readLifetime returns the lab TTL for the summary, or nil when none is set.
+// Errors are only warnings: the TTL never prevents displaying the cluster.
+func readLifetime(ctx context.Context) *clusterinfo.Lifetime {
+	ctx, cancel := context.WithTimeout(ctx, lifetimeTimeout)
+	defer cancel()
+
+	store, err := openLifetimeStore()
+	if err == nil {
+		var l *clusterinfo.Lifetime
+
+		l, err = store.Lifetime(ctx)
+		if err == nil {
+			return l
+		}
+	}
+
+	// Warnings go to stdout: keep the --json output parseable.
+	if !errors.Is(err, clusterinfo.ErrNoLifetime) && !errors.Is(err, clusterinfo.ErrNoS3Backend) &&
+		!config.JSONOutput {
+		logWarning("Failed to read the lab TTL: %v", err)
+	}
+
+	return nil
+}
+
+// printLifetime prints l as JSON ("null" when no TTL is set).
+func printLifetime(l *clusterinfo.Lifetime) error {
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(stdout, string(data))
+
+	return err
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/k8s-lab/get-cluster-info/clusterinfo"
+	"github.com/k8s-lab/get-cluster-info/clusterinfo/clusterinfotest"
+)
+
+// =============================================================================
+// ttl and reap subcommand tests
+// =============================================================================
+
+// setupTTL is setupRun with a memory lifetime store, holding l if not nil.
+func setupTTL(t *testing.T, l *clusterinfo.Lifetime) (*clusterinfotest.FakeTerraform,
+	*clusterinfotest.MemoryLifetimeStore, func() []byte,
+) {
+	t.Helper()
+
+	out, fake := setupRun(t, clusterinfotest.FixtureDeployed)
+	store := &clusterinfotest.MemoryLifetimeStore{Value: l}
+	lifetimeStore = store
+
+	oldDryRun := reapDryRun
+	t.Cleanup(func() { reapDryRun = oldDryRun })
+
+	reapDryRun = false
+
+	return fake, store, out.Bytes
+}
+
+func TestRunTTL(t *testing.T) {
+	// Not parallel - modifies global config
+
+	t.Run("set then extend keeps the creation time", func(t *testing.T) {
+		_, store, _ := setupTTL(t, nil)
+		t.Setenv("USER", "alice")
+
+		must(t, runTTLSet(nil, []string{"8h"}))
+
+		if want := testNow.Add(8 * time.Hour); store.Value == nil || !store.Value.ExpiresAt.Equal(want) ||
+			store.Value.SetBy != "alice" {
+			t.Fatalf("lifetime = %+v, want expiry %v set by alice", store.Value, want)
+		}
+
+		now = func() time.Time { return testNow.Add(6 * time.Hour) }
+		must(t, runTTLSet(nil, []string{"1d"}))
+
+		if !store.Value.CreatedAt.Equal(testNow) || !store.Value.ExpiresAt.Equal(testNow.Add(30*time.Hour)) {
+			t.Errorf("extended lifetime = %+v, want created %v", store.Value, testNow)
+		}
+	})
+
+	t.Run("invalid duration", func(t *testing.T) {
+		_, store, _ := setupTTL(t, nil)
+
+		if err := runTTLSet(nil, []string{"soon"}); !errors.Is(err, clusterinfo.ErrInvalidTTL) {
+			t.Errorf("runTTLSet() error = %v, want %v", err, clusterinfo.ErrInvalidTTL)
+		}
+
+		if store.Value != nil {
+			t.Errorf("lifetime = %+v, want none", store.Value)
+		}
+	})
+
+	t.Run("show JSON", func(t *testing.T) {
+		l := clusterinfo.NewLifetime(testNow, 8*time.Hour, "alice", nil)
+		_, _, out := setupTTL(t, &l)
+		config.JSONOutput = true
+
+		must(t, runTTLShow(nil, nil))
+
+		var got clusterinfo.Lifetime
+		if err := json.Unmarshal(out(), &got); err != nil || got != l {
+			t.Errorf("ttl show --json = %s (%v), want %+v", out(), err, l)
+		}
+	})
+
+	t.Run("show JSON without TTL", func(t *testing.T) {
+		_, _, out := setupTTL(t, nil)
+		config.JSONOutput = true
+
+		must(t, runTTLShow(nil, nil))
+
+		if got := string(out()); got != "null\n" {
+			t.Errorf("ttl show --json = %q, want null", got)
+		}
+	})
+
+	t.Run("clear", func(t *testing.T) {
+		l := clusterinfo.NewLifetime(testNow, 8*time.Hour, "alice", nil)
+		_, store, _ := setupTTL(t, &l)
+
+		must(t, runTTLClear(nil, nil))
+
+		if store.Value != nil {
+			t.Errorf("lifetime = %+v, want none", store.Value)
+		}
+	})
+
+	t.Run("store error", func(t *testing.T) {
+		_, store, _ := setupTTL(t, nil)
+		store.Err = errors.New("access denied")
+
+		if err := runTTLShow(nil, nil); !errors.Is(err, store.Err) {
+			t.Errorf("runTTLShow() error = %v, want %v", err, store.Err)
+		}
+	})
+}
+
+func TestRunReap(t *testing.T) {
+	// Not parallel - modifies global config
+
+	errBoom := errors.New("boom")
+
+	tests := []struct {
+		name       string
+		ttl        time.Duration // remaining at testNow, 0 for no TTL
+		dryRun     bool
+		destroyErr error
+		wantCalls  []string
+		wantKept   bool
+	}{
+		{name: "no TTL"},
+		{name: "not expired", ttl: time.Hour, wantKept: true},
+		{name: "expired, dry run", ttl: -time.Hour, dryRun: true, wantKept: true},
+		{name: "expired", ttl: -time.Hour, wantCalls: []string{"init", "destroy"}},
+		{
+			name:       "destroy failure keeps the TTL",
+			ttl:        -time.Hour,
+			destroyErr: errBoom,
+			wantCalls:  []string{"init", "destroy"},
+			wantKept:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var l *clusterinfo.Lifetime
+			if tt.ttl != 0 {
+				v := clusterinfo.NewLifetime(testNow.Add(-8*time.Hour), 8*time.Hour+tt.ttl, "alice", nil)
+				l = &v
+			}
+
+			fake, store, _ := setupTTL(t, l)
+			fake.DestroyErr = tt.destroyErr
+			reapDryRun = tt.dryRun
+
+			if err := runReap(nil, nil); !errors.Is(err, tt.destroyErr) {
+				t.Fatalf("runReap() error = %v, want %v", err, tt.destroyErr)
+			}
+
+			if !slices.Equal(fake.Calls(), tt.wantCalls) {
+				t.Errorf("calls = %v, want %v", fake.Calls(), tt.wantCalls)
+			}
+
+			if kept := store.Value != nil; kept != tt.wantKept {
+				t.Errorf("TTL kept = %v, want %v", kept, tt.wantKept)
+			}
+		})
+	}
+}
+
+func TestReadLifetime(t *testing.T) {
+	// Not parallel - modifies global config
+
+	_, store, out := setupTTL(t, nil)
+	config.Quiet = false
+
+	if l := readLifetime(context.Background()); l != nil {
+		t.Errorf("readLifetime() = %+v, want nil without TTL", l)
+	}
+
+	if len(out()) != 0 {
+		t.Errorf("no TTL should not warn, got %q", out())
+	}
+
+	store.Err = errors.New("access denied")
+
+	if l := readLifetime(context.Background()); l != nil {
+		t.Errorf("readLifetime() = %+v, want nil on error", l)
+	}
+
+	if len(out()) == 0 {
+		t.Error("store error should warn")
+	}
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/charmbracelet/x/term"
+	"github.com/k8s-lab/get-cluster-info/clusterinfo"
+	"github.com/k8s-lab/get-cluster-info/i18n"
+	"github.com/spf13/cobra"
+)
+
+// =============================================================================
+// --watch
+// =============================================================================
+//
+// Polls the state while `terraform apply` runs in another terminal. Each tick
+// reads the state version (S3 ETag or local serial, see clusterinfo.StateVersioner)
+// and only fetches the outputs again when it changed. The summary is
+// re-rendered (or a JSON line emitted with --json) only when the cluster
+// information itself changed, then the --on-change hook runs.
+
+// defaultWatchInterval is used by --watch without a value.
+const defaultWatchInterval = 5 * time.Second
+
+// clearScreen moves the cursor home and clears the terminal.
+const clearScreen = "\x1b[H\x1b[2J"
+
+// stateVersioner replaces the state version lookup when set (test hook, see main.go).
+var stateVersioner clusterinfo.StateVersioner
+
+// watcher holds what was last displayed.
+type watcher struct {
+	src     clusterinfo.StateSource
+	version *string // nil: outputs never fetched successfully
+	info    []byte  // JSON of the last displayed cluster info
+	lastErr string  // last reported error, not repeated on every tick
+}
+
+// checkWatchInterval rejects a --watch interval that is not positive: 0 is
+// only the default, not a value the user may pass.
+func checkWatchInterval(cmd *cobra.Command) error {
+	if config.Watch < 0 || config.Watch == 0 && cmd != nil && cmd.Flags().Changed("watch") {
+		return withClass(errUsage, i18n.Errorf("invalid --watch %s: the interval must be positive", config.Watch))
+	}
+
+	return nil
+}
+
+// watch re-renders the cluster information every config.Watch until ctx is done.
+func watch(ctx context.Context, src clusterinfo.StateSource) error {
+	versioner, err := openStateVersioner()
+	if err != nil {
+		logError("Cannot read the state version (%v): fetching the outputs on every tick", err)
+	}
+
+	w := &watcher{src: src}
+	ticker := time.NewTicker(config.Watch)
+
+	defer ticker.Stop()
+
+	for {
+		w.tick(ctx, versioner)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// tick fetches the outputs if the state version changed, and displays them if the cluster information changed.
+func (w *watcher) tick(ctx context.Context, versioner clusterinfo.StateVersioner) {
+	version := ""
+
+	if versioner != nil {
+		v, err := versioner.StateVersion(ctx)
+		if err != nil {
+			// Fetch the outputs anyway: they are the source of truth.
+			w.report(err)
+		} else if w.version != nil && v == *w.version {
+			return
+		}
+
+		version = v
+	}
+
+	if err := w.refresh(ctx); err != nil {
+		w.report(err)
+
+		return
+	}
+
+	w.version = &version
+	w.lastErr = ""
+}
+
+// refresh loads the cluster information and displays it when it changed.
+func (w *watcher) refresh(ctx context.Context) error {
+	src := clusterinfo.NewCachedSource(timedSource{w.src})
+
+	info, err := loadClusterInfo(ctx, src)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(info)
+	if err != nil {
+		return i18n.Errorf("failed to encode the cluster info: %w", err)
+	}
+
+	if bytes.Equal(data, w.info) {
+		return nil
+	}
+
+	w.info = data
+
+	if !config.NoSaveKey {
+		if err := saveSSHKey(ctx, src); err != nil {
+			logWarning("Failed to save SSH key: %v", err)
+		}
+	}
+
+	info.Lifetime = readLifetime(ctx)
+
+	if err := displayWatched(info); err != nil {
+		return err
+	}
+
+	if config.OnChange != "" {
+		if err := runOnChange(ctx, info); err != nil {
+			logError("--on-change: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// report prints err on stderr, unless it was the last reported one.
+func (w *watcher) report(err error) {
+	if err.Error() == w.lastErr {
+		return
+	}
+
+	w.lastErr = err.Error()
+	logError("%v", err)
+}
+
+// displayWatched prints info as a JSON line (--json) or as the summary, on a cleared terminal.
+func displayWatched(info *clusterinfo.ClusterInfo) error {
+	if config.JSONOutput {
+		data, err := json.Marshal(info)
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintln(stdout, string(data))
+
+		return err
+	}
+
+	if f, ok := stdout.(*os.File); ok && term.IsTerminal(f.Fd()) {
+		fmt.Fprint(stdout, clearScreen)
+	}
+
+	if err := summaryRenderer().Render(stdout, info); err != nil {
+		return err
+	}
+
+	logInfo("Updated at %s, watching every %s (Ctrl+C to stop)", now().Format(time.TimeOnly), config.Watch)
+
+	return nil
+}
+
+// runOnChange runs the --on-change command with sh, the cluster info JSON on stdin.
+// Its output goes to stderr to keep the --json lines parseable.
+func runOnChange(ctx context.Context, info *clusterinfo.ClusterInfo) error {
+	data, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", config.OnChange)
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stdout = stderr
+	cmd.Stderr = stderr
+
+	if err := cmd.Run(); err != nil {
+		return i18n.Errorf("failed to run %q: %w", config.OnChange, err)
+	}
+
+	return nil
+}
+
+func openStateVersioner() (clusterinfo.StateVersioner, error) {
+	if stateVersioner != nil {
+		return stateVersioner, nil
+	}
+
+	creds, err := clusterinfo.FileCredentials{Path: config.CredentialsFile}.Credentials(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return clusterinfo.NewStateVersioner(config.TerraformDir, *creds)
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/k8s-lab/get-cluster-info/clusterinfo"
+	"github.com/k8s-lab/get-cluster-info/clusterinfo/clusterinfotest"
+)
+
+// =============================================================================
+// --watch tests
+// =============================================================================
+
+// versionFunc is a clusterinfo.StateVersioner calling a function.
+type versionFunc func(ctx context.Context) (string, error)
+
+func (f versionFunc) StateVersion(ctx context.Context) (string, error) { return f(ctx) }
+
+// tickStep is what the state looks like at one tick of the watch loop.
+type tickStep struct {
+	version string
+	fixture string // state of the fake from this tick on (empty: unchanged)
+}
+
+// runWatch runs the watch loop over steps, one step per tick, and returns stdout and stderr.
+// setup adjusts the config (may be nil).
+func runWatch(t *testing.T, steps []tickStep, setup func()) (string, string, *clusterinfotest.FakeTerraform) {
+	t.Helper()
+
+	out, fake := setupRun(t, steps[0].fixture)
+	errOut := &bytes.Buffer{}
+	stderr = errOut
+	config.NoSaveKey = true
+	config.Watch = time.Millisecond
+
+	if setup != nil {
+		setup()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tick := 0
+	oldVersioner := stateVersioner
+	stateVersioner = versionFunc(func(context.Context) (string, error) {
+		// The ticker may win the race against the cancellation: the last step repeats.
+		step := steps[min(tick, len(steps)-1)]
+		if step.fixture != "" {
+			fake.State = clusterinfotest.MustFixture(t, step.fixture).State
+		}
+
+		tick++
+		if tick == len(steps) {
+			cancel()
+		}
+
+		return step.version, nil
+	})
+
+	t.Cleanup(func() { stateVersioner = oldVersioner })
+
+	src, err := setupEnvironment(ctx)
+	if err != nil {
+		t.Fatalf("setupEnvironment() error = %v", err)
+	}
+
+	defer func() { _ = src.Close() }()
+
+	if err := watch(ctx, src); err != nil {
+		t.Fatalf("watch() error = %v", err)
+	}
+
+	return out.String(), errOut.String(), fake
+}
+
+func countCalls(calls []string, call string) int {
+	n := 0
+
+	for _, c := range calls {
+		if c == call {
+			n++
+		}
+	}
+
+	return n
+}
+
+func TestWatchJSONLines(t *testing.T) {
+	// Not parallel - modifies global config
+
+	hookOut := filepath.Join(t.TempDir(), "hook.json")
+
+	out, errOut, fake := runWatch(t, []tickStep{
+		{version: "", fixture: clusterinfotest.FixtureNotDeployed}, // nothing applied yet
+		{version: ""}, // same version, but the first fetch failed: fetched again
+		{version: "a", fixture: clusterinfotest.FixtureDeployed},
+		{version: "a"}, // unchanged: outputs not fetched
+		{version: "b"}, // state rewritten, same outputs: nothing printed
+	}, func() {
+		config.JSONOutput = true
+		config.OnChange = "cat >> " + hookOut
+	})
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("stdout has %d lines, want a single JSON line:\n%s", len(lines), out)
+	}
+
+	var info clusterinfo.ClusterInfo
+	if err := json.Unmarshal([]byte(lines[0]), &info); err != nil {
+		t.Fatalf("stdout is not a JSON line: %v\n%s", err, out)
+	}
+
+	if info.ControlPlane.PublicIP != "203.0.113.10" {
+		t.Errorf("control plane IP = %q, want 203.0.113.10", info.ControlPlane.PublicIP)
+	}
+
+	if got := strings.Count(errOut, clusterinfo.ErrNotDeployed.Error()); got != 1 {
+		t.Errorf("stderr reports the fetch error %d times, want once:\n%s", got, errOut)
+	}
+
+	if got := countCalls(fake.Calls(), "output"); got != 4 {
+		t.Errorf("terraform output called %d times, want 4 (calls: %v)", got, fake.Calls())
+	}
+
+	hook, err := os.ReadFile(hookOut)
+	if err != nil {
+		t.Fatalf("--on-change did not run: %v", err)
+	}
+
+	if strings.TrimSpace(string(hook)) != lines[0] {
+		t.Errorf("--on-change stdin = %s, want the JSON line %s", hook, lines[0])
+	}
+}
+
+func TestWatchSummary(t *testing.T) {
+	// Not parallel - modifies global config
+
+	out, _, _ := runWatch(t, []tickStep{
+		{version: "1", fixture: clusterinfotest.FixtureDeployed},
+		{version: "2", fixture: clusterinfotest.FixtureHalfApplied},
+	}, nil)
+
+	// The half-applied state fails validation: the summary is not re-rendered.
+	if got := strings.Count(out, "ssh -i "); got != 2 {
+		t.Errorf("summary rendered %d times, want once (2 SSH commands):\n%s", got, out)
+	}
+
+	if strings.Contains(out, clearScreen) {
+		t.Error("clearScreen written to a non-terminal")
+	}
+}
+
+func TestWatchFallsBackWithoutVersion(t *testing.T) {
+	// Not parallel - modifies global config
+
+	_, fake := setupRun(t, clusterinfotest.FixtureDeployed)
+	config.NoSaveKey = true
+
+	ctx := context.Background()
+
+	src, err := setupEnvironment(ctx)
+	if err != nil {
+		t.Fatalf("setupEnvironment() error = %v", err)
+	}
+
+	defer func() { _ = src.Close() }()
+
+	// Without a versioner every tick fetches the outputs.
+	w := &watcher{src: src}
+	for range 3 {
+		w.tick(ctx, nil)
+	}
+
+	if calls := fake.Calls(); countCalls(calls, "output") != 3 || !slices.Contains(calls, "init") {
+		t.Errorf("calls = %v, want init then 3 outputs", calls)
+	}
+}
+
+func TestWatchRejectsNonPositiveInterval(t *testing.T) {
+	// Not parallel - modifies global config
+
+	for _, value := range []string{"0s", "-5s"} {
+		t.Run(value, func(t *testing.T) {
+			setupRun(t, clusterinfotest.FixtureDeployed)
+
+			watchFlag := rootCmd.Flags().Lookup("watch")
+			t.Cleanup(func() { watchFlag.Changed = false })
+
+			must(t, rootCmd.Flags().Set("watch", value))
+
+			err := run(rootCmd, nil)
+			if exitCode(err) != exitUsage || !strings.Contains(err.Error(), "--watch") {
+				t.Errorf("run() error = %v (exit %d), want an invalid --watch with exit %d", err, exitCode(err), exitUsage)
+			}
+		})
+	}
+}
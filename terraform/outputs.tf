@@ -33,6 +33,19 @@ output "worker_private_ip" {
   value       = local.worker_private_ip
 }
 
+# Tous les nodes de local.nodes, y compris les workers ajoutés ensuite
+# (lu par get-cluster-info, notamment "node join worker-2")
+output "nodes" {
+  description = "Rôle et IPs de chaque node, par nom"
+  value = {
+    for name, node in local.nodes : name => {
+      role       = node.role
+      public_ip  = scaleway_instance_ip.nodes_ips[name].address
+      private_ip = node.private_ip
+    }
+  }
+}
+
 # -----------------------------------------------------------------------------
 # CLÉ SSH (récupérable via: terraform output -raw ssh_private_key)
 # -----------------------------------------------------------------------------